@@ -33,6 +33,38 @@ const (
 
 type Region string
 
+// FencingProvider which will be either NetworkFence, Webhook or NodeCordon
+// +kubebuilder:validation:Enum=NetworkFence;Webhook;NodeCordon
+type FencingProvider string
+
+const (
+	// FencingProviderNetworkFence fences the cluster off its storage with a
+	// csi-addons NetworkFence resource created on a peer cluster
+	FencingProviderNetworkFence = FencingProvider("NetworkFence")
+
+	// FencingProviderWebhook requests an out-of-band fencing endpoint, such as
+	// a STONITH or IPMI gateway or a storage array API, to fence the cluster
+	FencingProviderWebhook = FencingProvider("Webhook")
+
+	// FencingProviderNodeCordon cordons and taints the nodes of the cluster,
+	// except its control plane and infrastructure nodes, so that no workload
+	// is scheduled on them, and evicts the pods of its protected applications.
+	// As the cluster fences itself, it must be reachable.
+	FencingProviderNodeCordon = FencingProvider("NodeCordon")
+)
+
+// FencingWebhook is the endpoint that the Webhook fencing provider requests
+type FencingWebhook struct {
+	// URL that fence and unfence requests are POSTed to
+	URL string `json:"url"`
+
+	// SecretName, if specified, is the name of a secret in the hub operator's
+	// namespace whose "token" key is sent as a bearer token, and whose
+	// "ca.crt" key, if any, verifies the certificate of the endpoint
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
 // DRClusterSpec defines the desired state of DRCluster
 type DRClusterSpec struct {
 	// CIDRs is a list of CIDR strings. An admin can use this field to indicate
@@ -41,9 +73,29 @@ type DRClusterSpec struct {
 	// operation for sync/Metro DR.
 	CIDRs []string `json:"cidrs,omitempty"`
 
+	// UseDiscoveredCIDRs, when set, adds the node CIDRs discovered on this
+	// managed cluster to CIDRs when the cluster is fenced
+	// +optional
+	UseDiscoveredCIDRs bool `json:"useDiscoveredCIDRs,omitempty"`
+
 	// ClusterFence is a string that determines the desired fencing state of the cluster.
 	ClusterFence ClusterFenceState `json:"clusterFence,omitempty"`
 
+	// FencingProvider selects how the cluster is fenced and unfenced when
+	// ClusterFence is Fenced or Unfenced. Defaults to NetworkFence
+	// +optional
+	FencingProvider FencingProvider `json:"fencingProvider,omitempty"`
+
+	// FencingWebhook is the endpoint requested by the Webhook fencing provider
+	// +optional
+	FencingWebhook *FencingWebhook `json:"fencingWebhook,omitempty"`
+
+	// RequireFenceConfirmation, when set, holds off fencing the cluster until
+	// the fence-confirmation annotation confirms the ClusterFence of the
+	// current generation, so that accidental ClusterFence edits do not fence it
+	// +optional
+	RequireFenceConfirmation bool `json:"requireFenceConfirmation,omitempty"`
+
 	// Region of a managed cluster determines it DR group.
 	// All managed clusters in a region are considered to be in a sync group.
 	Region Region `json:"region,omitempty"`
@@ -69,6 +121,10 @@ const (
 	// Fencing CR to fence off this cluster
 	// has been created
 	DRClusterConditionTypeFenced = "Fenced"
+
+	// Some node CIDRs discovered on this cluster
+	// are not within the spec CIDRs
+	DRClusterConditionTypeCIDRsOutOfDate = "CIDRsOutOfDate"
 )
 
 type DRClusterPhase string
@@ -105,10 +161,91 @@ const (
 	Unfenced = DRClusterPhase("Unfenced")
 )
 
+// StorageFencing describes how the storage of a managed cluster fences off
+// other clusters, as discovered by its dr-cluster operator
+type StorageFencing struct {
+	// Driver is the name of the CSI driver that fences
+	Driver string `json:"driver"`
+
+	// SecretName is the name of the secret the driver fences with
+	SecretName string `json:"secretName"`
+
+	// SecretNamespace is the namespace of the secret the driver fences with
+	SecretNamespace string `json:"secretNamespace"`
+
+	// ClusterID identifies the storage cluster to the driver
+	ClusterID string `json:"clusterID"`
+}
+
+// FenceOperation records a fence or unfence operation performed by Ramen
+type FenceOperation struct {
+	// State is the state the cluster is fenced or unfenced to
+	State ClusterFenceState `json:"state"`
+
+	// Provider is the fencing provider that performed the operation
+	Provider FencingProvider `json:"provider"`
+
+	// PeerCluster, if any, is the cluster the operation was performed on
+	// +optional
+	PeerCluster string `json:"peerCluster,omitempty"`
+
+	// FieldManager is the field manager that last set the ClusterFence
+	// requesting the operation. It names the client, such as kubectl or a
+	// controller, rather than the user that requested it.
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
+
+	// StartTime is when the operation started
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime, if the operation succeeded, is when it did
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Result is InProgress, Succeeded, or else the failure of the operation,
+	// as reported by its provider
+	Result string `json:"result"`
+
+	// Message, if any, details the result
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // DRClusterStatus defines the observed state of DRCluster
 type DRClusterStatus struct {
 	Phase      DRClusterPhase     `json:"phase,omitempty"`
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// FenceHistory records the latest fence and unfence operations performed
+	// on the cluster, the most recent last
+	// +optional
+	FenceHistory []FenceOperation `json:"fenceHistory,omitempty"`
+
+	// StorageFencing, if the managed cluster reports a single candidate,
+	// provides the storage details of the NetworkFence resources created on it
+	// to fence off its peers
+	// +optional
+	StorageFencing *StorageFencing `json:"storageFencing,omitempty"`
+
+	// StorageFencingCandidates, if the managed cluster reports more than one,
+	// lists the storage details of each CSI driver that may fence, as not
+	// every driver supports NetworkFence resources. StorageFencing is not set
+	// then: the storage driver annotation of a fenced peer's DRCluster chooses
+	// one of them.
+	// +optional
+	StorageFencingCandidates []StorageFencing `json:"storageFencingCandidates,omitempty"`
+
+	// NodeCIDRs, if reported by the managed cluster, are the CIDRs of the
+	// InternalIP addresses of its nodes
+	// +optional
+	NodeCIDRs []string `json:"nodeCIDRs,omitempty"`
+
+	// FencedCIDRs are the CIDRs the cluster is fenced off with, set when its
+	// fencing starts and kept until it is unfenced, so that the node CIDRs
+	// its managed cluster reports meanwhile change neither its fence nor its
+	// unfence
+	// +optional
+	FencedCIDRs []string `json:"fencedCIDRs,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DRFailoverPlanSpec defines the DRPCs a DRFailoverPlan fails over, and to
+// which cluster. The DRPCs are selected once, when the plan starts.
+type DRFailoverPlanSpec struct {
+	// FailoverCluster is the cluster the selected DRPCs are failed over to
+	FailoverCluster string `json:"failoverCluster"`
+
+	// DRPCSelector selects the DRPCs to fail over by their labels. An empty
+	// selector selects every DRPC of the namespaces
+	// +optional
+	DRPCSelector metav1.LabelSelector `json:"drpcSelector,omitempty"`
+
+	// Namespaces are the namespaces of the DRPCs to select. A plan without
+	// namespaces fails, lest it fail over the DRPCs of every namespace.
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+
+	// Time each DRPC of a wave has to fail over, from the start of the wave.
+	// A DRPC that did not fail over in time fails the plan, and the later
+	// waves are not started. Defaults to 1h.
+	// +optional
+	WaveTimeout *metav1.Duration `json:"waveTimeout,omitempty"`
+}
+
+// DRFailoverPlanPhase for keeping track of a DRFailoverPlan
+type DRFailoverPlanPhase string
+
+// These are the valid values for DRFailoverPlanPhase
+const (
+	// DRFailoverPlanInProgress, the waves of DRPCs are being failed over
+	DRFailoverPlanInProgress = DRFailoverPlanPhase("InProgress")
+
+	// DRFailoverPlanCompleted, every wave has been failed over or skipped
+	DRFailoverPlanCompleted = DRFailoverPlanPhase("Completed")
+
+	// DRFailoverPlanFailed, a DRPC of the current wave did not fail over in
+	// time, and the later waves were not started
+	DRFailoverPlanFailed = DRFailoverPlanPhase("Failed")
+)
+
+// DRFailoverPlanDRPCPhase for keeping track of a DRPC of a DRFailoverPlan
+type DRFailoverPlanDRPCPhase string
+
+// These are the valid values for DRFailoverPlanDRPCPhase
+const (
+	// DRFailoverPlanDRPCPending, the DRPC's wave has not started yet
+	DRFailoverPlanDRPCPending = DRFailoverPlanDRPCPhase("Pending")
+
+	// DRFailoverPlanDRPCFailingOver, the DRPC's failover was requested and
+	// its Available condition is not true yet
+	DRFailoverPlanDRPCFailingOver = DRFailoverPlanDRPCPhase("FailingOver")
+
+	// DRFailoverPlanDRPCFailedOver, the DRPC failed over to the plan's cluster
+	DRFailoverPlanDRPCFailedOver = DRFailoverPlanDRPCPhase("FailedOver")
+
+	// DRFailoverPlanDRPCSkipped, the DRPC was not failed over, as its message
+	// explains
+	DRFailoverPlanDRPCSkipped = DRFailoverPlanDRPCPhase("Skipped")
+
+	// DRFailoverPlanDRPCFailed, the DRPC did not fail over within the wave
+	// timeout, or its wave was not started as an earlier wave failed
+	DRFailoverPlanDRPCFailed = DRFailoverPlanDRPCPhase("Failed")
+)
+
+// DRFailoverPlanDRPCStatus is the outcome of the failover of a DRPC of a
+// DRFailoverPlan
+type DRFailoverPlanDRPCStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Priority is the DRPC's priority when the plan started
+	Priority int `json:"priority,omitempty"`
+
+	// Wave is the index of the wave the DRPC fails over in
+	Wave int `json:"wave"`
+
+	Phase   DRFailoverPlanDRPCPhase `json:"phase"`
+	Message string                  `json:"message,omitempty"`
+}
+
+// DRFailoverPlanStatus defines the observed state of DRFailoverPlan
+type DRFailoverPlanStatus struct {
+	Phase DRFailoverPlanPhase `json:"phase,omitempty"`
+
+	// Waves is the number of waves of the plan, one per distinct priority of
+	// its DRPCs, highest first
+	Waves int `json:"waves,omitempty"`
+
+	// CurrentWave is the index of the wave being failed over
+	CurrentWave int `json:"currentWave,omitempty"`
+
+	// WaveStartTime is when the current wave started
+	WaveStartTime *metav1.Time `json:"waveStartTime,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// DRPCs lists the outcome of each DRPC of the plan, in failover order
+	DRPCs []DRFailoverPlanDRPCStatus `json:"drpcs,omitempty"`
+
+	// Message explains why the plan failed to start, if it did
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+
+// DRFailoverPlan is the Schema for the drfailoverplans API. It fails over the
+// DRPCs it selects in waves of decreasing priority, starting each wave once
+// every DRPC of the previous one is available on the failover cluster.
+// It selects DRPCs only of the namespaces it lists. As creating a plan fails
+// over each DRPC it selects, regardless of its creator's access to their
+// namespaces, it is to be allowed only to cluster admins.
+type DRFailoverPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DRFailoverPlanSpec   `json:"spec,omitempty"`
+	Status DRFailoverPlanStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DRFailoverPlanList contains a list of DRFailoverPlan
+type DRFailoverPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DRFailoverPlan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DRFailoverPlan{}, &DRFailoverPlanList{})
+}
@@ -22,8 +22,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// DRAction which will be either a Failover or Relocate action
-// +kubebuilder:validation:Enum=Failover;Relocate
+// DRAction which will be either a Failover, Relocate or Failback action
+// +kubebuilder:validation:Enum=Failover;Relocate;Failback
 type DRAction string
 
 // These are the valid values for DRAction
@@ -34,6 +34,11 @@ const (
 	// Relocate, restore PVs to the designated TargetCluster.  PreferredCluster will change
 	// to be the TargetCluster.
 	ActionRelocate = DRAction("Relocate")
+
+	// Failback, relocate a failed over application back to the PreferredCluster
+	// once its old primary there has been demoted and resynced, unfencing the
+	// PreferredCluster first if UnfenceOnFailback is set
+	ActionFailback = DRAction("Failback")
 )
 
 // DRState for keeping track of the DR placement
@@ -75,6 +80,25 @@ const (
 const (
 	ConditionAvailable = "Available"
 	ConditionPeerReady = "PeerReady"
+
+	// ConditionDataLagging is True while the application data on the peer
+	// cluster is older than the allowed multiple of the scheduling interval.
+	// It is added once the data lags for the first time, or once its sync time
+	// is unknown, in which case it is Unknown.
+	ConditionDataLagging = "DataLagging"
+
+	// ConditionReadyForFailover and ConditionReadyForRelocate are True while
+	// every readiness check of the action passed. Otherwise they are False,
+	// with the name of the first failed check as their reason or, if none
+	// failed but some could not be evaluated, ReasonReadinessUnknown.
+	ConditionReadyForFailover = "ReadyForFailover"
+	ConditionReadyForRelocate = "ReadyForRelocate"
+
+	// ConditionDependenciesResolved is added to a DRPC with DependsOn once it
+	// checks its dependencies before placing its application. It is False if
+	// a dependency does not exist, or depends, directly or not, on the DRPC
+	// itself; neither is waited for, as either would hold the DRPC forever.
+	ConditionDependenciesResolved = "DependenciesResolved"
 )
 
 const (
@@ -82,6 +106,52 @@ const (
 	ReasonCleaning    = "Cleaning"
 	ReasonSuccess     = "Success"
 	ReasonNotStarted  = "NotStarted"
+	ReasonLagging     = "Lagging"
+	ReasonInSync      = "InSync"
+	ReasonReady       = "Ready"
+
+	// ReasonReadinessUnknown, no readiness check of an action failed, but
+	// some could not be evaluated
+	ReasonReadinessUnknown = "ReadinessUnknown"
+
+	ReasonSyncTimeUnknown    = "SyncTimeUnknown"
+	ReasonPrimaryUnavailable = "PrimaryUnavailable"
+
+	ReasonDependencyNotFound = "DependencyNotFound"
+	ReasonDependencyCycle    = "DependencyCycle"
+)
+
+// These are the names of the readiness checks
+const (
+	// ReadinessCheckTargetVRG, the peer cluster's VRG is viewable
+	ReadinessCheckTargetVRG = "TargetVRG"
+
+	// ReadinessCheckDataReady and ReadinessCheckDataProtected, the peer
+	// cluster's VRG condition of the same name is true for its generation
+	ReadinessCheckDataReady     = "DataReady"
+	ReadinessCheckDataProtected = "DataProtected"
+
+	// ReadinessCheckClusterDataProtected, the current cluster's VRG, which
+	// uploads the PV cluster data, reports its condition of the same name true
+	// for its generation
+	ReadinessCheckClusterDataProtected = "ClusterDataProtected"
+
+	// ReadinessCheckS3ProfileReachable, the S3 store of the peer cluster's
+	// profile can be listed
+	ReadinessCheckS3ProfileReachable = "S3ProfileReachable"
+
+	// ReadinessCheckFencing, for a failover, the current cluster is fenced if
+	// it is in the peer cluster's region and, for both actions, the peer
+	// cluster is not fenced
+	ReadinessCheckFencing = "Fencing"
+
+	// ReadinessCheckKubeObjectsCaptureAge, the primary VRG's kube objects
+	// capture to recover from is younger than twice its capture interval
+	ReadinessCheckKubeObjectsCaptureAge = "KubeObjectsCaptureAge"
+
+	// ReadinessCheckVolSyncLatestImage, every VolSync replication destination
+	// of the peer cluster's VRG has a latest image to restore from
+	ReadinessCheckVolSyncLatestImage = "VolSyncLatestImage"
 )
 
 type ProgressionStatus string
@@ -102,6 +172,9 @@ const (
 	ProgressionUpdatedPlRule        = ProgressionStatus("UpdatedPlRule")
 	ProgressionEnsuringVolSyncSetup = ProgressionStatus("EnsuringVolSyncSetup")
 	ProgressionSettingupVolsyncDest = ProgressionStatus("SettingUpVolSyncDest")
+	ProgressionUnfencingCluster     = ProgressionStatus("UnfencingCluster")
+	ProgressionWaitingForResync     = ProgressionStatus("WaitingForResync")
+	ProgressionWaitingForDeps       = ProgressionStatus("WaitingForDependencies")
 )
 
 // DRPlacementControlSpec defines the desired state of DRPlacementControl
@@ -124,10 +197,113 @@ type DRPlacementControlSpec struct {
 	// need DR protection. It will be passed in to the VRG when it is created
 	PVCSelector metav1.LabelSelector `json:"pvcSelector"`
 
-	// Action is either Failover or Relocate operation
+	// ProtectedNamespaces lists namespaces, besides the DRPC's own, whose PVCs
+	// selected by PVCSelector and whose kube objects are protected along with
+	// the DRPC namespace's, and fail over and relocate with them as one unit.
+	// It is passed in to the VRG when it is created
+	// +optional
+	ProtectedNamespaces []string `json:"protectedNamespaces,omitempty"`
+
+	// Action is either Failover, Relocate or Failback operation
 	Action DRAction `json:"action,omitempty"`
+
+	// UnfenceOnFailback, when set, unfences the DRCluster of the PreferredCluster
+	// as the first step of a Failback; otherwise a Failback waits for it to be
+	// unfenced
+	// +optional
+	UnfenceOnFailback bool `json:"unfenceOnFailback,omitempty"`
+
+	// Priority orders the failover of the DRPC among others by a DRFailoverPlan
+	// or an automatic failover: DRPCs of higher priority fail over first.
+	// Defaults to 0
+	// +optional
+	Priority int `json:"priority,omitempty"`
+
+	// DependsOn lists the DRPCs whose applications must be available on the
+	// cluster this DRPC deploys, fails over or relocates to, before its own
+	// application is placed there
+	// +optional
+	DependsOn []DRPCReference `json:"dependsOn,omitempty"`
+
+	// Drill, when set, rehearses a failover of the application to a peer cluster
+	// without disturbing the current primary. Removing it cleans up the drill.
+	// +optional
+	Drill *DrillSpec `json:"drill,omitempty"`
+
+	// ClusterDataRetention, when set, retains point-in-time generations of the
+	// PV cluster data of the application. It is passed in to the VRG, also
+	// when it is updated
+	// +optional
+	ClusterDataRetention *ClusterDataRetentionSpec `json:"clusterDataRetention,omitempty"`
+
+	// ClusterDataGeneration, when set, names the retained generation of PV
+	// cluster data to restore on the next failover or relocation, instead of
+	// the latest one. It is passed in to the VRG, also when it is updated
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]{8}T[0-9]{6}Z$`
+	ClusterDataGeneration string `json:"clusterDataGeneration,omitempty"`
+
+	// KubeObjectProtection, when set, protects the kube objects of the
+	// application, retaining its CaptureRetentionCount latest captures, and
+	// recovers them from the capture numbered RecoverFromCaptureNumber, if set,
+	// instead of the latest one. It is passed in to the VRG, also when it is
+	// updated
+	// +optional
+	KubeObjectProtection *KubeObjectProtectionSpec `json:"kubeObjectProtection,omitempty"`
+}
+
+// DRPCReference refers to a DRPC that another DRPC depends on
+type DRPCReference struct {
+	// Namespace of the DRPC. If not specified, then the depending DRPC's
+	// namespace is used
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the DRPC
+	Name string `json:"name"`
+}
+
+// DrillSpec defines where a failover drill brings up the application
+type DrillSpec struct {
+	// Cluster is the peer cluster the failover is rehearsed on. If not specified,
+	// then FailoverCluster is used
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// Namespace is the isolated namespace on Cluster that the PVs and kube objects
+	// of the application are restored to. If not specified, then the DRPC
+	// namespace name suffixed with "-drill" is used
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// VerifierImage is the image of the pod that verifies that the restored
+	// volumes are readable before the drill succeeds. If not specified, then a
+	// UBI minimal image is used
+	// +optional
+	VerifierImage string `json:"verifierImage,omitempty"`
 }
 
+// DrillPhase for keeping track of a failover drill
+type DrillPhase string
+
+// These are the valid values for DrillPhase
+const (
+	// DrillRunning, the drill VRG is being deployed and the application data is
+	// being restored on the drill cluster
+	DrillRunning = DrillPhase("Running")
+
+	// DrillSucceeded, the application data was restored to the drill namespace,
+	// and its volumes were mounted and read there
+	DrillSucceeded = DrillPhase("Succeeded")
+
+	// DrillFailed, the drill could not be started or the restore failed
+	DrillFailed = DrillPhase("Failed")
+
+	// DrillCleaningUp, the drill was removed from the spec and its resources
+	// are being deleted from the drill cluster
+	DrillCleaningUp = DrillPhase("CleaningUp")
+)
+
 // VRGResourceMeta represents the VRG resource.
 type VRGResourceMeta struct {
 	// Kind is the kind of the Kubernetes resource.
@@ -169,6 +345,71 @@ type DRPlacementControlStatus struct {
 	Conditions         []metav1.Condition      `json:"conditions,omitempty"`
 	ResourceConditions VRGConditions           `json:"resourceConditions,omitempty"`
 	LastUpdateTime     metav1.Time             `json:"lastUpdateTime"`
+	// lastGroupSyncTime is the time up to which the application data is
+	// replicated to the peer cluster, as reported by the primary VRG. The
+	// recovery point objective (RPO) is the time elapsed since then.
+	// +optional
+	LastGroupSyncTime *metav1.Time `json:"lastGroupSyncTime,omitempty"`
+	// +optional
+	Drill *DrillStatus `json:"drill,omitempty"`
+	// readiness reports whether the application can be failed over or
+	// relocated to the peer of its current cluster, and why not
+	// +optional
+	Readiness *ReadinessStatus `json:"readiness,omitempty"`
+}
+
+// ReadinessStatus is the result of the checks that predict whether a
+// failover, a relocate or a failback to TargetCluster would proceed
+type ReadinessStatus struct {
+	// TargetCluster is the peer of the current cluster that the checks are for
+	TargetCluster string `json:"targetCluster"`
+
+	// CheckTime is the time the checks' results last changed
+	// +nullable
+	CheckTime metav1.Time `json:"checkTime,omitempty"`
+
+	// +optional
+	Checks []ReadinessCheck `json:"checks,omitempty"`
+}
+
+// ReadinessCheck is the result of a readiness check
+type ReadinessCheck struct {
+	Name string `json:"name"`
+
+	// Actions the check applies to
+	Actions []DRAction `json:"actions"`
+
+	// Status is True if the check passed, False if it failed, and Unknown if
+	// it could not be evaluated, which leaves the actions not known to be ready
+	Status metav1.ConditionStatus `json:"status"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DrillStatus reports the progress and result of a failover drill
+type DrillStatus struct {
+	// Cluster the drill runs on
+	Cluster string `json:"cluster,omitempty"`
+
+	// Namespace the application is restored to on Cluster
+	Namespace string `json:"namespace,omitempty"`
+
+	Phase DrillPhase `json:"phase,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message is a human readable explanation of the phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ResourceConditions are the conditions of the drill VRG
+	// +optional
+	ResourceConditions VRGConditions `json:"resourceConditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -46,6 +46,34 @@ type DRPolicySpec struct {
 
 	// List of DRCluster resources that are governed by this policy
 	DRClusters []string `json:"drClusters,omitempty"`
+
+	// autoFailover opts the applications of the policy in to failover by the
+	// hub once their current cluster is unavailable
+	//+optional
+	AutoFailover *AutoFailoverSpec `json:"autoFailover,omitempty"`
+}
+
+// AutoFailoverSpec configures the automatic failover of the applications of a
+// DRPolicy to a surviving cluster of the policy
+type AutoFailoverSpec struct {
+	// Enabled turns automatic failover on. Defaults to false.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Time the ManagedCluster of an application's current cluster must be
+	// unavailable before the application is failed over. Defaults to 5m.
+	//+optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+
+	// Minimum time between the start of two automatic failovers of the
+	// policy's applications. Defaults to 30s.
+	//+optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+
+	// Maximum number of the policy's applications failing over at once,
+	// whether automatically or not. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	//+optional
+	MaxConcurrentFailovers int `json:"maxConcurrentFailovers,omitempty"`
 }
 
 // DRPolicyStatus defines the observed state of DRPolicy
@@ -53,10 +81,24 @@ type DRPolicySpec struct {
 // Important: Run "make" to regenerate code after modifying this file
 type DRPolicyStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// lastAutoFailoverTime is the time the latest automatic failover of the
+	// policy's applications was started
+	//+optional
+	LastAutoFailoverTime *metav1.Time `json:"lastAutoFailoverTime,omitempty"`
 }
 
 const (
 	DRPolicyValidated string = `Validated`
+
+	// DRPolicyProtectedApplicationsAdopted reports whether the applications
+	// protected by the policy are adopted by the hub, as requested by the
+	// policy's adopt-protected-applications annotation
+	DRPolicyProtectedApplicationsAdopted string = `ProtectedApplicationsAdopted`
+
+	// DRPolicyAutoFailoverBlocked reports whether automatic failovers of the
+	// policy's applications are blocked until an admin acts
+	DRPolicyAutoFailoverBlocked string = `AutoFailoverBlocked`
 )
 
 // +kubebuilder:object:root=true
@@ -25,6 +25,38 @@ type ProtectedVolumeReplicationGroupListSpec struct {
 	// ProfileName is the name of the S3 profile in the Ramen operator config map
 	// specifying the store to be queried
 	S3ProfileName string `json:"s3ProfileName"`
+
+	// RefreshInterval when set, is the interval at which the store is queried
+	// again. Otherwise, it is queried again only when the spec is updated or
+	// the status is cleared
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// NamespaceNames when set, limits the VRGs listed to those in these
+	// namespaces. Only the keys of these namespaces are listed in the store
+	// +optional
+	NamespaceNames []string `json:"namespaceNames,omitempty"`
+
+	// VRGNames when set, limits the VRGs listed to those with these names
+	// +optional
+	VRGNames []string `json:"vrgNames,omitempty"`
+
+	// LabelSelector when set, limits the VRGs listed to those whose labels
+	// match it
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Limit when set, is the maximum number of VRGs, in namespace and name
+	// order, downloaded from the store per query. The label selector applies
+	// to the downloaded VRGs, so fewer may be listed
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Limit int `json:"limit,omitempty"`
+
+	// Continue when set, to the continue value of the status of a prior query,
+	// resumes the listing after the VRGs downloaded by that query
+	// +optional
+	Continue string `json:"continue,omitempty"`
 }
 
 // ProtectedVolumeReplicationGroupListStatus defines the observed state of ProtectedVolumeReplicationGroupList
@@ -36,6 +68,16 @@ type ProtectedVolumeReplicationGroupListStatus struct {
 	// Items is a list of VolumeReplicationGroup objects represented in
 	// the specified store when it was last queried.
 	Items []VolumeReplicationGroup `json:"items,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the store was last
+	// queried for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Continue when set, indicates that the limit was reached before all the
+	// VRGs were downloaded. Set the spec's continue to it to list the next ones
+	// +optional
+	Continue string `json:"continue,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -37,6 +37,47 @@ const (
 	DRHubType ControllerType = "dr-hub"
 )
 
+// ObjectStoreType is the type of object store an S3 store profile refers to
+type ObjectStoreType string
+
+const (
+	// S3ObjectStoreType is an S3 compatible object store
+	S3ObjectStoreType ObjectStoreType = "s3"
+
+	// FileSystemObjectStoreType stores objects as files in a directory, such
+	// as the mount point of a PVC, given by the file:// URL of the profile's
+	// S3CompatibleEndpoint, in a subdirectory named after its S3Bucket
+	FileSystemObjectStoreType ObjectStoreType = "filesystem"
+
+	// HTTPObjectStoreType stores objects in a generic HTTP object store at
+	// the http(s):// URL of the profile's S3CompatibleEndpoint. Objects are
+	// PUT, GET and DELETEd at <endpoint>/<bucket>/<key>, and the keys with a
+	// given prefix are listed by a GET of <endpoint>/<bucket>/?prefix=<prefix>,
+	// which returns a JSON array of keys. The metadata of an object, such as
+	// its Ramen-Sha256 digest, is PUT with it and must be returned by its
+	// GET as HTTP headers of the same names. Requests are authenticated with
+	// basic auth using the access key id and secret access key of the
+	// profile's S3SecretRef, if set.
+	HTTPObjectStoreType ObjectStoreType = "http"
+)
+
+// KubeObjectsEngine is the engine that captures and recovers the kube objects
+// of VRGs with kube object protection
+type KubeObjectsEngine string
+
+const (
+	// VeleroKubeObjectsEngine captures and recovers kube objects with Velero
+	// backups and restores, and so requires Velero on each managed cluster.
+	// It supports s3 object stores only; s3 profiles of other types are
+	// rejected unless kube object protection is disabled.
+	VeleroKubeObjectsEngine KubeObjectsEngine = "velero"
+
+	// NativeKubeObjectsEngine captures kube objects by listing them with the
+	// API server and uploading them to the VRG's object stores directly, and
+	// recovers them by creating them in the order of the VRG's RecoverOrder
+	NativeKubeObjectsEngine KubeObjectsEngine = "native"
+)
+
 // When naming a S3 bucket, follow the bucket naming rules at:
 // https://docs.aws.amazon.com/AmazonS3/latest/userguide/bucketnamingrules.html
 // - Bucket names must be between 3 and 63 characters long.
@@ -58,6 +99,10 @@ type S3StoreProfile struct {
 	// Name of this S3 profile
 	S3ProfileName string `json:"s3ProfileName"`
 
+	// Type of the object store of this profile. Defaults to s3.
+	//+optional
+	Type ObjectStoreType `json:"type,omitempty"`
+
 	// Name of the S3 bucket to protect and recover PV related cluster-data of
 	// subscriptions protected by this DR policy.  This S3 bucket name is used
 	// across all DR policies that use this S3 profile. Objects deposited in
@@ -87,6 +132,45 @@ type S3StoreProfile struct {
 	S3SecretRef v1.SecretReference `json:"s3SecretRef"`
 	//+optional
 	VeleroNamespaceSecretName string `json:"veleroNamespaceSecretName,omitempty"`
+
+	// Client side encryption of the cluster data uploaded to the object store
+	// of this profile. Disabled unless a key secret is referenced.
+	//+optional
+	Encryption ObjectEncryption `json:"encryption,omitempty"`
+
+	// RejectUnverifiedObjects rejects objects stored without an HMAC, or
+	// without a digest if the profile's secret has no integrity key, as those
+	// uploaded by earlier versions of Ramen, so that cluster data cannot be
+	// injected into the object store. Otherwise they are accepted and
+	// uploaded again, signed. Set it once every object of the store has been
+	// signed. The HMAC is keyed from the RAMEN_OBJECT_INTEGRITY_KEY of the
+	// profile's secret, and objects are verified with it and with the keys
+	// prefixed RAMEN_OBJECT_INTEGRITY_KEY_, such as
+	// RAMEN_OBJECT_INTEGRITY_KEY_PREVIOUS, so that it can be rotated.
+	//+optional
+	RejectUnverifiedObjects bool `json:"rejectUnverifiedObjects,omitempty"`
+}
+
+// ObjectEncryption configures the envelope encryption of the objects uploaded
+// to an object store. Each object is encrypted with AES-GCM using a random data
+// key, which is in turn encrypted with a key from a secret. The ID of that key
+// is stored with each object, so that objects uploaded before a key rotation
+// can still be decrypted as long as their key remains in the secret.
+type ObjectEncryption struct {
+	// Reference to the secret whose data maps key IDs to AES keys of 16, 24
+	// or 32 bytes
+	KeySecretRef v1.SecretReference `json:"keySecretRef,omitempty"`
+
+	// ID of the key in the secret used to encrypt objects being uploaded. To
+	// rotate keys, add a new key to the secret and set its ID here.
+	KeyID string `json:"keyID,omitempty"`
+
+	// AllowUnencryptedObjects accepts objects that are not encrypted, as those
+	// uploaded before encryption was enabled, while migrating to encryption.
+	// Otherwise they are rejected, so that unencrypted cluster data cannot be
+	// injected into the object store in place of encrypted data.
+	//+optional
+	AllowUnencryptedObjects bool `json:"allowUnencryptedObjects,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -139,6 +223,9 @@ type RamenConfig struct {
 	VolSync struct {
 		// Disabled is used to disable VolSync usage in Ramen. Defaults to false.
 		Disabled bool `json:"disabled,omitempty"`
+		// Period after which the ssh keys VolSync replicates with are rotated.
+		// Defaults to 0, which never rotates them.
+		SecretRotationPeriod metav1.Duration `json:"secretRotationPeriod,omitempty"`
 	} `json:"volSync,omitempty"`
 
 	KubeObjectProtection struct {
@@ -146,7 +233,47 @@ type RamenConfig struct {
 		Disabled bool `json:"disabled,omitempty"`
 		// Velero namespace input
 		VeleroNamespaceName string `json:"veleroNamespaceName,omitempty"`
+		// Engine that captures and recovers kube objects. Defaults to velero.
+		Engine KubeObjectsEngine `json:"engine,omitempty"`
 	} `json:"kubeObjectProtection,omitempty"`
+
+	// Replication lag alerting configuration
+	DataLagging struct {
+		// Data of a protected application is flagged as lagging once the time
+		// since its last group sync exceeds this multiple of the scheduling
+		// interval of its DR policy. Defaults to 2.
+		SchedulingIntervalMultiple int `json:"schedulingIntervalMultiple,omitempty"`
+	} `json:"dataLagging,omitempty"`
+
+	// Hub objects backup and restore configuration
+	HubBackup struct {
+		// Disabled is used to disable the periodic backup of the hub's
+		// DRPolicies, DRClusters and DRPlacementControls to the object stores
+		// of the DRPolicies. Defaults to false.
+		Disabled bool `json:"disabled,omitempty"`
+		// Interval between backups. Defaults to 5 minutes.
+		Interval metav1.Duration `json:"interval,omitempty"`
+		// Name of the S3 profile whose store's backup is restored at startup,
+		// creating the hub objects that do not exist. The restore is done
+		// once, and recorded in the ramen-hub-backup-status config map of the
+		// hub operator's namespace; delete it to restore again.
+		RestoreS3ProfileName string `json:"restoreS3ProfileName,omitempty"`
+		// TakeOver has the hub take over the backups of other hubs without
+		// restoring them, deleting from them the objects the hub lacks. A hub
+		// otherwise only deletes objects from the backups it owns: those it
+		// started in an empty store, and those it took over by restoring.
+		// Defaults to false.
+		TakeOver bool `json:"takeOver,omitempty"`
+	} `json:"hubBackup,omitempty"`
+
+	// Protection of several namespaces by one DRPC
+	MultiNamespace struct {
+		// Name of the hub namespace whose DRPCs may list protectedNamespaces.
+		// Since such a DRPC fails over and relocates the applications of
+		// other namespaces, only the hub admin must be able to create DRPCs
+		// in it. Defaults to none, which allows no DRPC to.
+		AdminNamespaceName string `json:"adminNamespaceName,omitempty"`
+	} `json:"multiNamespace,omitempty"`
 }
 
 func init() {
@@ -133,6 +133,12 @@ type VolSyncSpec struct {
 
 	// disabled when set, all the VolSync code is bypassed. Default is 'false'
 	Disabled bool `json:"disabled,omitempty"`
+
+	// secretRotatedAt is when the keys of the replication secret that both
+	// clusters have were generated. The ReplicationSources are paused while
+	// the secret on the cluster has other keys
+	//+optional
+	SecretRotatedAt string `json:"secretRotatedAt,omitempty"`
 }
 
 // VRGAction which will be either a Failover or Relocate
@@ -169,6 +175,14 @@ type VolumeReplicationGroupSpec struct {
 	// that needs to be replicated to the peer cluster.
 	PVCSelector metav1.LabelSelector `json:"pvcSelector"`
 
+	// ProtectedNamespaces lists namespaces, besides the VRG's own, whose PVCs
+	// selected by PVCSelector and whose kube objects are protected by this VRG,
+	// so that they fail over and relocate as one unit. Their PVCs must be
+	// replicated by VolumeReplication, as VolSync protects PVCs in the VRG's
+	// namespace only
+	//+optional
+	ProtectedNamespaces []string `json:"protectedNamespaces,omitempty"`
+
 	// Desired state of all volumes [primary or secondary] in this replication group;
 	// this value is propagated to children VolumeReplication CRs
 	ReplicationState ReplicationState `json:"replicationState"`
@@ -199,6 +213,64 @@ type VolumeReplicationGroupSpec struct {
 	Action VRGAction `json:"action,omitempty"`
 	//+optional
 	KubeObjectProtection *KubeObjectProtectionSpec `json:"kubeObjectProtection,omitempty"`
+
+	// Drill when set, restores the replicated cluster data of the VRG with the same
+	// name in Drill.SourceNamespace to this VRG's namespace, for a failover drill.
+	// Volumes are neither replicated nor protected for such a VRG
+	//+optional
+	Drill *VRGDrillSpec `json:"drill,omitempty"`
+
+	// ClusterDataRetention when set, retains point-in-time generations of the
+	// PV cluster data and VRG uploaded to the S3 stores, in addition to the
+	// latest ones, which are overwritten on each upload
+	//+optional
+	ClusterDataRetention *ClusterDataRetentionSpec `json:"clusterDataRetention,omitempty"`
+
+	// ClusterDataGeneration when set, names the retained generation of PV
+	// cluster data to restore, instead of the latest one
+	//+optional
+	//+kubebuilder:validation:Pattern=`^[0-9]{8}T[0-9]{6}Z$`
+	ClusterDataGeneration string `json:"clusterDataGeneration,omitempty"`
+}
+
+// ClusterDataRetentionSpec limits the number of retained generations of PV
+// cluster data. The latest generation is always retained.
+type ClusterDataRetentionSpec struct {
+	// Maximum number of generations to retain
+	//+optional
+	//+kubebuilder:validation:Minimum=1
+	MaxCount int `json:"maxCount,omitempty"`
+
+	// Maximum age of the generations to retain
+	//+optional
+	//+kubebuilder:validation:Format=duration
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+// ClusterDataGeneration identifies a retained generation of PV cluster data
+type ClusterDataGeneration struct {
+	// Name of the generation, which is the UTC time it was captured at in the
+	// format YYYYMMDDThhmmssZ
+	Name string `json:"name"`
+
+	// Generation of the VRG whose PV cluster data the generation holds
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Digest of the PVCs, and of the specs of their PVs, whose cluster data
+	// the generation holds
+	//+optional
+	PVsDigest string `json:"pvsDigest,omitempty"`
+}
+
+// VRGDrillSpec identifies the protected VRG that a drill VRG rehearses a failover of
+type VRGDrillSpec struct {
+	// SourceNamespace is the namespace of the protected application
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// VerifierImage is the image of the pod that mounts the restored volumes
+	// read-only to verify that they are readable
+	// +optional
+	VerifierImage string `json:"verifierImage,omitempty"`
 }
 
 type KubeObjectProtectionSpec struct {
@@ -212,9 +284,84 @@ type KubeObjectProtectionSpec struct {
 
 	//+optional
 	RecoverOrder []KubeObjectsRecoverSpec `json:"recoverOrder,omitempty"`
+
+	// Number of the most recent captures to retain. Defaults to 2.
+	// +optional
+	// +kubebuilder:validation:Minimum=2
+	CaptureRetentionCount int `json:"captureRetentionCount,omitempty"`
+
+	// Number of the capture, among those retained, to recover from instead of
+	// the latest one. Capture numbers increase with each capture and are not
+	// reused, so a number identifies a single capture
+	// +optional
+	RecoverFromCaptureNumber *int64 `json:"recoverFromCaptureNumber,omitempty"`
+
+	// Hooks to quiesce the application before each capture starts and to
+	// resume it after the capture completes. Pre capture hooks run in order,
+	// and post capture hooks in reverse order.
+	// +optional
+	CaptureHooks []KubeObjectsCaptureHook `json:"captureHooks,omitempty"`
 }
 
-const KubeObjectProtectionCaptureIntervalDefault = 5 * time.Minute
+const (
+	KubeObjectProtectionCaptureIntervalDefault       = 5 * time.Minute
+	KubeObjectProtectionCaptureRetentionCountDefault = 2
+	KubeObjectsCaptureHookTimeoutDefault             = 30 * time.Second
+)
+
+// KubeObjectsCaptureHookErrorMode is what to do when a pre capture hook fails
+// +kubebuilder:validation:Enum=Fail;Continue
+type KubeObjectsCaptureHookErrorMode string
+
+const (
+	// Run the post capture hooks of the hooks that ran, and retry the capture later
+	KubeObjectsCaptureHookErrorModeFail = KubeObjectsCaptureHookErrorMode("Fail")
+
+	// Start the capture regardless
+	KubeObjectsCaptureHookErrorModeContinue = KubeObjectsCaptureHookErrorMode("Continue")
+)
+
+type KubeObjectsCaptureHook struct {
+	// Name of the hook, reported when it fails
+	Name string `json:"name"`
+
+	// Selects the pods to run the commands in, and the deployments and
+	// stateful sets to scale, in the VRG's namespace
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+
+	// Commands to run in each selected pod
+	// +optional
+	Exec *KubeObjectsCaptureHookExec `json:"exec,omitempty"`
+
+	// Scale the selected deployments and stateful sets to zero replicas
+	// before the capture, and back to their prior replicas after it
+	// +optional
+	ScaleToZero bool `json:"scaleToZero,omitempty"`
+
+	// Time allowed for the hook to run, before the capture and after it.
+	// Defaults to 30s.
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// What to do when the hook fails before the capture. Defaults to Fail.
+	// +optional
+	OnError KubeObjectsCaptureHookErrorMode `json:"onError,omitempty"`
+}
+
+type KubeObjectsCaptureHookExec struct {
+	// Container to run the commands in. Defaults to the pod's first container.
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// Command to run before the capture, such as fsfreeze --freeze
+	// +optional
+	Pre []string `json:"pre,omitempty"`
+
+	// Command to run after the capture
+	// +optional
+	Post []string `json:"post,omitempty"`
+}
 
 type KubeObjectsCaptureSpec struct {
 	// +optional
@@ -247,6 +394,11 @@ type ProtectedPVC struct {
 	//+optional
 	Name string `json:"name,omitempty"`
 
+	// Namespace of the VolRep/PVC resource. If not specified, then it is the
+	// VRG's namespace
+	//+optional
+	Namespace string `json:"namespace,omitempty"`
+
 	// VolSyncPVC can be used to denote whether this PVC is protected by VolSync. Defaults to "false".
 	//+optional
 	ProtectedByVolSync bool `json:"protectedByVolSync,omitempty"`
@@ -270,6 +422,11 @@ type ProtectedPVC struct {
 	// Conditions for this protected pvc
 	//+optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Time of the last successful replication of the PVC data to the peer
+	// cluster, if known. Only VolSync reports it
+	//+optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
 type KubeObjectsCaptureIdentifier struct {
@@ -281,6 +438,25 @@ type KubeObjectsCaptureIdentifier struct {
 type KubeObjectProtectionStatus struct {
 	// +optional
 	CaptureToRecoverFrom *KubeObjectsCaptureIdentifier `json:"captureToRecoverFrom,omitempty"`
+
+	// Retained captures that can be recovered from, from oldest to latest
+	// +optional
+	Captures []KubeObjectsCaptureIdentifier `json:"captures,omitempty"`
+
+	// Whether the pre capture hooks ran for a capture whose post capture
+	// hooks have not run yet
+	// +optional
+	CaptureHooksPostPending bool `json:"captureHooksPostPending,omitempty"`
+
+	// Whether the pre capture hooks ran for a final sync whose post capture
+	// hooks have not run yet
+	// +optional
+	FinalSyncHooksPostPending bool `json:"finalSyncHooksPostPending,omitempty"`
+
+	// When the pre capture hooks scaled workloads to zero, while waiting for
+	// their pods to terminate
+	// +optional
+	HooksScaleToZeroStartTime *metav1.Time `json:"hooksScaleToZeroStartTime,omitempty"`
 }
 
 // VolumeReplicationGroupStatus defines the observed state of VolumeReplicationGroup
@@ -304,6 +480,22 @@ type VolumeReplicationGroupStatus struct {
 
 	PrepareForFinalSyncComplete bool `json:"prepareForFinalSyncComplete,omitempty"`
 	FinalSyncComplete           bool `json:"finalSyncComplete,omitempty"`
+
+	// lastGroupSyncTime is the time up to which the data of all the protected
+	// PVCs is replicated to the peer cluster, i.e. the oldest of their last
+	// sync times
+	// +optional
+	LastGroupSyncTime *metav1.Time `json:"lastGroupSyncTime,omitempty"`
+
+	// lastClusterDataGeneration is the latest retained generation of PV
+	// cluster data, if retention is enabled
+	// +optional
+	LastClusterDataGeneration *ClusterDataGeneration `json:"lastClusterDataGeneration,omitempty"`
+
+	// volSyncSecretRotatedAt is when the keys of the VolSync replication
+	// secret on the cluster were generated
+	// +optional
+	VolSyncSecretRotatedAt string `json:"volSyncSecretRotatedAt,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -27,6 +27,66 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoFailoverSpec) DeepCopyInto(out *AutoFailoverSpec) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoFailoverSpec.
+func (in *AutoFailoverSpec) DeepCopy() *AutoFailoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoFailoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDataGeneration) DeepCopyInto(out *ClusterDataGeneration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDataGeneration.
+func (in *ClusterDataGeneration) DeepCopy() *ClusterDataGeneration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDataGeneration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDataRetentionSpec) DeepCopyInto(out *ClusterDataRetentionSpec) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDataRetentionSpec.
+func (in *ClusterDataRetentionSpec) DeepCopy() *ClusterDataRetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDataRetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DRCluster) DeepCopyInto(out *DRCluster) {
 	*out = *in
@@ -94,6 +154,11 @@ func (in *DRClusterSpec) DeepCopyInto(out *DRClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.FencingWebhook != nil {
+		in, out := &in.FencingWebhook, &out.FencingWebhook
+		*out = new(FencingWebhook)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterSpec.
@@ -116,6 +181,33 @@ func (in *DRClusterStatus) DeepCopyInto(out *DRClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FenceHistory != nil {
+		in, out := &in.FenceHistory, &out.FenceHistory
+		*out = make([]FenceOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StorageFencing != nil {
+		in, out := &in.StorageFencing, &out.StorageFencing
+		*out = new(StorageFencing)
+		**out = **in
+	}
+	if in.StorageFencingCandidates != nil {
+		in, out := &in.StorageFencingCandidates, &out.StorageFencingCandidates
+		*out = make([]StorageFencing, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeCIDRs != nil {
+		in, out := &in.NodeCIDRs, &out.NodeCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FencedCIDRs != nil {
+		in, out := &in.FencedCIDRs, &out.FencedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterStatus.
@@ -128,6 +220,153 @@ func (in *DRClusterStatus) DeepCopy() *DRClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRFailoverPlan) DeepCopyInto(out *DRFailoverPlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRFailoverPlan.
+func (in *DRFailoverPlan) DeepCopy() *DRFailoverPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(DRFailoverPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DRFailoverPlan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRFailoverPlanDRPCStatus) DeepCopyInto(out *DRFailoverPlanDRPCStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRFailoverPlanDRPCStatus.
+func (in *DRFailoverPlanDRPCStatus) DeepCopy() *DRFailoverPlanDRPCStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DRFailoverPlanDRPCStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRFailoverPlanList) DeepCopyInto(out *DRFailoverPlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DRFailoverPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRFailoverPlanList.
+func (in *DRFailoverPlanList) DeepCopy() *DRFailoverPlanList {
+	if in == nil {
+		return nil
+	}
+	out := new(DRFailoverPlanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DRFailoverPlanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRFailoverPlanSpec) DeepCopyInto(out *DRFailoverPlanSpec) {
+	*out = *in
+	in.DRPCSelector.DeepCopyInto(&out.DRPCSelector)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WaveTimeout != nil {
+		in, out := &in.WaveTimeout, &out.WaveTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRFailoverPlanSpec.
+func (in *DRFailoverPlanSpec) DeepCopy() *DRFailoverPlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DRFailoverPlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRFailoverPlanStatus) DeepCopyInto(out *DRFailoverPlanStatus) {
+	*out = *in
+	if in.WaveStartTime != nil {
+		in, out := &in.WaveStartTime, &out.WaveStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DRPCs != nil {
+		in, out := &in.DRPCs, &out.DRPCs
+		*out = make([]DRFailoverPlanDRPCStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRFailoverPlanStatus.
+func (in *DRFailoverPlanStatus) DeepCopy() *DRFailoverPlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DRFailoverPlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRPCReference) DeepCopyInto(out *DRPCReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPCReference.
+func (in *DRPCReference) DeepCopy() *DRPCReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DRPCReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DRPlacementControl) DeepCopyInto(out *DRPlacementControl) {
 	*out = *in
@@ -193,6 +432,31 @@ func (in *DRPlacementControlSpec) DeepCopyInto(out *DRPlacementControlSpec) {
 	out.PlacementRef = in.PlacementRef
 	out.DRPolicyRef = in.DRPolicyRef
 	in.PVCSelector.DeepCopyInto(&out.PVCSelector)
+	if in.ProtectedNamespaces != nil {
+		in, out := &in.ProtectedNamespaces, &out.ProtectedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]DRPCReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drill != nil {
+		in, out := &in.Drill, &out.Drill
+		*out = new(DrillSpec)
+		**out = **in
+	}
+	if in.ClusterDataRetention != nil {
+		in, out := &in.ClusterDataRetention, &out.ClusterDataRetention
+		*out = new(ClusterDataRetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeObjectProtection != nil {
+		in, out := &in.KubeObjectProtection, &out.KubeObjectProtection
+		*out = new(KubeObjectProtectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControlSpec.
@@ -227,6 +491,20 @@ func (in *DRPlacementControlStatus) DeepCopyInto(out *DRPlacementControlStatus)
 	}
 	in.ResourceConditions.DeepCopyInto(&out.ResourceConditions)
 	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	if in.LastGroupSyncTime != nil {
+		in, out := &in.LastGroupSyncTime, &out.LastGroupSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Drill != nil {
+		in, out := &in.Drill, &out.Drill
+		*out = new(DrillStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ReadinessStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControlStatus.
@@ -308,6 +586,11 @@ func (in *DRPolicySpec) DeepCopyInto(out *DRPolicySpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AutoFailover != nil {
+		in, out := &in.AutoFailover, &out.AutoFailover
+		*out = new(AutoFailoverSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicySpec.
@@ -330,6 +613,10 @@ func (in *DRPolicyStatus) DeepCopyInto(out *DRPolicyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastAutoFailoverTime != nil {
+		in, out := &in.LastAutoFailoverTime, &out.LastAutoFailoverTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicyStatus.
@@ -342,6 +629,80 @@ func (in *DRPolicyStatus) DeepCopy() *DRPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrillSpec) DeepCopyInto(out *DrillSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrillSpec.
+func (in *DrillSpec) DeepCopy() *DrillSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrillSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrillStatus) DeepCopyInto(out *DrillStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	in.ResourceConditions.DeepCopyInto(&out.ResourceConditions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrillStatus.
+func (in *DrillStatus) DeepCopy() *DrillStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DrillStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FenceOperation) DeepCopyInto(out *FenceOperation) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FenceOperation.
+func (in *FenceOperation) DeepCopy() *FenceOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(FenceOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FencingWebhook) DeepCopyInto(out *FencingWebhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FencingWebhook.
+func (in *FencingWebhook) DeepCopy() *FencingWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(FencingWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeObjectProtectionSpec) DeepCopyInto(out *KubeObjectProtectionSpec) {
 	*out = *in
@@ -364,6 +725,18 @@ func (in *KubeObjectProtectionSpec) DeepCopyInto(out *KubeObjectProtectionSpec)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RecoverFromCaptureNumber != nil {
+		in, out := &in.RecoverFromCaptureNumber, &out.RecoverFromCaptureNumber
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CaptureHooks != nil {
+		in, out := &in.CaptureHooks, &out.CaptureHooks
+		*out = make([]KubeObjectsCaptureHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectProtectionSpec.
@@ -384,6 +757,17 @@ func (in *KubeObjectProtectionStatus) DeepCopyInto(out *KubeObjectProtectionStat
 		*out = new(KubeObjectsCaptureIdentifier)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Captures != nil {
+		in, out := &in.Captures, &out.Captures
+		*out = make([]KubeObjectsCaptureIdentifier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HooksScaleToZeroStartTime != nil {
+		in, out := &in.HooksScaleToZeroStartTime, &out.HooksScaleToZeroStartTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectProtectionStatus.
@@ -396,6 +780,57 @@ func (in *KubeObjectProtectionStatus) DeepCopy() *KubeObjectProtectionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsCaptureHook) DeepCopyInto(out *KubeObjectsCaptureHook) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(KubeObjectsCaptureHookExec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsCaptureHook.
+func (in *KubeObjectsCaptureHook) DeepCopy() *KubeObjectsCaptureHook {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsCaptureHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsCaptureHookExec) DeepCopyInto(out *KubeObjectsCaptureHookExec) {
+	*out = *in
+	if in.Pre != nil {
+		in, out := &in.Pre, &out.Pre
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Post != nil {
+		in, out := &in.Post, &out.Post
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsCaptureHookExec.
+func (in *KubeObjectsCaptureHookExec) DeepCopy() *KubeObjectsCaptureHookExec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsCaptureHookExec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeObjectsCaptureIdentifier) DeepCopyInto(out *KubeObjectsCaptureIdentifier) {
 	*out = *in
@@ -479,6 +914,22 @@ func (in *KubeObjectsSpec) DeepCopy() *KubeObjectsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectEncryption) DeepCopyInto(out *ObjectEncryption) {
+	*out = *in
+	out.KeySecretRef = in.KeySecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectEncryption.
+func (in *ObjectEncryption) DeepCopy() *ObjectEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtectedPVC) DeepCopyInto(out *ProtectedPVC) {
 	*out = *in
@@ -507,6 +958,10 @@ func (in *ProtectedPVC) DeepCopyInto(out *ProtectedPVC) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedPVC.
@@ -524,7 +979,7 @@ func (in *ProtectedVolumeReplicationGroupList) DeepCopyInto(out *ProtectedVolume
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(ProtectedVolumeReplicationGroupListStatus)
@@ -585,6 +1040,26 @@ func (in *ProtectedVolumeReplicationGroupListList) DeepCopyObject() runtime.Obje
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtectedVolumeReplicationGroupListSpec) DeepCopyInto(out *ProtectedVolumeReplicationGroupListSpec) {
 	*out = *in
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NamespaceNames != nil {
+		in, out := &in.NamespaceNames, &out.NamespaceNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VRGNames != nil {
+		in, out := &in.VRGNames, &out.VRGNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupListSpec.
@@ -633,6 +1108,9 @@ func (in *RamenConfig) DeepCopyInto(out *RamenConfig) {
 	out.DrClusterOperator = in.DrClusterOperator
 	out.VolSync = in.VolSync
 	out.KubeObjectProtection = in.KubeObjectProtection
+	out.DataLagging = in.DataLagging
+	out.HubBackup = in.HubBackup
+	out.MultiNamespace = in.MultiNamespace
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RamenConfig.
@@ -653,10 +1131,54 @@ func (in *RamenConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]DRAction, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessCheck.
+func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessStatus) DeepCopyInto(out *ReadinessStatus) {
+	*out = *in
+	in.CheckTime.DeepCopyInto(&out.CheckTime)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ReadinessCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessStatus.
+func (in *ReadinessStatus) DeepCopy() *ReadinessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3StoreProfile) DeepCopyInto(out *S3StoreProfile) {
 	*out = *in
 	out.S3SecretRef = in.S3SecretRef
+	out.Encryption = in.Encryption
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3StoreProfile.
@@ -669,6 +1191,21 @@ func (in *S3StoreProfile) DeepCopy() *S3StoreProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageFencing) DeepCopyInto(out *StorageFencing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageFencing.
+func (in *StorageFencing) DeepCopy() *StorageFencing {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageFencing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VRGAsyncSpec) DeepCopyInto(out *VRGAsyncSpec) {
 	*out = *in
@@ -709,6 +1246,21 @@ func (in *VRGConditions) DeepCopy() *VRGConditions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VRGDrillSpec) DeepCopyInto(out *VRGDrillSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VRGDrillSpec.
+func (in *VRGDrillSpec) DeepCopy() *VRGDrillSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VRGDrillSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VRGResourceMeta) DeepCopyInto(out *VRGResourceMeta) {
 	*out = *in
@@ -861,6 +1413,11 @@ func (in *VolumeReplicationGroupList) DeepCopyObject() runtime.Object {
 func (in *VolumeReplicationGroupSpec) DeepCopyInto(out *VolumeReplicationGroupSpec) {
 	*out = *in
 	in.PVCSelector.DeepCopyInto(&out.PVCSelector)
+	if in.ProtectedNamespaces != nil {
+		in, out := &in.ProtectedNamespaces, &out.ProtectedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.S3Profiles != nil {
 		in, out := &in.S3Profiles, &out.S3Profiles
 		*out = make([]string, len(*in))
@@ -874,6 +1431,16 @@ func (in *VolumeReplicationGroupSpec) DeepCopyInto(out *VolumeReplicationGroupSp
 		*out = new(KubeObjectProtectionSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Drill != nil {
+		in, out := &in.Drill, &out.Drill
+		*out = new(VRGDrillSpec)
+		**out = **in
+	}
+	if in.ClusterDataRetention != nil {
+		in, out := &in.ClusterDataRetention, &out.ClusterDataRetention
+		*out = new(ClusterDataRetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeReplicationGroupSpec.
@@ -905,6 +1472,15 @@ func (in *VolumeReplicationGroupStatus) DeepCopyInto(out *VolumeReplicationGroup
 	}
 	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
 	in.KubeObjectProtection.DeepCopyInto(&out.KubeObjectProtection)
+	if in.LastGroupSyncTime != nil {
+		in, out := &in.LastGroupSyncTime, &out.LastGroupSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastClusterDataGeneration != nil {
+		in, out := &in.LastClusterDataGeneration, &out.LastClusterDataGeneration
+		*out = new(ClusterDataGeneration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeReplicationGroupStatus.
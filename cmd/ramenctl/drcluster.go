@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The RamenDR authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const drclusterFenceTimeoutDefault = 10 * time.Minute
+
+func drclusterFence(ctx context.Context, c client.Client, args []string) error {
+	return drclusterFenceSet(ctx, c, "fence", ramen.ClusterFenceStateFenced, ramen.Fenced, args)
+}
+
+func drclusterUnfence(ctx context.Context, c client.Client, args []string) error {
+	return drclusterFenceSet(ctx, c, "unfence", ramen.ClusterFenceStateUnfenced, ramen.Unfenced, args)
+}
+
+// drclusterFenceSet sets a DRCluster's desired fence state and waits for the
+// DRCluster controller to reach it unless told not to
+func drclusterFenceSet(ctx context.Context, c client.Client, commandName string,
+	fenceState ramen.ClusterFenceState, phase ramen.DRClusterPhase, args []string,
+) error {
+	flags := flag.NewFlagSet(commandName, flag.ExitOnError)
+	wait := flags.Bool("wait", true, "wait for the DRCluster to reach the fence state")
+	timeout := flags.Duration("timeout", drclusterFenceTimeoutDefault, "time to wait for the fence state")
+
+	name, err := nameArgument(flags, args, "DRCluster")
+	if err != nil {
+		return err
+	}
+
+	drcluster := &ramen.DRCluster{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, drcluster); err != nil {
+		return fmt.Errorf("drcluster get: %w", err)
+	}
+
+	drcluster.Spec.ClusterFence = fenceState
+	if err := c.Update(ctx, drcluster); err != nil {
+		return fmt.Errorf("drcluster update: %w", err)
+	}
+
+	fmt.Printf("%s %s requested\n", name, commandName)
+
+	if !*wait {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	for {
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, drcluster); err != nil {
+			return fmt.Errorf("drcluster get: %w", err)
+		}
+
+		fenced := meta.FindStatusCondition(drcluster.Status.Conditions, ramen.DRClusterConditionTypeFenced)
+		if drcluster.Status.Phase == phase && fenced != nil && fenced.ObservedGeneration == drcluster.Generation {
+			fmt.Printf("%s %s\n", name, phase)
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s wait, phase %s: %w", commandName, drcluster.Status.Phase, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// drclusterFenced returns whether a DRCluster's fenced condition is true for
+// its current generation
+func drclusterFenced(drclusters []ramen.DRCluster, name string) bool {
+	for i := range drclusters {
+		if drclusters[i].Name != name {
+			continue
+		}
+
+		condition := meta.FindStatusCondition(drclusters[i].Status.Conditions, ramen.DRClusterConditionTypeFenced)
+
+		return condition != nil && condition.Status == metav1.ConditionTrue &&
+			condition.ObservedGeneration == drclusters[i].Generation
+	}
+
+	return false
+}
+
+func drclusterRegion(drclusters []ramen.DRCluster, name string) ramen.Region {
+	for i := range drclusters {
+		if drclusters[i].Name == name {
+			return drclusters[i].Spec.Region
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2022 The RamenDR authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const drpcActionTimeoutDefault = 30 * time.Minute
+
+func drpcList(ctx context.Context, c client.Client, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	namespaceName := flags.String("n", "", "namespace of the DRPCs; all namespaces if empty")
+
+	if names := flagsParse(flags, args); len(names) != 0 {
+		return fmt.Errorf("list takes no arguments: %v", names)
+	}
+
+	drpcs := ramen.DRPlacementControlList{}
+	if err := c.List(ctx, &drpcs, client.InNamespace(*namespaceName)); err != nil {
+		return fmt.Errorf("drpcs list: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tPREFERRED\tFAILOVER\tCURRENT\tACTION\tPHASE\tPROGRESSION\tPEERREADY")
+
+	for i := range drpcs.Items {
+		drpc := &drpcs.Items[i]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			drpc.Namespace,
+			drpc.Name,
+			drpc.Spec.PreferredCluster,
+			drpc.Spec.FailoverCluster,
+			drpc.Status.PreferredDecision.ClusterName,
+			drpc.Spec.Action,
+			drpc.Status.Phase,
+			drpc.Status.Progression,
+			conditionStatus(drpc.Status.Conditions, ramen.ConditionPeerReady),
+		)
+	}
+
+	return w.Flush()
+}
+
+func drpcFailover(ctx context.Context, c client.Client, args []string) error {
+	return drpcActionRun(ctx, c, ramen.ActionFailover, args)
+}
+
+func drpcRelocate(ctx context.Context, c client.Client, args []string) error {
+	return drpcActionRun(ctx, c, ramen.ActionRelocate, args)
+}
+
+// drpcActionRun sets a DRPC's action and target cluster, after pre-flight
+// checks unless forced, and waits for the action to complete unless told not
+// to
+func drpcActionRun(ctx context.Context, c client.Client, action ramen.DRAction, args []string) error {
+	flags := flag.NewFlagSet(strings.ToLower(string(action)), flag.ExitOnError)
+	namespaceName := flags.String("n", "default", "namespace of the DRPC")
+	targetClusterName := flags.String("to", "",
+		"cluster to move the application to; the preferred cluster by default for relocate")
+	force := flags.Bool("force", false, "skip the pre-flight checks")
+	wait := flags.Bool("wait", true, "wait for the action to complete, printing its progression")
+	timeout := flags.Duration("timeout", drpcActionTimeoutDefault, "time to wait for the action to complete")
+
+	name, err := nameArgument(flags, args, "DRPC")
+	if err != nil {
+		return err
+	}
+
+	drpc := &ramen.DRPlacementControl{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: *namespaceName, Name: name}, drpc); err != nil {
+		return fmt.Errorf("drpc get: %w", err)
+	}
+
+	if *targetClusterName == "" {
+		if action == ramen.ActionFailover {
+			return fmt.Errorf("failover requires a target cluster, -to")
+		}
+
+		*targetClusterName = drpc.Spec.PreferredCluster
+	}
+
+	if !*force {
+		if err := drpcActionPreflight(ctx, c, drpc, action, *targetClusterName); err != nil {
+			return fmt.Errorf("pre-flight check failed: %w; -force skips the checks", err)
+		}
+	}
+
+	drpc.Spec.Action = action
+
+	switch action {
+	case ramen.ActionFailover:
+		drpc.Spec.FailoverCluster = *targetClusterName
+	case ramen.ActionRelocate:
+		drpc.Spec.PreferredCluster = *targetClusterName
+	}
+
+	if err := c.Update(ctx, drpc); err != nil {
+		return fmt.Errorf("drpc update: %w", err)
+	}
+
+	fmt.Printf("%s/%s %s to %s requested\n", drpc.Namespace, drpc.Name, strings.ToLower(string(action)),
+		*targetClusterName)
+
+	if !*wait {
+		return nil
+	}
+
+	return drpcActionWait(ctx, c, drpc, action, *timeout)
+}
+
+// drpcActionPreflight returns an error if the DRPC controller is expected to
+// refuse or stall the action, as it requires of either action that the peer
+// be ready, of a failover that the current cluster be fenced if it is in the
+// target cluster's region, and of a relocate that neither cluster be fenced
+func drpcActionPreflight(ctx context.Context, c client.Client, drpc *ramen.DRPlacementControl,
+	action ramen.DRAction, targetClusterName string,
+) error {
+	drpolicy := &ramen.DRPolicy{}
+	if err := c.Get(ctx, types.NamespacedName{Name: drpc.Spec.DRPolicyRef.Name}, drpolicy); err != nil {
+		return fmt.Errorf("drpolicy get: %w", err)
+	}
+
+	if !sets.NewString(drpolicy.Spec.DRClusters...).Has(targetClusterName) {
+		return fmt.Errorf("cluster %s is not a cluster of drpolicy %s", targetClusterName, drpolicy.Name)
+	}
+
+	currentClusterName := drpc.Status.PreferredDecision.ClusterName
+	if currentClusterName == targetClusterName {
+		return fmt.Errorf("application is already placed on cluster %s", targetClusterName)
+	}
+
+	drclusters := ramen.DRClusterList{}
+	if err := c.List(ctx, &drclusters); err != nil {
+		return fmt.Errorf("drclusters list: %w", err)
+	}
+
+	if drclusterFenced(drclusters.Items, targetClusterName) {
+		return fmt.Errorf("target cluster %s is fenced", targetClusterName)
+	}
+
+	if condition := meta.FindStatusCondition(drpc.Status.Conditions, ramen.ConditionPeerReady); condition != nil &&
+		condition.Status != metav1.ConditionTrue {
+		return fmt.Errorf("peer is not ready: %s", condition.Message)
+	}
+
+	switch action {
+	case ramen.ActionFailover:
+		sameRegion := drclusterRegion(drclusters.Items, currentClusterName) ==
+			drclusterRegion(drclusters.Items, targetClusterName)
+		if currentClusterName != "" && sameRegion && !drclusterFenced(drclusters.Items, currentClusterName) {
+			return fmt.Errorf("current cluster %s, in the region of cluster %s, is not fenced",
+				currentClusterName, targetClusterName)
+		}
+	case ramen.ActionRelocate:
+		if currentClusterName != "" && drclusterFenced(drclusters.Items, currentClusterName) {
+			return fmt.Errorf("current cluster %s is fenced", currentClusterName)
+		}
+	}
+
+	return nil
+}
+
+// drpcActionWait prints a DRPC's progression until its action completes
+func drpcActionWait(ctx context.Context, c client.Client, drpc *ramen.DRPlacementControl,
+	action ramen.DRAction, timeout time.Duration,
+) error {
+	phase := ramen.FailedOver
+	if action == ramen.ActionRelocate {
+		phase = ramen.Relocated
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	key := types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name}
+	progress := ""
+
+	for {
+		if err := c.Get(ctx, key, drpc); err != nil {
+			return fmt.Errorf("drpc get: %w", err)
+		}
+
+		available := meta.FindStatusCondition(drpc.Status.Conditions, ramen.ConditionAvailable)
+		availableMessage := ""
+
+		if available != nil && available.ObservedGeneration == drpc.Generation {
+			availableMessage = available.Message
+		}
+
+		if progressNew := fmt.Sprintf("%s %s %s", drpc.Status.Phase, drpc.Status.Progression,
+			availableMessage); progressNew != progress {
+			progress = progressNew
+			fmt.Printf("%s phase=%s progression=%s %s\n", time.Now().Format(time.RFC3339), drpc.Status.Phase,
+				drpc.Status.Progression, availableMessage)
+		}
+
+		if drpc.Status.Phase == phase && drpc.Status.Progression == ramen.ProgressionCompleted &&
+			available != nil && available.ObservedGeneration == drpc.Generation &&
+			available.Status == metav1.ConditionTrue {
+			fmt.Printf("%s/%s %s completed\n", drpc.Namespace, drpc.Name, strings.ToLower(string(action)))
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s wait: %w", strings.ToLower(string(action)), ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// conditionStatus returns the status of a condition, or an empty string if
+// absent
+func conditionStatus(conditions []metav1.Condition, conditionType string) metav1.ConditionStatus {
+	if condition := meta.FindStatusCondition(conditions, conditionType); condition != nil {
+		return condition.Status
+	}
+
+	return ""
+}
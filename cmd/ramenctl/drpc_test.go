@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The RamenDR authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("Command parsing", func() {
+	It("parses flags that follow the positional arguments", func() {
+		flags := flag.NewFlagSet("failover", flag.ContinueOnError)
+		namespaceName := flags.String("n", "default", "")
+		wait := flags.Bool("wait", true, "")
+
+		Expect(flagsParse(flags, []string{"-n", "app", "drpc", "-wait=false"})).To(Equal([]string{"drpc"}))
+		Expect(*namespaceName).To(Equal("app"))
+		Expect(*wait).To(BeFalse())
+	})
+
+	It("requires a single name argument", func() {
+		name, err := nameArgument(flag.NewFlagSet("fence", flag.ContinueOnError), []string{"east"}, "DRCluster")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("east"))
+
+		_, err = nameArgument(flag.NewFlagSet("fence", flag.ContinueOnError), []string{}, "DRCluster")
+		Expect(err).To(MatchError("fence requires a single DRCluster name, not 0"))
+
+		_, err = nameArgument(flag.NewFlagSet("fence", flag.ContinueOnError), []string{"east", "west"}, "DRCluster")
+		Expect(err).To(MatchError("fence requires a single DRCluster name, not 2"))
+	})
+})
+
+var _ = Describe("DRPC action pre-flight", func() {
+	var (
+		drpolicy   *ramen.DRPolicy
+		drclusters []*ramen.DRCluster
+		drpc       *ramen.DRPlacementControl
+	)
+
+	drclusterNew := func(name string, region ramen.Region, fenced bool) *ramen.DRCluster {
+		status := metav1.ConditionFalse
+		if fenced {
+			status = metav1.ConditionTrue
+		}
+
+		return &ramen.DRCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+			Spec:       ramen.DRClusterSpec{Region: region},
+			Status: ramen.DRClusterStatus{Conditions: []metav1.Condition{{
+				Type:               ramen.DRClusterConditionTypeFenced,
+				Status:             status,
+				ObservedGeneration: 1,
+			}}},
+		}
+	}
+
+	clientNew := func() client.Client {
+		scheme := runtime.NewScheme()
+		Expect(ramen.AddToScheme(scheme)).To(Succeed())
+
+		objects := []client.Object{drpolicy, drpc}
+		for _, drcluster := range drclusters {
+			objects = append(objects, drcluster)
+		}
+
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	}
+
+	preflight := func(action ramen.DRAction, targetClusterName string) error {
+		return drpcActionPreflight(context.TODO(), clientNew(), drpc, action, targetClusterName)
+	}
+
+	BeforeEach(func() {
+		drpolicy = &ramen.DRPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "drpolicy"},
+			Spec:       ramen.DRPolicySpec{DRClusters: []string{"east", "west"}},
+		}
+		drclusters = []*ramen.DRCluster{
+			drclusterNew("east", "region-east", false),
+			drclusterNew("west", "region-west", false),
+		}
+		drpc = &ramen.DRPlacementControl{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"},
+			Spec: ramen.DRPlacementControlSpec{
+				DRPolicyRef:      corev1.ObjectReference{Name: drpolicy.Name},
+				PreferredCluster: "east",
+			},
+			Status: ramen.DRPlacementControlStatus{
+				PreferredDecision: plrv1.PlacementDecision{ClusterName: "east"},
+				Conditions: []metav1.Condition{{
+					Type:   ramen.ConditionPeerReady,
+					Status: metav1.ConditionTrue,
+				}},
+			},
+		}
+	})
+
+	It("passes a failover and a relocate to a ready peer", func() {
+		Expect(preflight(ramen.ActionFailover, "west")).To(Succeed())
+		Expect(preflight(ramen.ActionRelocate, "west")).To(Succeed())
+	})
+
+	It("refuses a target cluster outside the DRPolicy or already placed", func() {
+		Expect(preflight(ramen.ActionFailover, "north")).To(MatchError(
+			"cluster north is not a cluster of drpolicy drpolicy"))
+		Expect(preflight(ramen.ActionRelocate, "east")).To(MatchError(
+			"application is already placed on cluster east"))
+	})
+
+	It("refuses a fenced target cluster", func() {
+		drclusters[1] = drclusterNew("west", "region-west", true)
+
+		Expect(preflight(ramen.ActionFailover, "west")).To(MatchError("target cluster west is fenced"))
+	})
+
+	It("refuses a failover and a relocate if the peer is not ready", func() {
+		drpc.Status.Conditions[0].Status = metav1.ConditionFalse
+		drpc.Status.Conditions[0].Message = "relocation in progress"
+
+		Expect(preflight(ramen.ActionFailover, "west")).To(MatchError("peer is not ready: relocation in progress"))
+		Expect(preflight(ramen.ActionRelocate, "west")).To(MatchError("peer is not ready: relocation in progress"))
+	})
+
+	It("refuses a failover within a region unless the current cluster is fenced", func() {
+		drclusters[1] = drclusterNew("west", "region-east", false)
+
+		Expect(preflight(ramen.ActionFailover, "west")).To(MatchError(
+			"current cluster east, in the region of cluster west, is not fenced"))
+
+		drclusters[0] = drclusterNew("east", "region-east", true)
+
+		Expect(preflight(ramen.ActionFailover, "west")).To(Succeed())
+	})
+
+	It("refuses a relocate from a fenced cluster", func() {
+		drclusters[0] = drclusterNew("east", "region-east", true)
+
+		Expect(preflight(ramen.ActionRelocate, "west")).To(MatchError("current cluster east is fenced"))
+	})
+
+	It("sets the action and target cluster of a DRPC without waiting", func() {
+		c := clientNew()
+
+		Expect(drpcActionRun(context.TODO(), c, ramen.ActionFailover,
+			[]string{"-n", drpc.Namespace, drpc.Name, "-to", "west", "-wait=false"})).To(Succeed())
+
+		updated := &ramen.DRPlacementControl{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name},
+			updated)).To(Succeed())
+		Expect(updated.Spec.Action).To(Equal(ramen.ActionFailover))
+		Expect(updated.Spec.FailoverCluster).To(Equal("west"))
+	})
+
+	It("requires a target cluster of a failover", func() {
+		Expect(drpcActionRun(context.TODO(), clientNew(), ramen.ActionFailover,
+			[]string{"-n", drpc.Namespace, drpc.Name})).To(MatchError("failover requires a target cluster, -to"))
+	})
+})
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The RamenDR authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// ramenctl lists DRPlacementControls, fails them over or relocates them, and
+// fences or unfences DRClusters on a Ramen hub cluster
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const pollInterval = 5 * time.Second
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, c client.Client, args []string) error
+}
+
+var commands = []command{
+	{"list", "list [-n namespace]", drpcList},
+	{"failover", "failover [-n namespace] -to cluster [-force] [-wait=false] [-timeout duration] drpc", drpcFailover},
+	{"relocate", "relocate [-n namespace] [-to cluster] [-force] [-wait=false] [-timeout duration] drpc", drpcRelocate},
+	{"fence", "fence [-wait=false] [-timeout duration] drcluster", drclusterFence},
+	{"unfence", "unfence [-wait=false] [-timeout duration] drcluster", drclusterUnfence},
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-kubeconfig file] command\n\nCommands:\n", os.Args[0])
+
+	for _, command := range commands {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s\n", command.usage)
+	}
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, command := range commands {
+		if command.name != flag.Arg(0) {
+			continue
+		}
+
+		c, err := clientNew()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ramenctl:", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		err = command.run(ctx, c, flag.Args()[1:])
+
+		cancel()
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ramenctl:", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "ramenctl: unknown command %q\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+// clientNew returns a client of the hub cluster of the kubeconfig flag, the
+// KUBECONFIG environment variable, the in-cluster config or ~/.kube/config
+func clientNew() (client.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("kubeconfig get: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(ramen.AddToScheme(scheme))
+
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
+
+// flagsParse parses the flags of a command, which may follow its positional
+// arguments, and returns the positional arguments
+func flagsParse(flags *flag.FlagSet, args []string) []string {
+	positional := []string{}
+
+	for {
+		// flags that fail to parse exit, per flag.ExitOnError
+		_ = flags.Parse(args)
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// nameArgument returns the single positional argument of a command
+func nameArgument(flags *flag.FlagSet, args []string, kind string) (string, error) {
+	names := flagsParse(flags, args)
+	if len(names) != 1 {
+		return "", fmt.Errorf("%s requires a single %s name, not %d", flags.Name(), kind, len(names))
+	}
+
+	return names[0], nil
+}
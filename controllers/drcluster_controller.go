@@ -22,6 +22,7 @@ import (
 	"net"
 	"reflect"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -121,7 +122,7 @@ func (r *DRClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	if !u.object.ObjectMeta.DeletionTimestamp.IsZero() {
-		return r.processDeletion(u)
+		return r.processDeletion(u, ramenConfig)
 	}
 
 	log.Info("create/update")
@@ -139,7 +140,14 @@ func (r *DRClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			u.validatedSetFalseAndUpdate(ReasonValidationFailed, err))
 	}
 
-	requeue, err = u.clusterFenceHandle()
+	storageFencingRequeue := false
+	if err := u.storageFencingUpdate(ramenConfig); err != nil {
+		log.Info("Storage fencing details not updated", "error", err.Error())
+
+		storageFencingRequeue = true
+	}
+
+	requeue, err = u.clusterFenceHandle(ramenConfig)
 	if err != nil {
 		// On error proceed with S3 validation, as fencing is independent of S3
 		reconcileError = fmt.Errorf("failed to handle cluster fencing: %w", err)
@@ -156,7 +164,7 @@ func (r *DRClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		log.Info("failed to update status", "failure", err)
 	}
 
-	return ctrl.Result{Requeue: requeue}, reconcileError
+	return ctrl.Result{Requeue: requeue || storageFencingRequeue}, reconcileError
 }
 
 func (u *drclusterInstance) initializeStatus() {
@@ -224,7 +232,8 @@ func validateCIDRsFormat(drcluster *ramen.DRCluster, log logr.Logger) error {
 	return nil
 }
 
-func (r DRClusterReconciler) processDeletion(u *drclusterInstance) (ctrl.Result, error) {
+func (r DRClusterReconciler) processDeletion(u *drclusterInstance, ramenConfig *ramen.RamenConfig,
+) (ctrl.Result, error) {
 	u.log.Info("delete")
 
 	// Undeploy manifests
@@ -232,9 +241,14 @@ func (r DRClusterReconciler) processDeletion(u *drclusterInstance) (ctrl.Result,
 		return ctrl.Result{}, fmt.Errorf("drclusters undeploy: %w", err)
 	}
 
+	if err := r.MCVGetter.DeleteConfigMapManagedClusterView(StorageFencingConfigMapName,
+		drClusterOperatorNamespaceNameOrDefault(ramenConfig), u.object.Name, util.MWTypeCM); err != nil {
+		return ctrl.Result{}, fmt.Errorf("storage fencing view delete: %w", err)
+	}
+
 	if u.object.Spec.ClusterFence == ramen.ClusterFenceStateFenced ||
 		u.object.Spec.ClusterFence == ramen.ClusterFenceStateUnfenced {
-		requeue, err := u.handleDeletion()
+		requeue, err := u.handleDeletion(ramenConfig)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("cleanup update: %w", err)
 		}
@@ -323,10 +337,10 @@ func (u *drclusterInstance) finalizerRemove() error {
 //
 // 3) Handle Ramen driven fencing here
 //
-func (u *drclusterInstance) clusterFenceHandle() (bool, error) {
+func (u *drclusterInstance) clusterFenceHandle(ramenConfig *ramen.RamenConfig) (bool, error) {
 	switch u.object.Spec.ClusterFence {
 	case ramen.ClusterFenceStateUnfenced:
-		return u.clusterUnfence()
+		return u.clusterUnfence(ramenConfig)
 
 	case ramen.ClusterFenceStateManuallyFenced:
 		setDRClusterFencedCondition(&u.object.Status.Conditions, u.object.Generation, "Cluster Manually fenced")
@@ -342,7 +356,7 @@ func (u *drclusterInstance) clusterFenceHandle() (bool, error) {
 		return false, nil
 
 	case ramen.ClusterFenceStateFenced:
-		return u.clusterFence()
+		return u.clusterFence(ramenConfig)
 
 	default:
 		// This is needed when a DRCluster is created fresh without any fencing related information.
@@ -356,7 +370,7 @@ func (u *drclusterInstance) clusterFenceHandle() (bool, error) {
 	}
 }
 
-func (u *drclusterInstance) handleDeletion() (bool, error) {
+func (u *drclusterInstance) handleDeletion(ramenConfig *ramen.RamenConfig) (bool, error) {
 	drpolicies, err := util.GetAllDRPolicies(u.ctx, u.reconciler.APIReader)
 	if err != nil {
 		return true, fmt.Errorf("getting all drpolicies failed: %w", err)
@@ -368,10 +382,24 @@ func (u *drclusterInstance) handleDeletion() (bool, error) {
 			u.object.Name, err)
 	}
 
-	return u.cleanClusters([]ramen.DRCluster{*u.object, peerCluster})
+	return u.fencingProvider(ramenConfig).clean([]ramen.DRCluster{*u.object, peerCluster})
 }
 
-func (u *drclusterInstance) clusterFence() (bool, error) {
+func (u *drclusterInstance) clusterFence(ramenConfig *ramen.RamenConfig) (bool, error) {
+	if !u.isFencingOrFenced() {
+		if confirmed, message := fenceConfirmed(u.object, time.Now()); !confirmed {
+			u.log.Info("Cluster fence not confirmed", "reason", message)
+			setDRClusterFenceNotConfirmedCondition(&u.object.Status.Conditions, u.object.Generation, message)
+
+			// the confirmation annotation's update requeues
+			return false, nil
+		}
+
+		// node CIDRs reported once fencing started, or no longer reported,
+		// must change neither the fence nor the unfence
+		u.object.Status.FencedCIDRs = drClusterCIDRsToFence(u.object)
+	}
+
 	// Ideally, here it should collect all the DRClusters available
 	// in the cluster and then match the appropriate peer cluster
 	// out of them by looking at the storage relationships. However,
@@ -392,10 +420,10 @@ func (u *drclusterInstance) clusterFence() (bool, error) {
 			u.object.Name, err)
 	}
 
-	return u.fenceClusterOnCluster(&peerCluster)
+	return u.fencingProvider(ramenConfig).fence(&peerCluster)
 }
 
-func (u *drclusterInstance) clusterUnfence() (bool, error) {
+func (u *drclusterInstance) clusterUnfence(ramenConfig *ramen.RamenConfig) (bool, error) {
 	// Ideally, here it should collect all the DRClusters available
 	// in the cluster and then match the appropriate peer cluster
 	// out of them by looking at the storage relationships. However,
@@ -417,7 +445,9 @@ func (u *drclusterInstance) clusterUnfence() (bool, error) {
 			u.object.Name, err)
 	}
 
-	requeue, err := u.unfenceClusterOnCluster(&peerCluster)
+	provider := u.fencingProvider(ramenConfig)
+
+	requeue, err := provider.unfence(&peerCluster)
 	if err != nil {
 		return requeue, fmt.Errorf("unfence operation to fence off cluster %s on cluster %s failed",
 			u.object.Name, peerCluster.Name)
@@ -429,8 +459,10 @@ func (u *drclusterInstance) clusterUnfence() (bool, error) {
 		return requeue, nil
 	}
 
+	u.object.Status.FencedCIDRs = nil
+
 	// once this cluster is unfenced. Clean the fencing resource.
-	return u.cleanClusters([]ramen.DRCluster{*u.object, peerCluster})
+	return provider.clean([]ramen.DRCluster{*u.object, peerCluster})
 }
 
 //
@@ -461,6 +493,7 @@ func (u *drclusterInstance) fenceClusterOnCluster(peerCluster *ramen.DRCluster)
 
 		setDRClusterFencingCondition(&u.object.Status.Conditions, u.object.Generation,
 			"ManifestWork for NetworkFence fence operation created")
+		u.fenceHistoryStart(ramen.ClusterFenceStateFenced, peerCluster.Name)
 		u.setDRClusterPhase(ramen.Fencing)
 		// just created fencing resource. Requeue and then check.
 		return true, nil
@@ -481,6 +514,7 @@ func (u *drclusterInstance) fenceClusterOnCluster(peerCluster *ramen.DRCluster)
 	}
 
 	if nf.Status.Result != csiaddonsv1alpha1.FencingOperationResultSucceeded {
+		u.fenceHistoryResultSet(networkFenceResult(nf), nf.Status.Message)
 		setDRClusterFencingFailedCondition(&u.object.Status.Conditions, u.object.Generation,
 			"fencing operation not successful")
 
@@ -489,6 +523,7 @@ func (u *drclusterInstance) fenceClusterOnCluster(peerCluster *ramen.DRCluster)
 		return true, fmt.Errorf("fencing operation result not successful")
 	}
 
+	u.fenceHistoryResultSet(FenceOperationResultSucceeded, nf.Status.Message)
 	setDRClusterFencedCondition(&u.object.Status.Conditions, u.object.Generation,
 		"Cluster successfully fenced")
 	u.advanceToNextPhase()
@@ -524,6 +559,7 @@ func (u *drclusterInstance) unfenceClusterOnCluster(peerCluster *ramen.DRCluster
 
 		setDRClusterUnfencingCondition(&u.object.Status.Conditions, u.object.Generation,
 			"ManifestWork for NetworkFence unfence operation created")
+		u.fenceHistoryStart(ramen.ClusterFenceStateUnfenced, peerCluster.Name)
 		u.setDRClusterPhase(ramen.Unfencing)
 
 		// just created NetworkFence resource to unfence. Requeue and then check.
@@ -544,6 +580,7 @@ func (u *drclusterInstance) unfenceClusterOnCluster(peerCluster *ramen.DRCluster
 	}
 
 	if nf.Status.Result != csiaddonsv1alpha1.FencingOperationResultSucceeded {
+		u.fenceHistoryResultSet(networkFenceResult(nf), nf.Status.Message)
 		setDRClusterUnfencingFailedCondition(&u.object.Status.Conditions, u.object.Generation,
 			"unfencing operation not successful")
 
@@ -552,6 +589,7 @@ func (u *drclusterInstance) unfenceClusterOnCluster(peerCluster *ramen.DRCluster
 		return true, fmt.Errorf("un operation result not successful")
 	}
 
+	u.fenceHistoryResultSet(FenceOperationResultSucceeded, nf.Status.Message)
 	setDRClusterUnfencedCondition(&u.object.Status.Conditions, u.object.Generation,
 		"Cluster successfully unfenced")
 	u.advanceToNextPhase()
@@ -559,6 +597,16 @@ func (u *drclusterInstance) unfenceClusterOnCluster(peerCluster *ramen.DRCluster
 	return false, nil
 }
 
+// networkFenceResult returns the result a NetworkFence reports, or that it is
+// in progress if it reports none yet
+func networkFenceResult(nf *csiaddonsv1alpha1.NetworkFence) string {
+	if nf.Status.Result == "" {
+		return FenceOperationResultInProgress
+	}
+
+	return string(nf.Status.Result)
+}
+
 func (u *drclusterInstance) requeueIfNFMWExists(peerCluster *ramen.DRCluster) (bool, error) {
 	nfMWName := u.mwUtil.BuildManifestWorkName(util.MWTypeNF)
 
@@ -1028,7 +1076,7 @@ func (u *drclusterInstance) createNFManifestWork(targetCluster *ramen.DRCluster,
 	log.Info(fmt.Sprintf("Creating NetworkFence ManifestWork on cluster %s to perform fencing op on cluster %s",
 		peerCluster.Name, targetCluster.Name))
 
-	nf, err := generateNF(targetCluster)
+	nf, err := generateNF(targetCluster, peerCluster)
 	if err != nil {
 		return fmt.Errorf("failed to generate network fence resource: %w", err)
 	}
@@ -1049,11 +1097,26 @@ func (u *drclusterInstance) createNFManifestWork(targetCluster *ramen.DRCluster,
 }
 
 // this function fills the storage specific details in the NetworkFence resource.
-// Currently it fills those details based on the annotations that are set on the
-// DRCluster resource. However, in future it can be changed to get the storage
-// specific details (such as driver, parameters, secret etc) from the status of
-// the DRCluster resource.
-func fillStorageDetails(cluster *ramen.DRCluster, nf *csiaddonsv1alpha1.NetworkFence) error {
+// The annotations that an admin set on the DRCluster resource of the fenced
+// cluster take precedence. Without them, the details are those the peer
+// cluster, that the resource is created on, reports in the status of its
+// DRCluster resource. If the peer reports several candidates, the storage
+// driver annotation alone chooses one of them.
+func fillStorageDetails(cluster, peerCluster *ramen.DRCluster, nf *csiaddonsv1alpha1.NetworkFence) error {
+	storageFencing, err := discoveredStorageFencing(cluster, peerCluster)
+	if err != nil {
+		return err
+	}
+
+	if storageFencing != nil {
+		nf.Spec.Secret.Name = storageFencing.SecretName
+		nf.Spec.Secret.Namespace = storageFencing.SecretNamespace
+		nf.Spec.Driver = storageFencing.Driver
+		nf.Spec.Parameters = map[string]string{"clusterID": storageFencing.ClusterID}
+
+		return nil
+	}
+
 	storageDriver, ok := cluster.Annotations[StorageAnnotationDriver]
 	if !ok {
 		return fmt.Errorf("failed to find storage driver in annotations")
@@ -1084,27 +1147,60 @@ func fillStorageDetails(cluster *ramen.DRCluster, nf *csiaddonsv1alpha1.NetworkF
 	return nil
 }
 
-func generateNF(targetCluster *ramen.DRCluster) (csiaddonsv1alpha1.NetworkFence, error) {
+// discoveredStorageFencing returns the storage fencing details, discovered on
+// the peer cluster, to fence off a cluster with, or nil if its annotations are
+// to be used instead
+func discoveredStorageFencing(cluster, peerCluster *ramen.DRCluster) (*ramen.StorageFencing, error) {
+	for _, annotation := range []string{
+		StorageAnnotationSecretName, StorageAnnotationSecretNamespace, StorageAnnotationClusterID,
+	} {
+		if _, ok := cluster.Annotations[annotation]; ok {
+			return nil, nil
+		}
+	}
+
+	storageDriver, ok := cluster.Annotations[StorageAnnotationDriver]
+	if !ok {
+		if len(peerCluster.Status.StorageFencingCandidates) > 0 {
+			return nil, fmt.Errorf("cluster %s reports %d storage fencing candidates, choose the driver of one"+
+				" with the %s annotation", peerCluster.Name, len(peerCluster.Status.StorageFencingCandidates),
+				StorageAnnotationDriver)
+		}
+
+		return peerCluster.Status.StorageFencing, nil
+	}
+
+	candidates := peerCluster.Status.StorageFencingCandidates
+	if peerCluster.Status.StorageFencing != nil {
+		candidates = []ramen.StorageFencing{*peerCluster.Status.StorageFencing}
+	}
+
+	for i := range candidates {
+		if candidates[i].Driver == storageDriver {
+			return &candidates[i], nil
+		}
+	}
+
+	// the driver annotation is then to be completed by the other annotations
+	return nil, nil
+}
+
+func generateNF(targetCluster, peerCluster *ramen.DRCluster) (csiaddonsv1alpha1.NetworkFence, error) {
 	// To ensure deterministic naming of the fencing CR, the resource name
 	// is generated as
 	// "network-fence" + name of the cluster being fenced
 	resourceName := "network-fence-" + targetCluster.Name
 
 	nf := csiaddonsv1alpha1.NetworkFence{
-		// TODO: There is no way currently to get the information such as
-		// the storage CSI driver, secrets and storage specific parameters
-		// until DRCluster is capable of exporting such information in its
-		// status based on its reconciliation. Until then, the fencing CR
-		// would be incomplete and incapable of performing fencing operation.
 		TypeMeta:   metav1.TypeMeta{Kind: "NetworkFence", APIVersion: "csiaddons.openshift.io/v1alpha1"},
 		ObjectMeta: metav1.ObjectMeta{Name: resourceName},
 		Spec: csiaddonsv1alpha1.NetworkFenceSpec{
 			FenceState: csiaddonsv1alpha1.FenceState(targetCluster.Spec.ClusterFence),
-			Cidrs:      targetCluster.Spec.CIDRs,
+			Cidrs:      drClusterFenceCIDRs(targetCluster),
 		},
 	}
 
-	if err := fillStorageDetails(targetCluster, &nf); err != nil {
+	if err := fillStorageDetails(targetCluster, peerCluster, &nf); err != nil {
 		return nf, fmt.Errorf("failed to create network fence resource with storage detai: %w", err)
 	}
 
@@ -17,6 +17,12 @@ package controllers_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
 
 	csiaddonsv1alpha1 "github.com/csi-addons/kubernetes-csi-addons/api/v1alpha1"
 	. "github.com/onsi/ginkgo"
@@ -64,6 +70,49 @@ func (f FakeMCVGetter) DeleteNFManagedClusterView(
 	return nil
 }
 
+var fakeStorageFencing = &ramen.StorageFencing{
+	Driver:          "fake.storage.com",
+	SecretName:      "fake-secret",
+	SecretNamespace: "fake-namespace",
+	ClusterID:       "fake-clusterid",
+}
+
+var fakeNodeCIDRs = []string{"198.51.100.30/32", "198.51.100.31/32"}
+
+// fakeStorageFencingUnreported, when set, has managed clusters not report
+// their storage fencing details and node CIDRs, as if unreachable
+var fakeStorageFencingUnreported = false
+
+func (f FakeMCVGetter) GetConfigMapFromManagedCluster(resourceName, resourceNamespace, managedCluster string,
+	annotations map[string]string) (*corev1.ConfigMap, error) {
+	if resourceName != controllers.StorageFencingConfigMapName || fakeStorageFencingUnreported {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, resourceName)
+	}
+
+	storageFencingCandidates, err := json.Marshal([]ramen.StorageFencing{*fakeStorageFencing})
+	if err != nil {
+		return nil, err
+	}
+
+	nodeCIDRs, err := json.Marshal(fakeNodeCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: resourceNamespace},
+		Data: map[string]string{
+			"storageFencingCandidates": string(storageFencingCandidates),
+			"nodeCIDRs":                string(nodeCIDRs),
+		},
+	}, nil
+}
+
+func (f FakeMCVGetter) DeleteConfigMapManagedClusterView(
+	resourceName, resourceNamespace, clusterName, resourceType string) error {
+	return nil
+}
+
 func drclusterConditionExpectEventually(
 	drcluster *ramen.DRCluster,
 	disabled bool,
@@ -335,6 +384,19 @@ var _ = Describe("DRClusterController", func() {
 		drpolicyDeleteAndConfirm(drpolicy)
 	}
 
+	var (
+		webhook         *httptest.Server
+		webhookMutex    sync.Mutex
+		webhookRequests []controllers.FencingWebhookRequest
+	)
+
+	webhookRequestsGet := func() []controllers.FencingWebhookRequest {
+		webhookMutex.Lock()
+		defer webhookMutex.Unlock()
+
+		return append([]controllers.FencingWebhookRequest{}, webhookRequests...)
+	}
+
 	var drcluster *ramen.DRCluster
 	Specify("DRCluster initialize tests", func() {
 		populateDRClusters()
@@ -384,6 +446,27 @@ var _ = Describe("DRClusterController", func() {
 				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionTrue, Equal("Succeeded"), Ignore(),
 					ramen.DRClusterValidated)
 			})
+			It("reports the storage fencing details of its managed cluster", func() {
+				Eventually(func() *ramen.StorageFencing {
+					current := &ramen.DRCluster{}
+					Expect(apiReader.Get(context.TODO(), types.NamespacedName{Name: drcluster.Name},
+						current)).To(Succeed())
+
+					return current.Status.StorageFencing
+				}, timeout, interval).Should(Equal(fakeStorageFencing))
+			})
+			It("reports the node CIDRs of its managed cluster as within its CIDRs", func() {
+				Eventually(func() []string {
+					current := &ramen.DRCluster{}
+					Expect(apiReader.Get(context.TODO(), types.NamespacedName{Name: drcluster.Name},
+						current)).To(Succeed())
+
+					return current.Status.NodeCIDRs
+				}, timeout, interval).Should(Equal(fakeNodeCIDRs))
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionFalse,
+					Equal(controllers.DRClusterConditionReasonNodeCIDRsCovered), Ignore(),
+					ramen.DRClusterConditionTypeCIDRsOutOfDate)
+			})
 		})
 		When("S3Profile is changed to an invalid profile in ramen config", func() {
 			It("reports NOT validated with reason s3ConnectionFailed", func() {
@@ -513,6 +596,18 @@ var _ = Describe("DRClusterController", func() {
 					Equal(controllers.DRClusterConditionReasonFenced), Ignore(),
 					ramen.DRClusterConditionTypeFenced)
 			})
+			It("fences with its storage annotations rather than the discovered storage details", func() {
+				manifestWork := &workv1.ManifestWork{}
+				Expect(apiReader.Get(context.TODO(), types.NamespacedName{
+					Name: fmt.Sprintf(util.ManifestWorkNameFormat, drcluster.Name, drcluster.Namespace,
+						util.MWTypeNF),
+					Namespace: drclusters[1].Name,
+				}, manifestWork)).To(Succeed())
+				nf := &csiaddonsv1alpha1.NetworkFence{}
+				Expect(json.Unmarshal(manifestWork.Spec.Workload.Manifests[0].Raw, nf)).To(Succeed())
+				Expect(nf.Spec.Driver).To(Equal(drcluster.Annotations[controllers.StorageAnnotationDriver]))
+				Expect(nf.Spec.Driver).ToNot(Equal(fakeStorageFencing.Driver))
+			})
 		})
 		When("provided Fencing value is Unfenced", func() {
 			It("reports Unfenced false with status fenced as false", func() {
@@ -554,6 +649,136 @@ var _ = Describe("DRClusterController", func() {
 					Ignore(), ramen.DRClusterConditionTypeFenced)
 			})
 		})
+		When("provided Fencing value is Fenced with the Webhook fencing provider", func() {
+			It("reports fenced once the webhook fenced the cluster", func() {
+				webhook = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					request := controllers.FencingWebhookRequest{}
+					if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+						w.WriteHeader(http.StatusBadRequest)
+
+						return
+					}
+					webhookMutex.Lock()
+					webhookRequests = append(webhookRequests, request)
+					webhookMutex.Unlock()
+					w.WriteHeader(http.StatusOK)
+				}))
+				drcluster.Spec.ClusterFence = "Fenced"
+				drcluster.Spec.FencingProvider = ramen.FencingProviderWebhook
+				drcluster.Spec.FencingWebhook = &ramen.FencingWebhook{URL: webhook.URL}
+				Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionTrue,
+					Equal(controllers.DRClusterConditionReasonFenced), Ignore(),
+					ramen.DRClusterConditionTypeFenced)
+				Expect(webhookRequestsGet()).To(ContainElement(controllers.FencingWebhookRequest{
+					Cluster: drcluster.Name, Operation: "Fence", CIDRs: drcluster.Spec.CIDRs,
+				}))
+			})
+		})
+		When("its node CIDRs change while fenced", func() {
+			It("keeps the CIDRs it was fenced off with", func() {
+				Expect(drcluster.Status.FencedCIDRs).To(Equal(cidrs[0]))
+				fakeNodeCIDRs = []string{"198.51.100.30/32", "198.51.100.31/32", "203.0.113.10/32"}
+				drcluster.Spec.UseDiscoveredCIDRs = true
+				Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionTrue,
+					Equal(controllers.DRClusterConditionReasonNodeCIDRsNotCovered),
+					ContainSubstring("203.0.113.10/32"), ramen.DRClusterConditionTypeCIDRsOutOfDate)
+				Expect(drcluster.Status.FencedCIDRs).To(Equal(cidrs[0]))
+			})
+			It("keeps the node CIDRs last reported once they are not", func() {
+				fakeStorageFencingUnreported = true
+				drcluster.Spec.CIDRs = append(append([]string{}, cidrs[0]...), "203.0.113.0/24")
+				Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionFalse,
+					Equal(controllers.DRClusterConditionReasonNodeCIDRsCovered), Ignore(),
+					ramen.DRClusterConditionTypeCIDRsOutOfDate)
+				Expect(drcluster.Status.NodeCIDRs).To(ContainElement("203.0.113.10/32"))
+				Expect(drcluster.Status.FencedCIDRs).To(Equal(cidrs[0]))
+			})
+		})
+		When("provided Fencing value is Unfenced with the Webhook fencing provider", func() {
+			It("reports clean once the webhook unfenced the cluster with the CIDRs it was fenced off with", func() {
+				drcluster.Spec.ClusterFence = "Unfenced"
+				Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionFalse,
+					Equal(controllers.DRClusterConditionReasonClean), Ignore(),
+					ramen.DRClusterConditionTypeFenced)
+				Expect(webhookRequestsGet()).To(ContainElement(controllers.FencingWebhookRequest{
+					Cluster: drcluster.Name, Operation: "Unfence", CIDRs: cidrs[0],
+				}))
+				Expect(drcluster.Status.FencedCIDRs).To(BeEmpty())
+				webhook.Close()
+				fakeStorageFencingUnreported = false
+				fakeNodeCIDRs = []string{"198.51.100.30/32", "198.51.100.31/32"}
+				drcluster.Spec.CIDRs = cidrs[0]
+				drcluster.Spec.UseDiscoveredCIDRs = false
+				Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+			})
+		})
+		When("provided Fencing value is Fenced requiring fence confirmation", func() {
+			It("reports not fenced until the fence is confirmed", func() {
+				drcluster.Spec.ClusterFence = "Fenced"
+				drcluster.Spec.FencingProvider = ""
+				drcluster.Spec.FencingWebhook = nil
+				drcluster.Spec.RequireFenceConfirmation = true
+				Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionFalse,
+					Equal(controllers.DRClusterConditionReasonFenceNotConfirmed), Ignore(),
+					ramen.DRClusterConditionTypeFenced)
+			})
+			It("reports not fenced with a confirmation of another generation, or expiring too late", func() {
+				confirmationSet := func(generation int64, until time.Duration) {
+					if drcluster.Annotations == nil {
+						drcluster.Annotations = map[string]string{}
+					}
+					drcluster.Annotations[controllers.FenceConfirmationAnnotation] = fmt.Sprintf("Fenced/%d/%s",
+						generation, time.Now().Add(until).UTC().Format(time.RFC3339))
+					Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+				}
+				confirmationSet(drcluster.Generation-1, 10*time.Minute)
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionFalse,
+					Equal(controllers.DRClusterConditionReasonFenceNotConfirmed), ContainSubstring("does not confirm"),
+					ramen.DRClusterConditionTypeFenced)
+				confirmationSet(drcluster.Generation, 2*time.Hour)
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionFalse,
+					Equal(controllers.DRClusterConditionReasonFenceNotConfirmed), ContainSubstring("later than"),
+					ramen.DRClusterConditionTypeFenced)
+			})
+			It("reports fenced once the fence is confirmed, and records it", func() {
+				drcluster.Annotations[controllers.FenceConfirmationAnnotation] = fmt.Sprintf("Fenced/%d/%s",
+					drcluster.Generation, time.Now().Add(10*time.Minute).UTC().Format(time.RFC3339))
+				Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionTrue,
+					Equal(controllers.DRClusterConditionReasonFenced), Ignore(),
+					ramen.DRClusterConditionTypeFenced)
+				Expect(drcluster.Status.FenceHistory).NotTo(BeEmpty())
+				Expect(drcluster.Status.FenceHistory[len(drcluster.Status.FenceHistory)-1]).To(MatchFields(IgnoreExtras,
+					Fields{
+						"State":          Equal(ramen.ClusterFenceStateFenced),
+						"Provider":       Equal(ramen.FencingProviderNetworkFence),
+						"PeerCluster":    Equal(drclusters[1].Name),
+						"FieldManager":   Not(BeEmpty()),
+						"Result":         Equal(controllers.FenceOperationResultSucceeded),
+						"CompletionTime": Not(BeNil()),
+					}))
+			})
+		})
+		When("provided Fencing value is Unfenced after a confirmed fence", func() {
+			It("reports Unfenced false with status fenced as false, and records it", func() {
+				drcluster.Spec.ClusterFence = "Unfenced"
+				Expect(k8sClient.Update(context.TODO(), drcluster)).To(Succeed())
+				drclusterConditionExpectEventually(drcluster, false, metav1.ConditionFalse,
+					BeElementOf(controllers.DRClusterConditionReasonUnfenced, controllers.DRClusterConditionReasonCleaning,
+						controllers.DRClusterConditionReasonClean),
+					Ignore(), ramen.DRClusterConditionTypeFenced)
+				Expect(drcluster.Status.FenceHistory[len(drcluster.Status.FenceHistory)-1]).To(MatchFields(IgnoreExtras,
+					Fields{
+						"State":  Equal(ramen.ClusterFenceStateUnfenced),
+						"Result": Equal(controllers.FenceOperationResultSucceeded),
+					}))
+			})
+		})
 		When("provided Fencing value is empty", func() {
 			It("reports validated with status fencing as Unfenced", func() {
 				drcluster.Spec.ClusterFence = ""
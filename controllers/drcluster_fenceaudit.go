@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	// FenceConfirmationAnnotation confirms fencing a cluster whose DRCluster
+	// requires fence confirmation. It is set to the ClusterFence state and
+	// the DRCluster generation it confirms, and the RFC 3339 time it confirms
+	// them until, separated by slashes, such as
+	// "Fenced/7/2022-06-01T12:10:00Z".
+	FenceConfirmationAnnotation = "drcluster.ramendr.openshift.io/fence-confirmation"
+
+	// fenceConfirmationLeaseMax is how far in the future a fence confirmation
+	// may expire at most, so that a confirmation left behind cannot confirm
+	// later edits
+	fenceConfirmationLeaseMax = time.Hour
+
+	DRClusterConditionReasonFenceNotConfirmed = "FenceNotConfirmed"
+
+	FenceOperationResultInProgress = "InProgress"
+	FenceOperationResultSucceeded  = "Succeeded"
+	FenceOperationResultFailed     = "Failed"
+
+	fenceHistoryLengthMax = 10
+)
+
+// fenceConfirmed returns whether fencing the cluster of a DRCluster is
+// confirmed at a time, and if not, why not. A confirmation only confirms the
+// ClusterFence state and generation it names, so that it does not confirm a
+// later edit before it expires.
+func fenceConfirmed(drcluster *ramen.DRCluster, now time.Time) (bool, string) {
+	if !drcluster.Spec.RequireFenceConfirmation {
+		return true, ""
+	}
+
+	confirmation := fmt.Sprintf("%s/%d", drcluster.Spec.ClusterFence, drcluster.Generation)
+
+	value, ok := drcluster.Annotations[FenceConfirmationAnnotation]
+	if !ok {
+		return false, fmt.Sprintf("fence requires confirmation with annotation %s=%s/<RFC 3339 time>",
+			FenceConfirmationAnnotation, confirmation)
+	}
+
+	separator := strings.LastIndex(value, "/")
+	if separator == -1 || value[:separator] != confirmation {
+		return false, fmt.Sprintf("fence confirmation %q does not confirm %s", value, confirmation)
+	}
+
+	until, err := time.Parse(time.RFC3339, value[separator+1:])
+	if err != nil {
+		return false, fmt.Sprintf("fence confirmation %q does not end with an RFC 3339 time: %v", value, err)
+	}
+
+	if !now.Before(until) {
+		return false, fmt.Sprintf("fence confirmation expired at %s", value[separator+1:])
+	}
+
+	if until.Sub(now) > fenceConfirmationLeaseMax {
+		return false, fmt.Sprintf("fence confirmation expires at %s, later than %s from now", value[separator+1:],
+			fenceConfirmationLeaseMax)
+	}
+
+	return true, ""
+}
+
+func setDRClusterFenceNotConfirmedCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	message string,
+) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               ramen.DRClusterConditionTypeFenced,
+		Reason:             DRClusterConditionReasonFenceNotConfirmed,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionFalse,
+		Message:            message,
+	})
+}
+
+// fenceHistoryStart records the start of a fence or unfence operation,
+// dropping the oldest operations beyond the history's length
+func (u *drclusterInstance) fenceHistoryStart(state ramen.ClusterFenceState, peerCluster string) {
+	provider := u.object.Spec.FencingProvider
+	if provider == "" {
+		provider = ramen.FencingProviderNetworkFence
+	}
+
+	history := append(u.object.Status.FenceHistory, ramen.FenceOperation{
+		State:        state,
+		Provider:     provider,
+		PeerCluster:  peerCluster,
+		FieldManager: clusterFenceManager(u.object),
+		StartTime:    metav1.Now(),
+		Result:       FenceOperationResultInProgress,
+	})
+
+	if len(history) > fenceHistoryLengthMax {
+		history = history[len(history)-fenceHistoryLengthMax:]
+	}
+
+	u.object.Status.FenceHistory = history
+}
+
+// fenceHistoryResultSet records the result of the latest fence or unfence
+// operation, and when it succeeded, if it did
+func (u *drclusterInstance) fenceHistoryResultSet(result, message string) {
+	history := u.object.Status.FenceHistory
+	if len(history) == 0 {
+		return
+	}
+
+	operation := &history[len(history)-1]
+	if operation.CompletionTime != nil {
+		return
+	}
+
+	operation.Result = result
+	operation.Message = message
+
+	if result == FenceOperationResultSucceeded {
+		now := metav1.Now()
+		operation.CompletionTime = &now
+	}
+}
+
+// clusterFenceManager returns the field manager that last set a DRCluster's
+// ClusterFence, as its managed fields record, or "" if none does
+func clusterFenceManager(drcluster *ramen.DRCluster) string {
+	manager := ""
+
+	var managerTime *metav1.Time
+
+	for _, entry := range drcluster.ManagedFields {
+		if entry.FieldsV1 == nil || entry.Subresource != "" {
+			continue
+		}
+
+		fields := map[string]map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+
+		if _, ok := fields["f:spec"]["f:clusterFence"]; !ok {
+			continue
+		}
+
+		if managerTime == nil || (entry.Time != nil && managerTime.Before(entry.Time)) {
+			manager = entry.Manager
+			managerTime = entry.Time
+		}
+	}
+
+	return manager
+}
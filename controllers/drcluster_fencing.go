@@ -0,0 +1,315 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// fencingProvider fences a cluster off, and unfences it, as its DRCluster's
+// ClusterFence requests. fence and unfence move the DRCluster through the
+// Fencing and Fenced, or Unfencing and Unfenced, phases; clean removes what
+// the provider left on the clusters once unfenced. Each returns whether to
+// requeue.
+type fencingProvider interface {
+	fence(peerCluster *ramen.DRCluster) (bool, error)
+	unfence(peerCluster *ramen.DRCluster) (bool, error)
+	clean(clusters []ramen.DRCluster) (bool, error)
+}
+
+// fencingProvider returns the fencing provider the DRCluster selects
+func (u *drclusterInstance) fencingProvider(ramenConfig *ramen.RamenConfig) fencingProvider {
+	switch u.object.Spec.FencingProvider {
+	case ramen.FencingProviderWebhook:
+		return webhookFencingProvider{u: u}
+	case ramen.FencingProviderNodeCordon:
+		return nodeCordonFencingProvider{u: u, namespaceName: drClusterOperatorNamespaceNameOrDefault(ramenConfig)}
+	case ramen.FencingProviderNetworkFence:
+		fallthrough
+	default:
+		return networkFenceProvider{u: u}
+	}
+}
+
+// networkFenceProvider fences the cluster off its storage with a csi-addons
+// NetworkFence resource created, through a ManifestWork, on the peer cluster
+type networkFenceProvider struct {
+	u *drclusterInstance
+}
+
+func (p networkFenceProvider) fence(peerCluster *ramen.DRCluster) (bool, error) {
+	return p.u.fenceClusterOnCluster(peerCluster)
+}
+
+func (p networkFenceProvider) unfence(peerCluster *ramen.DRCluster) (bool, error) {
+	return p.u.unfenceClusterOnCluster(peerCluster)
+}
+
+func (p networkFenceProvider) clean(clusters []ramen.DRCluster) (bool, error) {
+	return p.u.cleanClusters(clusters)
+}
+
+// fenceOperation fences the cluster with the operation that start starts and
+// completed checks, unless it is already fenced
+func (u *drclusterInstance) fenceOperation(provider string, start func() error,
+	completed func() (bool, error),
+) (bool, error) {
+	if u.getLastDRClusterPhase() == ramen.Fenced {
+		return false, nil
+	}
+
+	if !u.isFencingOrFenced() {
+		u.log.Info(fmt.Sprintf("initiating the cluster fence with provider %s", provider))
+
+		if err := start(); err != nil {
+			setDRClusterFencingFailedCondition(&u.object.Status.Conditions, u.object.Generation,
+				fmt.Sprintf("%s fence operation failed: %v", provider, err))
+
+			return true, fmt.Errorf("failed to start %s fence of cluster %s: %w", provider, u.object.Name, err)
+		}
+
+		setDRClusterFencingCondition(&u.object.Status.Conditions, u.object.Generation,
+			fmt.Sprintf("%s fence operation started", provider))
+		u.fenceHistoryStart(ramen.ClusterFenceStateFenced, "")
+		u.setDRClusterPhase(ramen.Fencing)
+
+		return true, nil
+	}
+
+	done, err := completed()
+	if err != nil {
+		u.fenceHistoryResultSet(FenceOperationResultFailed, err.Error())
+		setDRClusterFencingFailedCondition(&u.object.Status.Conditions, u.object.Generation,
+			fmt.Sprintf("%s fence operation not successful: %v", provider, err))
+
+		return true, fmt.Errorf("%s fence of cluster %s not successful: %w", provider, u.object.Name, err)
+	}
+
+	if !done {
+		return true, nil
+	}
+
+	u.fenceHistoryResultSet(FenceOperationResultSucceeded, "")
+	setDRClusterFencedCondition(&u.object.Status.Conditions, u.object.Generation,
+		"Cluster successfully fenced")
+	u.advanceToNextPhase()
+
+	return false, nil
+}
+
+// unfenceOperation unfences the cluster with the operation that start starts
+// and completed checks, unless it is already unfenced
+func (u *drclusterInstance) unfenceOperation(provider string, start func() error,
+	completed func() (bool, error),
+) (bool, error) {
+	if u.getLastDRClusterPhase() == ramen.Unfenced {
+		return false, nil
+	}
+
+	if !u.isUnfencingOrUnfenced() {
+		u.log.Info(fmt.Sprintf("initiating the cluster unfence with provider %s", provider))
+
+		if err := start(); err != nil {
+			setDRClusterUnfencingFailedCondition(&u.object.Status.Conditions, u.object.Generation,
+				fmt.Sprintf("%s unfence operation failed: %v", provider, err))
+
+			return true, fmt.Errorf("failed to start %s unfence of cluster %s: %w", provider, u.object.Name, err)
+		}
+
+		setDRClusterUnfencingCondition(&u.object.Status.Conditions, u.object.Generation,
+			fmt.Sprintf("%s unfence operation started", provider))
+		u.fenceHistoryStart(ramen.ClusterFenceStateUnfenced, "")
+		u.setDRClusterPhase(ramen.Unfencing)
+
+		return true, nil
+	}
+
+	done, err := completed()
+	if err != nil {
+		u.fenceHistoryResultSet(FenceOperationResultFailed, err.Error())
+		setDRClusterUnfencingFailedCondition(&u.object.Status.Conditions, u.object.Generation,
+			fmt.Sprintf("%s unfence operation not successful: %v", provider, err))
+
+		return true, fmt.Errorf("%s unfence of cluster %s not successful: %w", provider, u.object.Name, err)
+	}
+
+	if !done {
+		return true, nil
+	}
+
+	u.fenceHistoryResultSet(FenceOperationResultSucceeded, "")
+	setDRClusterUnfencedCondition(&u.object.Status.Conditions, u.object.Generation,
+		"Cluster successfully unfenced")
+	u.advanceToNextPhase()
+
+	return false, nil
+}
+
+const (
+	fencingWebhookOperationFence   = "Fence"
+	fencingWebhookOperationUnfence = "Unfence"
+
+	fencingWebhookSecretKeyToken = "token"
+	fencingWebhookSecretKeyCA    = "ca.crt"
+
+	fencingWebhookTimeout = 30 * time.Second
+)
+
+// FencingWebhookRequest is the body of the requests POSTed to a fencing
+// webhook
+type FencingWebhookRequest struct {
+	Cluster   string   `json:"cluster"`
+	Operation string   `json:"operation"`
+	CIDRs     []string `json:"cidrs,omitempty"`
+}
+
+// webhookFencingProvider requests an out-of-band fencing endpoint to fence
+// the cluster. The endpoint responds 202 Accepted while an operation is in
+// progress, and 200 OK once it completed; requests are repeated until then,
+// so the endpoint must handle them idempotently.
+type webhookFencingProvider struct {
+	u *drclusterInstance
+}
+
+func (p webhookFencingProvider) fence(peerCluster *ramen.DRCluster) (bool, error) {
+	return p.u.fenceOperation(string(ramen.FencingProviderWebhook),
+		func() error {
+			_, err := p.request(fencingWebhookOperationFence)
+
+			return err
+		},
+		func() (bool, error) { return p.request(fencingWebhookOperationFence) },
+	)
+}
+
+func (p webhookFencingProvider) unfence(peerCluster *ramen.DRCluster) (bool, error) {
+	return p.u.unfenceOperation(string(ramen.FencingProviderWebhook),
+		func() error {
+			_, err := p.request(fencingWebhookOperationUnfence)
+
+			return err
+		},
+		func() (bool, error) { return p.request(fencingWebhookOperationUnfence) },
+	)
+}
+
+// clean has nothing to remove, as the endpoint keeps no resource on the
+// clusters
+func (p webhookFencingProvider) clean(clusters []ramen.DRCluster) (bool, error) {
+	setDRClusterCleanCondition(&p.u.object.Status.Conditions, p.u.object.Generation,
+		"no fencing resource to clean from clusters")
+
+	return false, nil
+}
+
+// request POSTs an operation to the webhook, and returns whether it completed
+func (p webhookFencingProvider) request(operation string) (bool, error) {
+	webhook := p.u.object.Spec.FencingWebhook
+	if webhook == nil || webhook.URL == "" {
+		return false, fmt.Errorf("fencing webhook URL not specified")
+	}
+
+	body, err := json.Marshal(FencingWebhookRequest{
+		Cluster:   p.u.object.Name,
+		Operation: operation,
+		CIDRs:     drClusterFenceCIDRs(p.u.object),
+	})
+	if err != nil {
+		return false, fmt.Errorf("fencing webhook request marshal: %w", err)
+	}
+
+	httpClient, token, err := p.httpClient(webhook)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(p.u.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("fencing webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("fencing webhook %s request: %w", operation, err)
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusAccepted:
+		p.u.log.Info("Fencing webhook operation in progress", "operation", operation)
+
+		return false, nil
+	default:
+		const maxMessageLength = 1024
+
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxMessageLength))
+
+		return false, fmt.Errorf("fencing webhook %s request: %s: %s", operation, resp.Status, message)
+	}
+}
+
+// httpClient returns the client to request the webhook with, and the bearer
+// token to request it with, if any, from the webhook's secret
+func (p webhookFencingProvider) httpClient(webhook *ramen.FencingWebhook) (*http.Client, string, error) {
+	httpClient := &http.Client{Timeout: fencingWebhookTimeout}
+
+	if webhook.SecretName == "" {
+		return httpClient, "", nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: NamespaceName(), Name: webhook.SecretName}
+
+	if err := p.u.reconciler.APIReader.Get(p.u.ctx, key, secret); err != nil {
+		return nil, "", fmt.Errorf("fencing webhook secret %s get: %w", key, err)
+	}
+
+	if ca, ok := secret.Data[fencingWebhookSecretKeyCA]; ok {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(ca) {
+			return nil, "", fmt.Errorf("fencing webhook secret %s: no certificate in %s", key,
+				fencingWebhookSecretKeyCA)
+		}
+
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12},
+		}
+	}
+
+	return httpClient, string(secret.Data[fencingWebhookSecretKeyToken]), nil
+}
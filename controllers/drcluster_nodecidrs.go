@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	DRClusterConditionReasonNodeCIDRsCovered     = "NodeCIDRsCovered"
+	DRClusterConditionReasonNodeCIDRsNotCovered  = "NodeCIDRsNotCovered"
+	DRClusterConditionReasonNodeCIDRsNotReported = "NodeCIDRsNotReported"
+)
+
+// discoverNodeCIDRs returns, sorted, the single address CIDRs of the
+// InternalIP addresses of nodes
+func discoverNodeCIDRs(nodes []corev1.Node) []string {
+	cidrs := sets.NewString()
+
+	for i := range nodes {
+		for _, address := range nodes[i].Status.Addresses {
+			if address.Type != corev1.NodeInternalIP {
+				continue
+			}
+
+			ip := net.ParseIP(address.Address)
+			if ip == nil {
+				continue
+			}
+
+			if ip.To4() != nil {
+				cidrs.Insert(ip.String() + "/32")
+			} else {
+				cidrs.Insert(ip.String() + "/128")
+			}
+		}
+	}
+
+	return cidrs.List()
+}
+
+// drClusterCIDRsUncovered returns, in order, the node CIDRs whose address is
+// not within any of the CIDRs. CIDRs that fail to parse cover nothing.
+func drClusterCIDRsUncovered(cidrs, nodeCIDRs []string) []string {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			networks = append(networks, network)
+		}
+	}
+
+	uncovered := []string{}
+
+	for _, nodeCIDR := range nodeCIDRs {
+		ip, _, err := net.ParseCIDR(nodeCIDR)
+		if err != nil {
+			continue
+		}
+
+		covered := false
+
+		for _, network := range networks {
+			if network.Contains(ip) {
+				covered = true
+
+				break
+			}
+		}
+
+		if !covered {
+			uncovered = append(uncovered, nodeCIDR)
+		}
+	}
+
+	return uncovered
+}
+
+// drClusterFenceCIDRs returns the CIDRs a cluster is fenced off, or unfenced,
+// with: those frozen when its fencing started, if any, or else those to fence
+// it off with now
+func drClusterFenceCIDRs(drcluster *ramen.DRCluster) []string {
+	if len(drcluster.Status.FencedCIDRs) > 0 {
+		return drcluster.Status.FencedCIDRs
+	}
+
+	return drClusterCIDRsToFence(drcluster)
+}
+
+// drClusterCIDRsToFence returns the CIDRs to fence a cluster off with: its
+// spec CIDRs and, if it uses them, its discovered node CIDRs they do not cover
+func drClusterCIDRsToFence(drcluster *ramen.DRCluster) []string {
+	if !drcluster.Spec.UseDiscoveredCIDRs {
+		return drcluster.Spec.CIDRs
+	}
+
+	uncovered := drClusterCIDRsUncovered(drcluster.Spec.CIDRs, drcluster.Status.NodeCIDRs)
+	cidrs := make([]string, 0, len(drcluster.Spec.CIDRs)+len(uncovered))
+
+	return append(append(cidrs, drcluster.Spec.CIDRs...), uncovered...)
+}
+
+// cidrsOutOfDateConditionSet sets whether some node CIDRs reported by the
+// managed cluster are not within its DRCluster's spec CIDRs
+func (u *drclusterInstance) cidrsOutOfDateConditionSet() {
+	condition := metav1.Condition{
+		Type:               ramen.DRClusterConditionTypeCIDRsOutOfDate,
+		ObservedGeneration: u.object.Generation,
+	}
+
+	uncovered := drClusterCIDRsUncovered(u.object.Spec.CIDRs, u.object.Status.NodeCIDRs)
+
+	switch {
+	case len(u.object.Status.NodeCIDRs) == 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = DRClusterConditionReasonNodeCIDRsNotReported
+		condition.Message = "Node CIDRs not reported by the managed cluster"
+	case len(uncovered) > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = DRClusterConditionReasonNodeCIDRsNotCovered
+		condition.Message = fmt.Sprintf("Node CIDRs not within CIDRs: %s", strings.Join(uncovered, ", "))
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = DRClusterConditionReasonNodeCIDRsCovered
+		condition.Message = "Node CIDRs within CIDRs"
+	}
+
+	setStatusCondition(&u.object.Status.Conditions, condition)
+}
+
+// nodeAddressesChangedPredicate filters out the node updates that leave
+// their addresses unchanged, such as heartbeats
+func nodeAddressesChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+
+			return !reflect.DeepEqual(oldNode.Status.Addresses, newNode.Status.Addresses)
+		},
+	}
+}
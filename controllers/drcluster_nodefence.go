@@ -0,0 +1,541 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// NodeFenceConfigMapName is the name of the config map, in the dr-cluster
+	// operator's namespace, that the hub creates to request the operator to
+	// fence or unfence the nodes of its managed cluster
+	NodeFenceConfigMapName = "ramen-dr-cluster-node-fence"
+
+	// NodeFenceStatusConfigMapName is the name of the config map, in the
+	// dr-cluster operator's namespace, that reports to the hub the state the
+	// nodes of its managed cluster were last fenced or unfenced to
+	NodeFenceStatusConfigMapName = "ramen-dr-cluster-node-fence-status"
+
+	nodeFenceConfigMapKeyState = "state"
+	nodeFenceConfigMapKeyNodes = "nodes"
+	nodeFenceConfigMapKeyError = "error"
+
+	// NodeFenceTaintKey is the key of the taint that keeps workloads from
+	// being scheduled on fenced nodes
+	NodeFenceTaintKey = "ramendr.openshift.io/fenced"
+
+	// nodeFenceEvictionRequeueDelay is how long to wait for the evicted pods
+	// of the protected applications to terminate before checking them again
+	nodeFenceEvictionRequeueDelay = 5 * time.Second
+
+	// nodeFenceEvictionTimeoutDefault is how long the pods of the protected
+	// applications have to terminate before fencing fails
+	nodeFenceEvictionTimeoutDefault = 5 * time.Minute
+
+	// nodeFenceCordonedAnnotation marks the nodes that fencing cordoned, so
+	// that unfencing leaves those cordoned otherwise as they were
+	nodeFenceCordonedAnnotation = "ramendr.openshift.io/fence-cordoned"
+)
+
+// nodeCordonFencingProvider cordons and taints the nodes of the cluster, and
+// evicts the pods of its protected applications. The hub requests the
+// cluster's dr-cluster operator to do so with a config map, and waits for the
+// operator to report it did. As it is the fenced cluster that fences itself,
+// it can only fence a cluster that is reachable, unlike the NetworkFence and
+// Webhook providers.
+type nodeCordonFencingProvider struct {
+	u             *drclusterInstance
+	namespaceName string
+}
+
+func (p nodeCordonFencingProvider) fence(peerCluster *ramen.DRCluster) (bool, error) {
+	return p.u.fenceOperation(string(ramen.FencingProviderNodeCordon),
+		func() error { return p.request(ramen.ClusterFenceStateFenced) },
+		func() (bool, error) { return p.reported(ramen.ClusterFenceStateFenced) },
+	)
+}
+
+func (p nodeCordonFencingProvider) unfence(peerCluster *ramen.DRCluster) (bool, error) {
+	return p.u.unfenceOperation(string(ramen.FencingProviderNodeCordon),
+		func() error { return p.request(ramen.ClusterFenceStateUnfenced) },
+		func() (bool, error) { return p.reported(ramen.ClusterFenceStateUnfenced) },
+	)
+}
+
+// clean deletes the request, and then, once the dr-cluster operator deleted
+// its report, the view of the report. Only the cluster itself has either.
+func (p nodeCordonFencingProvider) clean(clusters []ramen.DRCluster) (bool, error) {
+	u := p.u
+
+	if err := u.mwUtil.DeleteManifestWork(u.mwUtil.BuildManifestWorkName(util.MWTypeNodeFence),
+		u.object.Name); err != nil {
+		return true, fmt.Errorf("failed to delete node fence request from cluster %s: %w", u.object.Name, err)
+	}
+
+	_, err := u.reconciler.MCVGetter.GetConfigMapFromManagedCluster(NodeFenceStatusConfigMapName,
+		p.namespaceName, u.object.Name, map[string]string{DRClusterNameAnnotation: u.object.Name})
+	if err == nil {
+		setDRClusterCleaningCondition(&u.object.Status.Conditions, u.object.Generation,
+			"node fence resource clean started")
+
+		return true, nil
+	}
+
+	if !k8serrors.IsNotFound(err) {
+		return true, fmt.Errorf("failed to get node fence status from cluster %s: %w", u.object.Name, err)
+	}
+
+	if err := u.reconciler.MCVGetter.DeleteConfigMapManagedClusterView(NodeFenceStatusConfigMapName,
+		p.namespaceName, u.object.Name, util.MWTypeCM); err != nil {
+		return true, fmt.Errorf("failed to delete node fence status view: %w", err)
+	}
+
+	setDRClusterCleanCondition(&u.object.Status.Conditions, u.object.Generation,
+		"fencing resource cleaned from cluster")
+
+	return false, nil
+}
+
+// request creates or updates the ManifestWork of the config map requesting
+// the cluster's nodes be fenced or unfenced
+func (p nodeCordonFencingProvider) request(state ramen.ClusterFenceState) error {
+	configMap := corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: NodeFenceConfigMapName, Namespace: p.namespaceName},
+		Data:       map[string]string{nodeFenceConfigMapKeyState: string(state)},
+	}
+
+	return p.u.mwUtil.CreateOrUpdateNodeFenceManifestWork(p.u.object.Name, p.u.object.Namespace,
+		p.u.object.Name, configMap, map[string]string{DRClusterNameAnnotation: p.u.object.Name})
+}
+
+// reported returns whether the cluster's dr-cluster operator reports its
+// nodes were fenced or unfenced to state, or the error it reports failing to
+func (p nodeCordonFencingProvider) reported(state ramen.ClusterFenceState) (bool, error) {
+	configMap, err := p.u.reconciler.MCVGetter.GetConfigMapFromManagedCluster(NodeFenceStatusConfigMapName,
+		p.namespaceName, p.u.object.Name, map[string]string{DRClusterNameAnnotation: p.u.object.Name})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("node fence status get: %w", err)
+	}
+
+	if configMap.Data[nodeFenceConfigMapKeyState] != string(state) {
+		return false, nil
+	}
+
+	if message := configMap.Data[nodeFenceConfigMapKeyError]; message != "" {
+		return false, fmt.Errorf("node fence failed: %s", message)
+	}
+
+	return true, nil
+}
+
+// NodeFenceReconciler fences or unfences, on a managed cluster, its nodes as
+// the hub requests, and reports the state it did so to
+type NodeFenceReconciler struct {
+	client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+
+	// EvictionTimeout is how long the pods of the protected applications have
+	// to terminate before fencing fails. Defaults to 5m.
+	EvictionTimeout time.Duration
+
+	// evictionStartTime is when the protected pods were first found running
+	// on fenced nodes, or zero if they were not
+	evictionStartTime time.Time
+}
+
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=list;delete
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=volumereplicationgroups,verbs=list
+
+// Reconcile fences or unfences the nodes as the request config map says,
+// whichever node or config map changed, and reports it did. Fencing leaves
+// the control plane and infrastructure nodes as they are, and reports fenced
+// only once the pods of the protected applications are gone. It reports a
+// failure instead while any of those pods runs on a node it left as it is, or
+// remains after the eviction timeout, and keeps checking them until they are
+// gone. Once the request is deleted, it deletes the report and leaves the
+// nodes as they are.
+func (r *NodeFenceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("name", req.NamespacedName.Name)
+
+	request := &corev1.ConfigMap{}
+	if err := r.APIReader.Get(ctx, nodeFenceConfigMapKey(), request); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("node fence request get: %w", err)
+		}
+
+		return ctrl.Result{}, r.nodeFenceReportDelete(ctx)
+	}
+
+	state := ramen.ClusterFenceState(request.Data[nodeFenceConfigMapKeyState])
+	if state != ramen.ClusterFenceStateFenced && state != ramen.ClusterFenceStateUnfenced {
+		log.Info("Node fence request state unsupported", "state", state)
+
+		return ctrl.Result{}, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.APIReader.List(ctx, nodes); err != nil {
+		return ctrl.Result{}, fmt.Errorf("nodes list: %w", err)
+	}
+
+	fenced := state == ramen.ClusterFenceStateFenced
+	nodeCount := 0
+	exemptNodeNames := sets.NewString()
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if fenced && nodeFenceExempt(node) {
+			exemptNodeNames.Insert(node.Name)
+
+			continue
+		}
+
+		nodeCount++
+
+		if !nodeFenceUpdate(node, fenced) {
+			continue
+		}
+
+		log.Info("Node fence state updated", "node", node.Name, "state", state)
+
+		if err := r.Client.Update(ctx, node); err != nil {
+			return ctrl.Result{}, fmt.Errorf("node %s update: %w", node.Name, err)
+		}
+	}
+
+	if fenced {
+		evicting, exempt, err := r.protectedPodsEvict(ctx, exemptNodeNames, log)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if message := r.evictionFailure(evicting, exempt); message != "" {
+			log.Info("Node fence failed", "message", message)
+
+			return ctrl.Result{RequeueAfter: nodeFenceEvictionRequeueDelay},
+				r.nodeFenceReport(ctx, state, nodeCount, message)
+		}
+
+		if len(evicting) != 0 {
+			return ctrl.Result{RequeueAfter: nodeFenceEvictionRequeueDelay}, nil
+		}
+	}
+
+	r.evictionStartTime = time.Time{}
+
+	return ctrl.Result{}, r.nodeFenceReport(ctx, state, nodeCount, "")
+}
+
+// evictionFailure returns why fencing fails, given the protected pods being
+// evicted and those running on exempt nodes, or an empty string if it does
+// not. An exempt node is not tainted, lest the cluster's own workloads not be
+// scheduled, so it may run, or reschedule, a protected pod, which fencing
+// would not stop from writing to its volumes.
+func (r *NodeFenceReconciler) evictionFailure(evicting, exempt []string) string {
+	if len(exempt) != 0 {
+		return fmt.Sprintf("protected pods run on control plane or infrastructure nodes, which are not fenced: %s",
+			strings.Join(exempt, ", "))
+	}
+
+	if len(evicting) == 0 {
+		return ""
+	}
+
+	if r.evictionStartTime.IsZero() {
+		r.evictionStartTime = time.Now()
+	}
+
+	timeout := r.EvictionTimeout
+	if timeout == 0 {
+		timeout = nodeFenceEvictionTimeoutDefault
+	}
+
+	if time.Since(r.evictionStartTime) < timeout {
+		return ""
+	}
+
+	return fmt.Sprintf("protected pods not terminated within %s of their eviction: %s", timeout,
+		strings.Join(evicting, ", "))
+}
+
+// nodeFenceExempt returns whether fencing leaves a node as it is: a control
+// plane or infrastructure node keeps running the cluster and its operators,
+// including the dr-cluster operator that unfences the other nodes
+func nodeFenceExempt(node *corev1.Node) bool {
+	for _, label := range []string{
+		"node-role.kubernetes.io/control-plane",
+		"node-role.kubernetes.io/master",
+		"node-role.kubernetes.io/infra",
+	} {
+		if _, ok := node.Labels[label]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// protectedPodsEvict deletes the pods that mount a PVC protected by a VRG,
+// and returns those that remain, and those that run on exempt nodes, which it
+// leaves as they are as they would be rescheduled there. Fencing must not wait
+// for their disruption budgets, so they are deleted rather than evicted
+// through the eviction API. The fenced nodes no longer schedule their
+// replacements, which are left pending, not running on any node.
+func (r *NodeFenceReconciler) protectedPodsEvict(ctx context.Context, exemptNodeNames sets.String,
+	log logr.Logger,
+) ([]string, []string, error) {
+	vrgs := &ramen.VolumeReplicationGroupList{}
+	if err := r.APIReader.List(ctx, vrgs); err != nil {
+		return nil, nil, fmt.Errorf("volume replication groups list: %w", err)
+	}
+
+	evicting := []string{}
+	exempt := []string{}
+
+	for i := range vrgs.Items {
+		vrg := &vrgs.Items[i]
+
+		pvcNames := sets.NewString()
+		for _, protectedPVC := range vrg.Status.ProtectedPVCs {
+			pvcNames.Insert(protectedPVC.Name)
+		}
+
+		pods := &corev1.PodList{}
+		if err := r.APIReader.List(ctx, pods, client.InNamespace(vrg.Namespace)); err != nil {
+			return nil, nil, fmt.Errorf("pods list in namespace %s: %w", vrg.Namespace, err)
+		}
+
+		for j := range pods.Items {
+			pod := &pods.Items[j]
+			if pod.Spec.NodeName == "" || !podMountsPVC(pod, pvcNames) {
+				continue
+			}
+
+			podName := pod.Namespace + "/" + pod.Name
+
+			if exemptNodeNames.Has(pod.Spec.NodeName) {
+				exempt = append(exempt, podName)
+
+				continue
+			}
+
+			evicting = append(evicting, podName)
+
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+
+			log.Info("Protected application pod evicted", "namespace", pod.Namespace, "pod", pod.Name)
+
+			if err := r.Client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+				return nil, nil, fmt.Errorf("pod %s/%s delete: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+	}
+
+	return evicting, exempt, nil
+}
+
+func podMountsPVC(pod *corev1.Pod, pvcNames sets.String) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && pvcNames.Has(volume.PersistentVolumeClaim.ClaimName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeFenceUpdate cordons a node and taints it so that no workload is
+// scheduled on it if fenced, or else reverts that, and returns whether it
+// changed the node
+func nodeFenceUpdate(node *corev1.Node, fenced bool) bool {
+	taintIndex := -1
+
+	for i := range node.Spec.Taints {
+		if node.Spec.Taints[i].Key == NodeFenceTaintKey {
+			taintIndex = i
+
+			break
+		}
+	}
+
+	_, cordoned := node.Annotations[nodeFenceCordonedAnnotation]
+	changed := false
+
+	if fenced {
+		if taintIndex == -1 {
+			node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+				Key:    NodeFenceTaintKey,
+				Effect: corev1.TaintEffectNoSchedule,
+			})
+			changed = true
+		}
+
+		if !node.Spec.Unschedulable {
+			node.Spec.Unschedulable = true
+
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+
+			node.Annotations[nodeFenceCordonedAnnotation] = ""
+			changed = true
+		}
+
+		return changed
+	}
+
+	if taintIndex != -1 {
+		node.Spec.Taints = append(node.Spec.Taints[:taintIndex], node.Spec.Taints[taintIndex+1:]...)
+		changed = true
+	}
+
+	if cordoned {
+		node.Spec.Unschedulable = false
+
+		delete(node.Annotations, nodeFenceCordonedAnnotation)
+
+		changed = true
+	}
+
+	return changed
+}
+
+func nodeFenceConfigMapKey() types.NamespacedName {
+	return types.NamespacedName{Namespace: NamespaceName(), Name: NodeFenceConfigMapName}
+}
+
+// nodeFenceReport creates or updates the config map reporting the state the
+// nodes were fenced or unfenced to, or failed to be with the error message
+func (r *NodeFenceReconciler) nodeFenceReport(ctx context.Context, state ramen.ClusterFenceState,
+	nodeCount int, message string,
+) error {
+	desired := map[string]string{
+		nodeFenceConfigMapKeyState: string(state),
+		nodeFenceConfigMapKeyNodes: strconv.Itoa(nodeCount),
+	}
+
+	if message != "" {
+		desired[nodeFenceConfigMapKeyError] = message
+	}
+
+	key := types.NamespacedName{Namespace: NamespaceName(), Name: NodeFenceStatusConfigMapName}
+	configMap := &corev1.ConfigMap{}
+
+	if err := r.APIReader.Get(ctx, key, configMap); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("config map %s get: %w", key, err)
+		}
+
+		return r.Client.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data:       desired,
+		})
+	}
+
+	if reflect.DeepEqual(configMap.Data, desired) {
+		return nil
+	}
+
+	configMap.Data = desired
+
+	return r.Client.Update(ctx, configMap)
+}
+
+func (r *NodeFenceReconciler) nodeFenceReportDelete(ctx context.Context) error {
+	return client.IgnoreNotFound(r.Client.Delete(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: NamespaceName(), Name: NodeFenceStatusConfigMapName},
+	}))
+}
+
+// nodeFenceChangedPredicate filters in node creations, and the node updates
+// that change their schedulability or taints, which fencing may have to undo
+func nodeFenceChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+
+			return oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+				!reflect.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeFenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("nodefence").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetNamespace() == NamespaceName() && o.GetName() == NodeFenceConfigMapName
+		}))).
+		Watches(&source.Kind{Type: &corev1.Node{}},
+			handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: nodeFenceConfigMapKey()}}
+			}),
+			builder.WithPredicates(nodeFenceChangedPredicate())).
+		Complete(r)
+}
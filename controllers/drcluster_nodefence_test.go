@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("NodeFenceReconciler", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *controllers.NodeFenceReconciler
+	)
+
+	otherTaint := corev1.Taint{Key: "other", Effect: corev1.TaintEffectNoSchedule}
+	fenceTaint := corev1.Taint{Key: controllers.NodeFenceTaintKey, Effect: corev1.TaintEffectNoSchedule}
+
+	node := func(name string, unschedulable bool, labels map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable, Taints: []corev1.Taint{otherTaint}},
+		}
+	}
+
+	nodeGet := func(name string) *corev1.Node {
+		n := &corev1.Node{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name}, n)).To(Succeed())
+
+		return n
+	}
+
+	pod := func(namespace, name, claimName, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Volumes: []corev1.Volume{{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+					},
+				}},
+			},
+		}
+	}
+
+	podExists := func(namespace, name string) bool {
+		err := fakeClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &corev1.Pod{})
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+
+		Expect(err).ToNot(HaveOccurred())
+
+		return true
+	}
+
+	request := func(state ramen.ClusterFenceState) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: controllers.NamespaceName(),
+				Name:      controllers.NodeFenceConfigMapName,
+			},
+			Data: map[string]string{"state": string(state)},
+		}
+
+		err := fakeClient.Update(ctx, configMap)
+		if k8serrors.IsNotFound(err) {
+			err = fakeClient.Create(ctx, configMap)
+		}
+
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	reconcile := func() ctrl.Result {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: controllers.NamespaceName(), Name: controllers.NodeFenceConfigMapName,
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		return result
+	}
+
+	report := func() map[string]string {
+		configMap := &corev1.ConfigMap{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{
+			Namespace: controllers.NamespaceName(), Name: controllers.NodeFenceStatusConfigMapName,
+		}, configMap); k8serrors.IsNotFound(err) {
+			return nil
+		}
+
+		return configMap.Data
+	}
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(ramen.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			node("worker-1", false, nil),
+			node("worker-2", true, nil),
+			node("control-plane-1", false, map[string]string{"node-role.kubernetes.io/control-plane": ""}),
+			node("infra-1", false, map[string]string{"node-role.kubernetes.io/infra": ""}),
+			&ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "app"},
+				Status: ramen.VolumeReplicationGroupStatus{
+					ProtectedPVCs: []ramen.ProtectedPVC{{Name: "data"}},
+				},
+			},
+			pod("app", "protected", "data", "worker-1"),
+			pod("app", "pending", "data", ""),
+			pod("app", "unprotected", "scratch", "worker-1"),
+			pod("other", "other", "data", "worker-1"),
+		).Build()
+		reconciler = &controllers.NodeFenceReconciler{
+			Client:    fakeClient,
+			APIReader: fakeClient,
+			Log:       ctrl.Log.WithName("NodeFenceReconcilerTest"),
+		}
+	})
+
+	It("fences the nodes other than control plane and infrastructure ones, and evicts protected pods", func() {
+		request(ramen.ClusterFenceStateFenced)
+
+		By("reporting fenced only once the protected pods are gone")
+		Expect(reconcile().RequeueAfter).ToNot(BeZero())
+		Expect(report()).To(BeNil())
+		Expect(podExists("app", "protected")).To(BeFalse())
+		Expect(podExists("app", "pending")).To(BeTrue())
+		Expect(podExists("app", "unprotected")).To(BeTrue())
+		Expect(podExists("other", "other")).To(BeTrue())
+
+		for _, name := range []string{"worker-1", "worker-2"} {
+			n := nodeGet(name)
+			Expect(n.Spec.Unschedulable).To(BeTrue())
+			Expect(n.Spec.Taints).To(ConsistOf(otherTaint, fenceTaint))
+		}
+
+		for _, name := range []string{"control-plane-1", "infra-1"} {
+			n := nodeGet(name)
+			Expect(n.Spec.Unschedulable).To(BeFalse())
+			Expect(n.Spec.Taints).To(ConsistOf(otherTaint))
+		}
+
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		Expect(report()).To(Equal(map[string]string{"state": "Fenced", "nodes": "2"}))
+
+		By("leaving cordoned, once unfenced, the nodes cordoned before fencing")
+		request(ramen.ClusterFenceStateUnfenced)
+		reconcile()
+		Expect(report()).To(HaveKeyWithValue("state", "Unfenced"))
+		Expect(nodeGet("worker-1").Spec.Unschedulable).To(BeFalse())
+		Expect(nodeGet("worker-2").Spec.Unschedulable).To(BeTrue())
+
+		for _, name := range []string{"worker-1", "worker-2"} {
+			n := nodeGet(name)
+			Expect(n.Spec.Taints).To(ConsistOf(otherTaint))
+			Expect(n.Annotations).To(BeEmpty())
+		}
+	})
+
+	It("fails the fence while a protected pod runs on a control plane or infrastructure node", func() {
+		Expect(fakeClient.Create(ctx, pod("app", "on-infra", "data", "infra-1"))).To(Succeed())
+		request(ramen.ClusterFenceStateFenced)
+
+		Expect(reconcile().RequeueAfter).ToNot(BeZero())
+		Expect(report()).To(HaveKeyWithValue("state", "Fenced"))
+		Expect(report()).To(HaveKeyWithValue("error", ContainSubstring("app/on-infra")))
+		Expect(podExists("app", "on-infra")).To(BeTrue())
+
+		By("reporting fenced once the pod is gone")
+		Expect(fakeClient.Delete(ctx, pod("app", "on-infra", "data", "infra-1"))).To(Succeed())
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		Expect(report()).To(Equal(map[string]string{"state": "Fenced", "nodes": "2"}))
+	})
+
+	It("fails the fence if a protected pod is not terminated within the eviction timeout", func() {
+		stuck := pod("app", "stuck", "data", "worker-2")
+		stuck.Finalizers = []string{"test/stuck"}
+		Expect(fakeClient.Create(ctx, stuck)).To(Succeed())
+		reconciler.EvictionTimeout = time.Nanosecond
+		request(ramen.ClusterFenceStateFenced)
+
+		Expect(reconcile().RequeueAfter).ToNot(BeZero())
+		Expect(report()).To(HaveKeyWithValue("error", ContainSubstring("not terminated within 1ns")))
+		Expect(report()).To(HaveKeyWithValue("error", ContainSubstring("app/stuck")))
+
+		By("reporting fenced once the pod terminated")
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "app", Name: "stuck"}, stuck)).To(Succeed())
+		stuck.Finalizers = nil
+		Expect(fakeClient.Update(ctx, stuck)).To(Succeed())
+		Expect(client.IgnoreNotFound(fakeClient.Delete(ctx, stuck))).To(Succeed())
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		Expect(report()).To(Equal(map[string]string{"state": "Fenced", "nodes": "2"}))
+	})
+})
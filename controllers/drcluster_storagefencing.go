@@ -0,0 +1,257 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	// StorageFencingConfigMapName is the name of the config map, in the
+	// dr-cluster operator's namespace, that reports the storage fencing details
+	// and node CIDRs of its managed cluster to the hub
+	StorageFencingConfigMapName          = "ramen-dr-cluster-storage-fencing"
+	storageFencingCandidatesConfigMapKey = "storageFencingCandidates"
+	nodeCIDRsConfigMapKey                = "nodeCIDRs"
+
+	storageClassParameterClusterID       = "clusterID"
+	storageClassParameterSecretName      = "csi.storage.k8s.io/provisioner-secret-name"
+	storageClassParameterSecretNamespace = "csi.storage.k8s.io/provisioner-secret-namespace"
+)
+
+// StorageFencingReconciler reports, on a managed cluster, the storage fencing
+// details discovered from its storage classes, and the CIDRs of its nodes
+type StorageFencingReconciler struct {
+	client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+}
+
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=configmaps,verbs=get;create;update
+
+// Reconcile discovers the storage fencing details and node CIDRs of the
+// managed cluster, whichever storage class or node changed, and reports them
+// in a config map
+func (r *StorageFencingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("name", req.NamespacedName.Name)
+
+	storageClasses := &storagev1.StorageClassList{}
+	if err := r.APIReader.List(ctx, storageClasses); err != nil {
+		return ctrl.Result{}, fmt.Errorf("storage classes list: %w", err)
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.APIReader.List(ctx, nodes); err != nil {
+		return ctrl.Result{}, fmt.Errorf("nodes list: %w", err)
+	}
+
+	return ctrl.Result{}, r.storageFencingReport(ctx, storageFencingCandidates(storageClasses.Items),
+		discoverNodeCIDRs(nodes.Items), log)
+}
+
+// storageFencingCandidates returns the distinct storage fencing details of
+// the storage classes whose parameters name a storage cluster and a
+// provisioner secret, ordered by driver. Whether a driver supports
+// NetworkFence resources cannot be told from its storage classes, so each is
+// a candidate: a single one is used, and one of several has to be chosen.
+func storageFencingCandidates(storageClasses []storagev1.StorageClass) []ramen.StorageFencing {
+	candidates := make([]ramen.StorageFencing, 0, len(storageClasses))
+	found := map[ramen.StorageFencing]bool{}
+
+	for i := range storageClasses {
+		parameters := storageClasses[i].Parameters
+		candidate := ramen.StorageFencing{
+			Driver:          storageClasses[i].Provisioner,
+			SecretName:      parameters[storageClassParameterSecretName],
+			SecretNamespace: parameters[storageClassParameterSecretNamespace],
+			ClusterID:       parameters[storageClassParameterClusterID],
+		}
+
+		if candidate.ClusterID == "" || candidate.SecretName == "" || candidate.SecretNamespace == "" ||
+			found[candidate] {
+			continue
+		}
+
+		found[candidate] = true
+		candidates = append(candidates, candidate)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Driver != candidates[j].Driver {
+			return candidates[i].Driver < candidates[j].Driver
+		}
+
+		return candidates[i].ClusterID < candidates[j].ClusterID
+	})
+
+	return candidates
+}
+
+// storageFencingReport creates or updates the config map reporting the
+// storage fencing candidates and node CIDRs. It is kept, without data, if
+// there are none, so that the hub can tell that nothing is discovered from a
+// report it fails to find, such as of an unreachable cluster.
+func (r *StorageFencingReconciler) storageFencingReport(ctx context.Context,
+	candidates []ramen.StorageFencing, nodeCIDRs []string, log logr.Logger,
+) error {
+	desired, err := storageFencingReportData(candidates, nodeCIDRs)
+	if err != nil {
+		return err
+	}
+
+	key := types.NamespacedName{Namespace: NamespaceName(), Name: StorageFencingConfigMapName}
+	configMap := &corev1.ConfigMap{}
+
+	if err := r.APIReader.Get(ctx, key, configMap); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("config map %s get: %w", key, err)
+		}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data:       desired,
+		}
+
+		log.Info("Storage fencing details discovered", "candidates", candidates, "nodeCIDRs", nodeCIDRs)
+
+		return r.Client.Create(ctx, configMap)
+	}
+
+	if len(configMap.Data) == 0 && len(desired) == 0 || reflect.DeepEqual(configMap.Data, desired) {
+		return nil
+	}
+
+	configMap.Data = desired
+
+	log.Info("Storage fencing details changed", "candidates", candidates, "nodeCIDRs", nodeCIDRs)
+
+	return r.Client.Update(ctx, configMap)
+}
+
+func storageFencingReportData(candidates []ramen.StorageFencing, nodeCIDRs []string,
+) (map[string]string, error) {
+	data := map[string]string{}
+
+	if len(candidates) > 0 {
+		value, err := json.Marshal(candidates)
+		if err != nil {
+			return nil, fmt.Errorf("storage fencing candidates marshal: %w", err)
+		}
+
+		data[storageFencingCandidatesConfigMapKey] = string(value)
+	}
+
+	if len(nodeCIDRs) > 0 {
+		value, err := json.Marshal(nodeCIDRs)
+		if err != nil {
+			return nil, fmt.Errorf("node CIDRs marshal: %w", err)
+		}
+
+		data[nodeCIDRsConfigMapKey] = string(value)
+	}
+
+	return data, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StorageFencingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("storagefencing").
+		For(&storagev1.StorageClass{}).
+		Watches(&source.Kind{Type: &corev1.Node{}}, &handler.EnqueueRequestForObject{},
+			builder.WithPredicates(nodeAddressesChangedPredicate())).
+		Complete(r)
+}
+
+// storageFencingUpdate sets the DRCluster status' storage fencing details and
+// node CIDRs to those its managed cluster reports, or clears those it reports
+// none of, and then whether its spec CIDRs are out of date. A report that is
+// not found, as of a cluster that is unreachable, such as one that is fenced,
+// leaves them as last reported.
+func (u *drclusterInstance) storageFencingUpdate(ramenConfig *ramen.RamenConfig) error {
+	err := u.storageFencingReportGet(ramenConfig)
+
+	u.cidrsOutOfDateConditionSet()
+
+	return err
+}
+
+func (u *drclusterInstance) storageFencingReportGet(ramenConfig *ramen.RamenConfig) error {
+	configMap, err := u.reconciler.MCVGetter.GetConfigMapFromManagedCluster(StorageFencingConfigMapName,
+		drClusterOperatorNamespaceNameOrDefault(ramenConfig), u.object.Name,
+		map[string]string{DRClusterNameAnnotation: u.object.Name})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			u.log.Info("Storage fencing report not found, keeping the last one")
+
+			return nil
+		}
+
+		return fmt.Errorf("storage fencing config map get: %w", err)
+	}
+
+	var candidates []ramen.StorageFencing
+
+	if value, ok := configMap.Data[storageFencingCandidatesConfigMapKey]; ok {
+		if err := json.Unmarshal([]byte(value), &candidates); err != nil {
+			return fmt.Errorf("storage fencing candidates unmarshal: %w", err)
+		}
+	}
+
+	var nodeCIDRs []string
+
+	if value, ok := configMap.Data[nodeCIDRsConfigMapKey]; ok {
+		if err := json.Unmarshal([]byte(value), &nodeCIDRs); err != nil {
+			return fmt.Errorf("node CIDRs unmarshal: %w", err)
+		}
+	}
+
+	u.object.Status.StorageFencing = nil
+	u.object.Status.StorageFencingCandidates = nil
+
+	switch len(candidates) {
+	case 0:
+	case 1:
+		u.object.Status.StorageFencing = &candidates[0]
+	default:
+		u.object.Status.StorageFencingCandidates = candidates
+	}
+
+	u.object.Status.NodeCIDRs = nodeCIDRs
+
+	return nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("StorageFencingReconciler", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *controllers.StorageFencingReconciler
+	)
+
+	storageClass := func(name, provisioner string, parameters map[string]string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: name},
+			Provisioner: provisioner,
+			Parameters:  parameters,
+		}
+	}
+
+	cephParameters := func(clusterID string) map[string]string {
+		return map[string]string{
+			"clusterID": clusterID,
+			"csi.storage.k8s.io/provisioner-secret-name":      "rook-csi-rbd-provisioner",
+			"csi.storage.k8s.io/provisioner-secret-namespace": "rook-ceph",
+		}
+	}
+
+	cephStorageFencing := func(driver, clusterID string) ramen.StorageFencing {
+		return ramen.StorageFencing{
+			Driver:          driver,
+			SecretName:      "rook-csi-rbd-provisioner",
+			SecretNamespace: "rook-ceph",
+			ClusterID:       clusterID,
+		}
+	}
+
+	reportKey := func() types.NamespacedName {
+		return types.NamespacedName{
+			Namespace: controllers.NamespaceName(),
+			Name:      controllers.StorageFencingConfigMapName,
+		}
+	}
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "storage-class"}})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	report := func() map[string]string {
+		configMap := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, reportKey(), configMap)).To(Succeed())
+
+		return configMap.Data
+	}
+
+	reportedCandidates := func() []ramen.StorageFencing {
+		var candidates []ramen.StorageFencing
+		Expect(json.Unmarshal([]byte(report()["storageFencingCandidates"]), &candidates)).To(Succeed())
+
+		return candidates
+	}
+
+	node := func(name string, addresses ...corev1.NodeAddress) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.NodeStatus{Addresses: addresses},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(storagev1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			storageClass("standard", "kubernetes.io/no-provisioner", nil),
+			storageClass("partial", "rbd.csi.ceph.com", map[string]string{"clusterID": "rook-ceph"}),
+		).Build()
+		reconciler = &controllers.StorageFencingReconciler{
+			Client:    fakeClient,
+			APIReader: fakeClient,
+			Log:       ctrl.Log.WithName("StorageFencingReconcilerTest"),
+		}
+	})
+
+	It("reports nothing, but keeps its report, without a storage class naming a storage cluster and secret", func() {
+		reconcile()
+		Expect(report()).To(BeEmpty())
+	})
+
+	It("reports the InternalIP addresses of nodes as single address CIDRs", func() {
+		for _, n := range []*corev1.Node{
+			node("worker-1",
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.12"},
+				corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.5"},
+				corev1.NodeAddress{Type: corev1.NodeHostName, Address: "worker-1"},
+			),
+			node("worker-2",
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.11"},
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "fd00::11"},
+			),
+			node("worker-3", corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.11"}),
+		} {
+			Expect(fakeClient.Create(ctx, n)).To(Succeed())
+		}
+
+		reconcile()
+		Expect(report()).To(Equal(map[string]string{
+			"nodeCIDRs": `["10.0.0.11/32","10.0.0.12/32","fd00::11/128"]`,
+		}))
+
+		By("reporting no node CIDRs, rather than no report, once the nodes are deleted")
+		for _, name := range []string{"worker-1", "worker-2", "worker-3"} {
+			Expect(fakeClient.Delete(ctx, node(name))).To(Succeed())
+		}
+
+		reconcile()
+		Expect(report()).To(BeEmpty())
+	})
+
+	It("reports each distinct storage class fencing detail as a candidate", func() {
+		for _, sc := range []*storagev1.StorageClass{
+			storageClass("rbd-b", "b.rbd.csi.ceph.com", cephParameters("b")),
+			storageClass("rbd-a", "a.rbd.csi.ceph.com", cephParameters("a")),
+			storageClass("rbd-a-retain", "a.rbd.csi.ceph.com", cephParameters("a")),
+		} {
+			Expect(fakeClient.Create(ctx, sc)).To(Succeed())
+		}
+
+		reconcile()
+		Expect(reportedCandidates()).To(Equal([]ramen.StorageFencing{
+			cephStorageFencing("a.rbd.csi.ceph.com", "a"),
+			cephStorageFencing("b.rbd.csi.ceph.com", "b"),
+		}))
+
+		By("reporting no candidate once its storage classes are deleted")
+		Expect(fakeClient.Delete(ctx, storageClass("rbd-b", "", nil))).To(Succeed())
+		reconcile()
+		Expect(reportedCandidates()).To(Equal([]ramen.StorageFencing{cephStorageFencing("a.rbd.csi.ceph.com", "a")}))
+	})
+})
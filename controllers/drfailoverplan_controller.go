@@ -0,0 +1,432 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+const drFailoverPlanWaveTimeoutDefault = time.Hour
+
+// DRFailoverPlanReconciler reconciles a DRFailoverPlan object
+type DRFailoverPlanReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//nolint:lll
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=drfailoverplans,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=drfailoverplans/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=drplacementcontrols,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=drpolicies,verbs=get;list;watch
+
+// Reconcile starts a DRFailoverPlan by selecting its DRPCs and ordering them
+// in waves, and then fails over one wave after the other, until one of them
+// fails to fail over in time
+func (r *DRFailoverPlanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("controllers").WithName("drfailoverplan").WithValues("name", req.NamespacedName.Name)
+	log.Info("reconcile enter")
+
+	defer log.Info("reconcile exit")
+
+	plan := &ramen.DRFailoverPlan{}
+	if err := r.Client.Get(ctx, req.NamespacedName, plan); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(fmt.Errorf("get: %w", err))
+	}
+
+	if plan.Status.Phase == ramen.DRFailoverPlanCompleted || plan.Status.Phase == ramen.DRFailoverPlanFailed {
+		return ctrl.Result{}, nil
+	}
+
+	statusOld := plan.Status.DeepCopy()
+
+	if plan.Status.Phase == "" {
+		if err := r.planStart(ctx, plan, log); err != nil {
+			return ctrl.Result{}, fmt.Errorf("start: %w", err)
+		}
+	}
+
+	var err error
+	if plan.Status.Phase == ramen.DRFailoverPlanInProgress {
+		err = r.planProgress(ctx, plan, log)
+	}
+
+	if !reflect.DeepEqual(statusOld, &plan.Status) {
+		if err1 := r.Client.Status().Update(ctx, plan); err1 != nil {
+			return ctrl.Result{}, fmt.Errorf("status update: %w", err1)
+		}
+	}
+
+	if err != nil || plan.Status.Phase != ramen.DRFailoverPlanInProgress {
+		return ctrl.Result{}, err
+	}
+
+	// DRPCs that do not change would not fail the wave otherwise
+	return ctrl.Result{RequeueAfter: time.Until(drFailoverPlanWaveDeadline(plan))}, nil
+}
+
+func drFailoverPlanWaveDeadline(plan *ramen.DRFailoverPlan) time.Time {
+	waveTimeout := drFailoverPlanWaveTimeoutDefault
+	if plan.Spec.WaveTimeout != nil {
+		waveTimeout = plan.Spec.WaveTimeout.Duration
+	}
+
+	return plan.Status.WaveStartTime.Add(waveTimeout)
+}
+
+// planStart selects the DRPCs of the plan and records them in its status, in
+// the waves they are to fail over in. A plan without namespaces fails instead,
+// as the CRD's validation, that requires them, may be bypassed by a plan
+// created before it.
+func (r *DRFailoverPlanReconciler) planStart(ctx context.Context, plan *ramen.DRFailoverPlan, log logr.Logger,
+) error {
+	if len(plan.Spec.Namespaces) == 0 {
+		plan.Status.Phase = ramen.DRFailoverPlanFailed
+		plan.Status.Message = "no namespaces listed, a plan may not select the DRPCs of every namespace"
+		plan.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+
+		log.Info("Plan failed", "message", plan.Status.Message)
+
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&plan.Spec.DRPCSelector)
+	if err != nil {
+		return fmt.Errorf("drpc selector: %w", err)
+	}
+
+	selected := make([]ramen.DRPlacementControl, 0)
+
+	for _, namespaceName := range sets.NewString(plan.Spec.Namespaces...).List() {
+		drpcs := &ramen.DRPlacementControlList{}
+		if err := r.Client.List(ctx, drpcs, client.InNamespace(namespaceName),
+			client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return fmt.Errorf("drpcs of namespace %s list: %w", namespaceName, err)
+		}
+
+		selected = append(selected, drpcs.Items...)
+	}
+
+	plan.Status.DRPCs, plan.Status.Waves = drFailoverPlanWaves(selected)
+	plan.Status.CurrentWave = 0
+	plan.Status.Phase = ramen.DRFailoverPlanInProgress
+	plan.Status.StartTime = &metav1.Time{Time: time.Now()}
+	plan.Status.WaveStartTime = plan.Status.StartTime
+
+	log.Info("Plan started", "drpcs", len(plan.Status.DRPCs), "waves", plan.Status.Waves)
+
+	return nil
+}
+
+// drFailoverPlanWaves orders DRPCs by decreasing priority, and then by
+// namespace and name, and returns their initial plan status along with the
+// number of waves, one per distinct priority
+func drFailoverPlanWaves(drpcs []ramen.DRPlacementControl) ([]ramen.DRFailoverPlanDRPCStatus, int) {
+	statuses := make([]ramen.DRFailoverPlanDRPCStatus, len(drpcs))
+
+	for i := range drpcs {
+		statuses[i] = ramen.DRFailoverPlanDRPCStatus{
+			Namespace: drpcs[i].Namespace,
+			Name:      drpcs[i].Name,
+			Priority:  drpcs[i].Spec.Priority,
+			Phase:     ramen.DRFailoverPlanDRPCPending,
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Priority != statuses[j].Priority {
+			return statuses[i].Priority > statuses[j].Priority
+		}
+
+		if statuses[i].Namespace != statuses[j].Namespace {
+			return statuses[i].Namespace < statuses[j].Namespace
+		}
+
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	waves := 0
+
+	for i := range statuses {
+		if i > 0 && statuses[i].Priority != statuses[i-1].Priority {
+			waves++
+		}
+
+		statuses[i].Wave = waves
+	}
+
+	if len(statuses) > 0 {
+		waves++
+	}
+
+	return statuses, waves
+}
+
+// planProgress fails over the current wave, and moves on to the next wave
+// once every DRPC of the current one failed over or was skipped. A wave with
+// a DRPC that failed to fail over in time fails the plan instead: as the
+// later waves were prioritized to come up after it, they are not started.
+func (r *DRFailoverPlanReconciler) planProgress(ctx context.Context, plan *ramen.DRFailoverPlan, log logr.Logger,
+) error {
+	for plan.Status.CurrentWave < plan.Status.Waves {
+		waveDone, waveFailed, err := r.waveProgress(ctx, plan, log)
+		if err != nil || !waveDone {
+			return err
+		}
+
+		if waveFailed {
+			drFailoverPlanFail(plan)
+
+			log.Info("Plan failed", "wave", plan.Status.CurrentWave)
+
+			return nil
+		}
+
+		log.Info("Wave completed", "wave", plan.Status.CurrentWave)
+
+		plan.Status.CurrentWave++
+		plan.Status.WaveStartTime = &metav1.Time{Time: time.Now()}
+	}
+
+	plan.Status.Phase = ramen.DRFailoverPlanCompleted
+	plan.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+
+	log.Info("Plan completed")
+
+	return nil
+}
+
+// waveProgress requests the failover of the pending DRPCs of the current wave
+// and returns whether each DRPC of the wave is done, meaning it failed over,
+// was skipped or failed, and whether any failed. A DRPC that has not failed
+// over by the wave deadline fails.
+func (r *DRFailoverPlanReconciler) waveProgress(ctx context.Context, plan *ramen.DRFailoverPlan, log logr.Logger,
+) (bool, bool, error) {
+	waveDone, waveFailed := true, false
+	waveTimedOut := !time.Now().Before(drFailoverPlanWaveDeadline(plan))
+
+	for i := range plan.Status.DRPCs {
+		status := &plan.Status.DRPCs[i]
+		if status.Wave != plan.Status.CurrentWave {
+			continue
+		}
+
+		if err := r.drpcProgress(ctx, plan.Spec.FailoverCluster, status, log); err != nil {
+			if !waveTimedOut {
+				return false, false, err
+			}
+
+			status.Message = err.Error()
+		}
+
+		switch status.Phase {
+		case ramen.DRFailoverPlanDRPCPending, ramen.DRFailoverPlanDRPCFailingOver:
+			if !waveTimedOut {
+				waveDone = false
+
+				break
+			}
+
+			drFailoverPlanDRPCTimeout(status)
+
+			waveFailed = true
+		case ramen.DRFailoverPlanDRPCFailed:
+			waveFailed = true
+		case ramen.DRFailoverPlanDRPCFailedOver, ramen.DRFailoverPlanDRPCSkipped:
+		}
+	}
+
+	return waveDone, waveFailed, nil
+}
+
+// drFailoverPlanFail fails a plan, and the DRPCs of its waves that were not
+// started
+func drFailoverPlanFail(plan *ramen.DRFailoverPlan) {
+	for i := range plan.Status.DRPCs {
+		status := &plan.Status.DRPCs[i]
+		if status.Wave > plan.Status.CurrentWave {
+			status.Phase = ramen.DRFailoverPlanDRPCFailed
+			status.Message = fmt.Sprintf("wave %d failed", plan.Status.CurrentWave)
+		}
+	}
+
+	plan.Status.Phase = ramen.DRFailoverPlanFailed
+	plan.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+}
+
+func (r *DRFailoverPlanReconciler) drpcProgress(ctx context.Context, failoverCluster string,
+	status *ramen.DRFailoverPlanDRPCStatus, log logr.Logger,
+) error {
+	drpc := &ramen.DRPlacementControl{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: status.Namespace, Name: status.Name},
+		drpc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			drFailoverPlanDRPCSkip(status, "DRPC not found")
+
+			return nil
+		}
+
+		return fmt.Errorf("drpc %s/%s get: %w", status.Namespace, status.Name, err)
+	}
+
+	switch status.Phase {
+	case ramen.DRFailoverPlanDRPCPending:
+		return r.drpcFailover(ctx, drpc, failoverCluster, status, log)
+	case ramen.DRFailoverPlanDRPCFailingOver:
+		available := findCondition(drpc.Status.Conditions, ramen.ConditionAvailable)
+		if drpc.Status.Phase == ramen.FailedOver && available != nil &&
+			available.Status == metav1.ConditionTrue && available.ObservedGeneration == drpc.Generation {
+			status.Phase = ramen.DRFailoverPlanDRPCFailedOver
+			status.Message = ""
+
+			return nil
+		}
+
+		if available != nil {
+			status.Message = available.Message
+		}
+	case ramen.DRFailoverPlanDRPCFailedOver, ramen.DRFailoverPlanDRPCSkipped, ramen.DRFailoverPlanDRPCFailed:
+	}
+
+	return nil
+}
+
+// drpcFailover requests the failover of a DRPC to the plan's cluster, or
+// skips it if it cannot be failed over there
+func (r *DRFailoverPlanReconciler) drpcFailover(ctx context.Context, drpc *ramen.DRPlacementControl,
+	failoverCluster string, status *ramen.DRFailoverPlanDRPCStatus, log logr.Logger,
+) error {
+	if !drpc.GetDeletionTimestamp().IsZero() {
+		drFailoverPlanDRPCSkip(status, "DRPC is being deleted")
+
+		return nil
+	}
+
+	drpolicy := &ramen.DRPolicy{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: drpc.Spec.DRPolicyRef.Name}, drpolicy); err != nil {
+		return fmt.Errorf("drpolicy %s get: %w", drpc.Spec.DRPolicyRef.Name, err)
+	}
+
+	if !sets.NewString(util.DrpolicyClusterNames(drpolicy)...).Has(failoverCluster) {
+		drFailoverPlanDRPCSkip(status, fmt.Sprintf("cluster %s is not a cluster of drpolicy %s", failoverCluster,
+			drpolicy.Name))
+
+		return nil
+	}
+
+	if drpc.Spec.Action == ramen.ActionFailover && drpc.Spec.FailoverCluster == failoverCluster {
+		status.Phase = ramen.DRFailoverPlanDRPCFailingOver
+
+		return nil
+	}
+
+	if drpc.Status.PreferredDecision.ClusterName == failoverCluster {
+		drFailoverPlanDRPCSkip(status, fmt.Sprintf("already placed on cluster %s", failoverCluster))
+
+		return nil
+	}
+
+	drpc.Spec.Action = ramen.ActionFailover
+	drpc.Spec.FailoverCluster = failoverCluster
+
+	if err := r.Client.Update(ctx, drpc); err != nil {
+		return fmt.Errorf("drpc %s/%s update: %w", drpc.Namespace, drpc.Name, err)
+	}
+
+	log.Info("Failover requested", "DRPC", types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name},
+		"wave", status.Wave)
+
+	status.Phase = ramen.DRFailoverPlanDRPCFailingOver
+
+	return nil
+}
+
+func drFailoverPlanDRPCSkip(status *ramen.DRFailoverPlanDRPCStatus, message string) {
+	status.Phase = ramen.DRFailoverPlanDRPCSkipped
+	status.Message = message
+}
+
+func drFailoverPlanDRPCTimeout(status *ramen.DRFailoverPlanDRPCStatus) {
+	message := "not failed over within the wave timeout"
+	if status.Message != "" {
+		message += ": " + status.Message
+	}
+
+	status.Phase = ramen.DRFailoverPlanDRPCFailed
+	status.Message = message
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DRFailoverPlanReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ramen.DRFailoverPlan{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &ramen.DRPlacementControl{}},
+			handler.EnqueueRequestsFromMapFunc(r.drpcMapFunc),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+// drpcMapFunc requests the reconciliation of the plans in progress that fail
+// over a DRPC that changed
+func (r *DRFailoverPlanReconciler) drpcMapFunc(drpc client.Object) []reconcile.Request {
+	plans := &ramen.DRFailoverPlanList{}
+	if err := r.Client.List(context.TODO(), plans); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+
+	for i := range plans.Items {
+		plan := &plans.Items[i]
+		if plan.Status.Phase != ramen.DRFailoverPlanInProgress {
+			continue
+		}
+
+		for _, status := range plan.Status.DRPCs {
+			if status.Namespace == drpc.GetNamespace() && status.Name == drpc.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: plan.Name}})
+
+				break
+			}
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("DRFailoverPlan", func() {
+	const failoverCluster = "plan-west1"
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *controllers.DRFailoverPlanReconciler
+	)
+
+	drpolicy := func(name string, clusterNames ...string) *ramen.DRPolicy {
+		return &ramen.DRPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       ramen.DRPolicySpec{DRClusters: clusterNames},
+		}
+	}
+
+	drpc := func(namespace, name, drpolicyName string, priority int) *ramen.DRPlacementControl {
+		return &ramen.DRPlacementControl{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"site": "east"}},
+			Spec: ramen.DRPlacementControlSpec{
+				DRPolicyRef: corev1.ObjectReference{Name: drpolicyName},
+				Priority:    priority,
+			},
+			Status: ramen.DRPlacementControlStatus{
+				PreferredDecision: plrv1.PlacementDecision{ClusterName: "plan-east1"},
+			},
+		}
+	}
+
+	drpcGet := func(namespace, name string) *ramen.DRPlacementControl {
+		drpc := &ramen.DRPlacementControl{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, drpc)).To(Succeed())
+
+		return drpc
+	}
+
+	drpcFailedOver := func(namespace, name string) {
+		drpc := drpcGet(namespace, name)
+		drpc.Status.Phase = ramen.FailedOver
+		drpc.Status.Conditions = []metav1.Condition{{
+			Type:               ramen.ConditionAvailable,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: drpc.Generation,
+			Reason:             string(ramen.FailedOver),
+			LastTransitionTime: metav1.Now(),
+		}}
+		Expect(fakeClient.Status().Update(ctx, drpc)).To(Succeed())
+	}
+
+	planReconcile := func(name string) (*ramen.DRFailoverPlan, ctrl.Result) {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+		Expect(err).ToNot(HaveOccurred())
+
+		plan := &ramen.DRFailoverPlan{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name}, plan)).To(Succeed())
+
+		return plan, result
+	}
+
+	planDRPCPhases := func(plan *ramen.DRFailoverPlan) map[string]ramen.DRFailoverPlanDRPCPhase {
+		phases := map[string]ramen.DRFailoverPlanDRPCPhase{}
+		for _, status := range plan.Status.DRPCs {
+			phases[status.Namespace+"/"+status.Name] = status.Phase
+		}
+
+		return phases
+	}
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+
+		scheme := runtime.NewScheme()
+		Expect(ramen.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			drpolicy("plan-metro", "plan-east1", failoverCluster),
+			drpolicy("plan-other", "plan-east1", "plan-east2"),
+			drpc("app-a", "db", "plan-metro", 10),
+			drpc("app-c", "cache", "plan-metro", 10),
+			drpc("app-a", "web", "plan-metro", 0),
+			drpc("app-b", "web", "plan-metro", 0),
+			drpc("app-d", "batch", "plan-other", -5),
+		).Build()
+		reconciler = &controllers.DRFailoverPlanReconciler{Client: fakeClient, Scheme: scheme}
+	})
+
+	planCreate := func(name string, namespaces ...string) {
+		Expect(fakeClient.Create(ctx, &ramen.DRFailoverPlan{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: ramen.DRFailoverPlanSpec{
+				FailoverCluster: failoverCluster,
+				DRPCSelector:    metav1.LabelSelector{MatchLabels: map[string]string{"site": "east"}},
+				Namespaces:      namespaces,
+				WaveTimeout:     &metav1.Duration{Duration: time.Minute},
+			},
+		})).To(Succeed())
+	}
+
+	It("fails over the DRPCs in one wave per priority, highest first", func() {
+		planCreate("plan-waves", "app-a", "app-b", "app-c", "app-d")
+
+		plan, result := planReconcile("plan-waves")
+		Expect(plan.Status.Phase).To(Equal(ramen.DRFailoverPlanInProgress))
+		Expect(plan.Status.Waves).To(Equal(3))
+		Expect(plan.Status.CurrentWave).To(Equal(0))
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+		Expect(plan.Status.DRPCs).To(HaveLen(5))
+		Expect(plan.Status.DRPCs[0].Namespace + "/" + plan.Status.DRPCs[0].Name).To(Equal("app-a/db"))
+		Expect(plan.Status.DRPCs[4].Namespace + "/" + plan.Status.DRPCs[4].Name).To(Equal("app-d/batch"))
+		Expect(planDRPCPhases(plan)).To(Equal(map[string]ramen.DRFailoverPlanDRPCPhase{
+			"app-a/db":    ramen.DRFailoverPlanDRPCFailingOver,
+			"app-c/cache": ramen.DRFailoverPlanDRPCFailingOver,
+			"app-a/web":   ramen.DRFailoverPlanDRPCPending,
+			"app-b/web":   ramen.DRFailoverPlanDRPCPending,
+			"app-d/batch": ramen.DRFailoverPlanDRPCPending,
+		}))
+		Expect(drpcGet("app-a", "db").Spec.Action).To(Equal(ramen.ActionFailover))
+		Expect(drpcGet("app-a", "db").Spec.FailoverCluster).To(Equal(failoverCluster))
+		Expect(drpcGet("app-a", "web").Spec.Action).To(BeEmpty())
+
+		By("waiting for each DRPC of the first wave to fail over")
+		drpcFailedOver("app-a", "db")
+		plan, _ = planReconcile("plan-waves")
+		Expect(plan.Status.CurrentWave).To(Equal(0))
+		Expect(drpcGet("app-a", "web").Spec.Action).To(BeEmpty())
+
+		drpcFailedOver("app-c", "cache")
+		plan, _ = planReconcile("plan-waves")
+		Expect(plan.Status.CurrentWave).To(Equal(1))
+		Expect(planDRPCPhases(plan)).To(HaveKeyWithValue("app-a/db", ramen.DRFailoverPlanDRPCFailedOver))
+		Expect(planDRPCPhases(plan)).To(HaveKeyWithValue("app-b/web", ramen.DRFailoverPlanDRPCFailingOver))
+		Expect(drpcGet("app-a", "web").Spec.Action).To(Equal(ramen.ActionFailover))
+		Expect(drpcGet("app-b", "web").Spec.Action).To(Equal(ramen.ActionFailover))
+
+		By("skipping a DRPC whose DRPolicy does not include the failover cluster")
+		drpcFailedOver("app-a", "web")
+		drpcFailedOver("app-b", "web")
+		plan, result = planReconcile("plan-waves")
+		Expect(plan.Status.Phase).To(Equal(ramen.DRFailoverPlanCompleted))
+		Expect(plan.Status.CompletionTime).ToNot(BeNil())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(plan.Status.DRPCs[4].Phase).To(Equal(ramen.DRFailoverPlanDRPCSkipped))
+		Expect(plan.Status.DRPCs[4].Message).To(ContainSubstring("is not a cluster of drpolicy plan-other"))
+		Expect(drpcGet("app-d", "batch").Spec.Action).To(BeEmpty())
+	})
+
+	It("selects only the DRPCs of its namespaces", func() {
+		planCreate("plan-namespaces", "app-a")
+
+		plan, _ := planReconcile("plan-namespaces")
+		Expect(planDRPCPhases(plan)).To(Equal(map[string]ramen.DRFailoverPlanDRPCPhase{
+			"app-a/db":  ramen.DRFailoverPlanDRPCFailingOver,
+			"app-a/web": ramen.DRFailoverPlanDRPCPending,
+		}))
+		Expect(drpcGet("app-c", "cache").Spec.Action).To(BeEmpty())
+	})
+
+	It("fails without selecting any DRPC if it lists no namespaces", func() {
+		planCreate("plan-no-namespaces")
+
+		plan, result := planReconcile("plan-no-namespaces")
+		Expect(plan.Status.Phase).To(Equal(ramen.DRFailoverPlanFailed))
+		Expect(plan.Status.Message).To(ContainSubstring("no namespaces listed"))
+		Expect(plan.Status.DRPCs).To(BeEmpty())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(drpcGet("app-a", "db").Spec.Action).To(BeEmpty())
+	})
+
+	It("fails without starting the later waves if a wave does not fail over in time", func() {
+		planCreate("plan-timeout", "app-a", "app-b", "app-c", "app-d")
+		plan, _ := planReconcile("plan-timeout")
+
+		drpcFailedOver("app-a", "db")
+		plan.Status.WaveStartTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		Expect(fakeClient.Status().Update(ctx, plan)).To(Succeed())
+
+		plan, result := planReconcile("plan-timeout")
+		Expect(plan.Status.Phase).To(Equal(ramen.DRFailoverPlanFailed))
+		Expect(plan.Status.CurrentWave).To(Equal(0))
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(planDRPCPhases(plan)).To(Equal(map[string]ramen.DRFailoverPlanDRPCPhase{
+			"app-a/db":    ramen.DRFailoverPlanDRPCFailedOver,
+			"app-c/cache": ramen.DRFailoverPlanDRPCFailed,
+			"app-a/web":   ramen.DRFailoverPlanDRPCFailed,
+			"app-b/web":   ramen.DRFailoverPlanDRPCFailed,
+			"app-d/batch": ramen.DRFailoverPlanDRPCFailed,
+		}))
+		Expect(plan.Status.DRPCs[1].Message).To(HavePrefix("not failed over within the wave timeout"))
+		Expect(plan.Status.DRPCs[2].Message).To(Equal("wave 0 failed"))
+		Expect(drpcGet("app-a", "web").Spec.Action).To(BeEmpty())
+
+		By("not failing over the later waves once failed")
+		plan, _ = planReconcile("plan-timeout")
+		Expect(plan.Status.Phase).To(Equal(ramen.DRFailoverPlanFailed))
+		Expect(drpcGet("app-b", "web").Spec.Action).To(BeEmpty())
+	})
+})
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/ghodss/yaml"
@@ -42,6 +43,21 @@ const (
 	// Annotations for MW and PlacementRule
 	DRPCNameAnnotation      = "drplacementcontrol.ramendr.openshift.io/drpc-name"
 	DRPCNamespaceAnnotation = "drplacementcontrol.ramendr.openshift.io/drpc-namespace"
+
+	// Annotations for VRG, so that a hub can reconstruct its DRPC from it
+	DRPolicyNameAnnotation      = "drplacementcontrol.ramendr.openshift.io/drpolicy-name"
+	PlacementRuleNameAnnotation = "drplacementcontrol.ramendr.openshift.io/placementrule-name"
+
+	// Annotation for a DRPC reconstructed by a hub from its VRG
+	DRPCAdoptedAnnotation = "drplacementcontrol.ramendr.openshift.io/adopted"
+
+	// Annotation for a DRPC failed over automatically, with the cluster it was
+	// failed over to
+	DRPCAutoFailedOverAnnotation = "drplacementcontrol.ramendr.openshift.io/auto-failed-over-to"
+
+	// Annotation for a DRPC restored by a hub from a hub backup, with the json
+	// encoded status to restore before the DRPC is processed
+	DRPCRestoredStatusAnnotation = "drplacementcontrol.ramendr.openshift.io/restored-status"
 )
 
 var (
@@ -50,23 +66,26 @@ var (
 	WaitForSourceCluster               error = errorswrapper.New("Waiting for primary to provide Protected PVCs...")
 	WaitForVolSyncManifestWorkCreation error = errorswrapper.New("Waiting for VolSync ManifestWork to be created...")
 	WaitForVolSyncRDInfoAvailibility   error = errorswrapper.New("Waiting for VolSync RDInfo...")
+	WaitForDependencies                error = errorswrapper.New("Waiting for dependencies...")
 )
 
 type DRPCInstance struct {
-	reconciler           *DRPlacementControlReconciler
-	ctx                  context.Context
-	log                  logr.Logger
-	instance             *rmn.DRPlacementControl
-	savedInstanceStatus  rmn.DRPlacementControlStatus
-	drPolicy             *rmn.DRPolicy
-	drClusters           []rmn.DRCluster
-	mcvRequestInProgress bool
-	volSyncDisabled      bool
-	userPlacementRule    *plrv1.PlacementRule
-	drpcPlacementRule    *plrv1.PlacementRule
-	vrgs                 map[string]*rmn.VolumeReplicationGroup
-	mwu                  rmnutil.MWUtil
-	metricsTimer         timerInstance
+	reconciler                  *DRPlacementControlReconciler
+	ctx                         context.Context
+	log                         logr.Logger
+	instance                    *rmn.DRPlacementControl
+	savedInstanceStatus         rmn.DRPlacementControlStatus
+	drPolicy                    *rmn.DRPolicy
+	drClusters                  []rmn.DRCluster
+	mcvRequestInProgress        bool
+	volSyncDisabled             bool
+	volSyncSecretRotationPeriod time.Duration
+	dataLaggingThreshold        time.Duration
+	userPlacementRule           *plrv1.PlacementRule
+	drpcPlacementRule           *plrv1.PlacementRule
+	vrgs                        map[string]*rmn.VolumeReplicationGroup
+	mwu                         rmnutil.MWUtil
+	metricsTimer                timerInstance
 }
 
 func (d *DRPCInstance) startProcessing() bool {
@@ -74,9 +93,19 @@ func (d *DRPCInstance) startProcessing() bool {
 
 	requeue := true
 	done, processingErr := d.processPlacement()
+	if processingErr == nil && done {
+		processingErr = d.ensureVRGRecoverySpec(d.getCurrentHomeClusterName())
+	}
 
-	if d.shouldUpdateStatus() || d.statusUpdateTimeElapsed() {
-		if err := d.reconciler.updateDRPCStatus(d.instance, d.userPlacementRule, d.log); err != nil {
+	if processingErr == nil && done {
+		done, processingErr = d.processDrill()
+	}
+
+	d.updateReadiness()
+
+	if d.shouldUpdateStatus() || d.statusUpdateTimeElapsed() || d.dataLaggingCheckDue() {
+		if err := d.reconciler.updateDRPCStatus(d.ctx, d.instance, d.userPlacementRule,
+			d.dataLaggingThreshold, d.log); err != nil {
 			d.log.Error(err, "failed to update status")
 
 			return requeue
@@ -103,6 +132,8 @@ func (d *DRPCInstance) processPlacement() (bool, error) {
 		return d.RunFailover()
 	case rmn.ActionRelocate:
 		return d.RunRelocate()
+	case rmn.ActionFailback:
+		return d.RunFailback()
 	}
 
 	// Not a failover or a relocation.  Must be an initial deployment.
@@ -387,15 +418,7 @@ func (d *DRPCInstance) checkClusterFenced(cluster string, drClusters []rmn.DRClu
 			continue
 		}
 
-		drClusterFencedCondition := findCondition(drClusters[i].Status.Conditions, rmn.DRClusterConditionTypeFenced)
-		if drClusterFencedCondition == nil {
-			d.log.Info("drCluster fenced condition not available", "cluster", drClusters[i].Name)
-
-			return false, nil
-		}
-
-		if drClusterFencedCondition.Status != metav1.ConditionTrue ||
-			drClusterFencedCondition.ObservedGeneration != drClusters[i].Generation {
+		if !drClusterFenced(&drClusters[i]) {
 			d.log.Info("drCluster fenced condition is not true", "cluster", drClusters[i].Name)
 
 			return false, nil
@@ -407,6 +430,28 @@ func (d *DRPCInstance) checkClusterFenced(cluster string, drClusters []rmn.DRClu
 	return false, fmt.Errorf("failed to get the fencing status for the cluster %s", cluster)
 }
 
+// drClusterFenced returns whether a DRCluster's fenced condition is true for
+// its current generation
+func drClusterFenced(drcluster *rmn.DRCluster) bool {
+	condition := findCondition(drcluster.Status.Conditions, rmn.DRClusterConditionTypeFenced)
+
+	return condition != nil && condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == drcluster.Generation
+}
+
+// drClusterUnfenced returns whether a DRCluster is neither to be fenced nor
+// still fenced
+func drClusterUnfenced(drcluster *rmn.DRCluster) bool {
+	switch drcluster.Spec.ClusterFence {
+	case rmn.ClusterFenceStateFenced, rmn.ClusterFenceStateManuallyFenced:
+		return false
+	}
+
+	condition := findCondition(drcluster.Status.Conditions, rmn.DRClusterConditionTypeFenced)
+
+	return condition == nil || condition.Status != metav1.ConditionTrue
+}
+
 func (d *DRPCInstance) switchToFailoverCluster() (bool, error) {
 	const done = true
 	// Make sure we record the state that we are failing over
@@ -1099,13 +1144,15 @@ func (d *DRPCInstance) cleanupSecondaries(skipCluster string) (bool, error) {
 			return false, fmt.Errorf("deletion of VRG MCV failed %w", err)
 		}
 
-		err = d.reconciler.MCVGetter.DeleteNamespaceManagedClusterView(d.instance.Name, d.instance.Namespace, clusterName,
-			rmnutil.MWTypeNS)
-		// MCV for Namespace is no longer needed
-		if err != nil {
-			d.log.Info("Deletion of Namespace MCV failed")
+		// MCVs for Namespaces are no longer needed
+		for _, namespaceName := range drpcNamespaceNames(d.instance) {
+			err = d.reconciler.MCVGetter.DeleteNamespaceManagedClusterView(d.instance.Name, namespaceName, clusterName,
+				rmnutil.MWTypeNS)
+			if err != nil {
+				d.log.Info("Deletion of Namespace MCV failed")
 
-			return false, fmt.Errorf("deletion of namespace MCV failed %w", err)
+				return false, fmt.Errorf("deletion of namespace MCV failed %w", err)
+			}
 		}
 	}
 
@@ -1116,6 +1163,15 @@ func (d *DRPCInstance) updateUserPlacementRule(homeCluster, homeClusterNamespace
 	d.log.Info(fmt.Sprintf("Updating userPlacementRule %s homeCluster %s",
 		d.userPlacementRule.Name, homeCluster))
 
+	// Hold the placement of the application until the applications it depends on are available there
+	if !d.isUserPlRuleUpdated(homeCluster) {
+		if err := d.checkDependenciesAvailable(homeCluster); err != nil {
+			d.setProgression(rmn.ProgressionWaitingForDeps)
+
+			return fmt.Errorf("%w %v", WaitForDependencies, err)
+		}
+	}
+
 	if homeClusterNamespace == "" {
 		homeClusterNamespace = homeCluster
 	}
@@ -1177,7 +1233,7 @@ func vrgAction(drpcAction rmn.DRAction) rmn.VRGAction {
 	switch drpcAction {
 	case rmn.ActionFailover:
 		return rmn.VRGActionFailover
-	case rmn.ActionRelocate:
+	case rmn.ActionRelocate, rmn.ActionFailback:
 		return rmn.VRGActionRelocate
 	default:
 		return ""
@@ -1195,22 +1251,73 @@ func (d *DRPCInstance) setVRGAction(vrg *rmn.VolumeReplicationGroup) {
 
 func (d *DRPCInstance) generateVRG(repState rmn.ReplicationState) rmn.VolumeReplicationGroup {
 	vrg := rmn.VolumeReplicationGroup{
-		TypeMeta:   metav1.TypeMeta{Kind: "VolumeReplicationGroup", APIVersion: "ramendr.openshift.io/v1alpha1"},
-		ObjectMeta: metav1.ObjectMeta{Name: d.instance.Name, Namespace: d.instance.Namespace},
+		TypeMeta: metav1.TypeMeta{Kind: "VolumeReplicationGroup", APIVersion: "ramendr.openshift.io/v1alpha1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      d.instance.Name,
+			Namespace: d.instance.Namespace,
+			Annotations: map[string]string{
+				DRPolicyNameAnnotation:      d.instance.Spec.DRPolicyRef.Name,
+				PlacementRuleNameAnnotation: d.instance.Spec.PlacementRef.Name,
+			},
+		},
 		Spec: rmn.VolumeReplicationGroupSpec{
-			PVCSelector:      d.instance.Spec.PVCSelector,
-			ReplicationState: repState,
-			S3Profiles:       rmnutil.DRPolicyS3Profiles(d.drPolicy, d.drClusters).List(),
+			PVCSelector:         d.instance.Spec.PVCSelector,
+			ProtectedNamespaces: d.instance.Spec.ProtectedNamespaces,
+			ReplicationState:    repState,
+			S3Profiles:          rmnutil.DRPolicyS3Profiles(d.drPolicy, d.drClusters).List(),
 		},
 	}
 
 	d.setVRGAction(&vrg)
+	d.setVRGRecoverySpec(&vrg)
 	vrg.Spec.Async = d.generateVRGSpecAsync()
 	vrg.Spec.Sync = d.generateVRGSpecSync()
 
 	return vrg
 }
 
+// setVRGRecoverySpec sets the cluster data retention and generation to
+// restore, and the kube object protection, of the given VRG to the DRPC's
+func (d *DRPCInstance) setVRGRecoverySpec(vrg *rmn.VolumeReplicationGroup) {
+	vrg.Spec.ClusterDataRetention = d.instance.Spec.ClusterDataRetention
+	vrg.Spec.ClusterDataGeneration = d.instance.Spec.ClusterDataGeneration
+	vrg.Spec.KubeObjectProtection = d.instance.Spec.KubeObjectProtection
+}
+
+// ensureVRGRecoverySpec updates the VRG of the given cluster, if any, with
+// the DRPC's cluster data retention and generation to restore, and kube object
+// protection, which may change once the VRG is deployed
+func (d *DRPCInstance) ensureVRGRecoverySpec(clusterName string) error {
+	if clusterName == "" {
+		return nil
+	}
+
+	vrg, err := d.getVRGFromManifestWork(clusterName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get VRG ManifestWork of cluster %s (%w)", clusterName, err)
+	}
+
+	if reflect.DeepEqual(vrg.Spec.ClusterDataRetention, d.instance.Spec.ClusterDataRetention) &&
+		vrg.Spec.ClusterDataGeneration == d.instance.Spec.ClusterDataGeneration &&
+		reflect.DeepEqual(vrg.Spec.KubeObjectProtection, d.instance.Spec.KubeObjectProtection) {
+		return nil
+	}
+
+	d.setVRGRecoverySpec(vrg)
+
+	if err := d.updateManifestWork(clusterName, vrg); err != nil {
+		return fmt.Errorf("failed to update recovery spec of VRG on cluster %s (%w)", clusterName, err)
+	}
+
+	d.log.Info("Updated VRG recovery spec", "cluster", clusterName)
+
+	return nil
+}
+
 func (d *DRPCInstance) generateVRGSpecAsync() rmn.VRGAsyncSpec {
 	if dRPolicySupportsRegional(d.drPolicy, d.drClusters) {
 		return rmn.VRGAsyncSpec{
@@ -1299,12 +1406,24 @@ func drClusterRegion(drClusters []rmn.DRCluster, cluster string) (region rmn.Reg
 	return
 }
 
+// ensureNamespaceExistsOnManagedCluster ensures that the DRPC's namespace,
+// and each of its protected namespaces, exists on the given cluster
 func (d *DRPCInstance) ensureNamespaceExistsOnManagedCluster(homeCluster string) error {
+	for _, namespaceName := range drpcNamespaceNames(d.instance) {
+		if err := d.ensureNamespaceNameExistsOnManagedCluster(namespaceName, homeCluster); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DRPCInstance) ensureNamespaceNameExistsOnManagedCluster(namespaceName, homeCluster string) error {
 	// verify namespace exists on target cluster
-	namespaceExists, err := d.namespaceExistsOnManagedCluster(homeCluster)
+	namespaceExists, err := d.namespaceExistsOnManagedCluster(namespaceName, homeCluster)
 
 	d.log.Info(fmt.Sprintf("createVRGManifestWork: namespace '%s' exists on cluster %s: %t",
-		d.instance.Namespace, homeCluster, namespaceExists))
+		namespaceName, homeCluster, namespaceExists))
 
 	if !namespaceExists { // attempt to create it
 		annotations := make(map[string]string)
@@ -1312,12 +1431,12 @@ func (d *DRPCInstance) ensureNamespaceExistsOnManagedCluster(homeCluster string)
 		annotations[DRPCNameAnnotation] = d.mwu.InstName
 		annotations[DRPCNamespaceAnnotation] = d.mwu.InstNamespace
 
-		err := d.mwu.CreateOrUpdateNamespaceManifest(d.instance.Name, d.instance.Namespace, homeCluster, annotations)
+		err := d.mwu.CreateOrUpdateNamespaceManifest(d.instance.Name, namespaceName, homeCluster, annotations)
 		if err != nil {
-			return fmt.Errorf("failed to create namespace '%s' on cluster %s: %w", d.instance.Namespace, homeCluster, err)
+			return fmt.Errorf("failed to create namespace '%s' on cluster %s: %w", namespaceName, homeCluster, err)
 		}
 
-		d.log.Info(fmt.Sprintf("Created Namespace '%s' on cluster %s", d.instance.Namespace, homeCluster))
+		d.log.Info(fmt.Sprintf("Created Namespace '%s' on cluster %s", namespaceName, homeCluster))
 
 		return nil // created namespace
 	}
@@ -1325,7 +1444,42 @@ func (d *DRPCInstance) ensureNamespaceExistsOnManagedCluster(homeCluster string)
 	// namespace exists already
 	if err != nil {
 		return fmt.Errorf("failed to verify if namespace '%s' on cluster %s exists: %w",
-			d.instance.Namespace, homeCluster, err)
+			namespaceName, homeCluster, err)
+	}
+
+	return nil
+}
+
+// drpcNamespaceNames returns the names of the namespaces whose applications
+// the DRPC protects, its own first
+func drpcNamespaceNames(drpc *rmn.DRPlacementControl) []string {
+	namespaceNames := []string{drpc.Namespace}
+
+	for _, namespaceName := range drpc.Spec.ProtectedNamespaces {
+		if !containsString(namespaceNames, namespaceName) {
+			namespaceNames = append(namespaceNames, namespaceName)
+		}
+	}
+
+	return namespaceNames
+}
+
+// drpcProtectedNamespacesAllowed returns an error if the DRPC lists protected
+// namespaces but is not in the namespace that the ramen config allows to.
+// Since such a DRPC fails over and relocates the applications of namespaces
+// besides its own, only the hub admin may create it.
+func drpcProtectedNamespacesAllowed(drpc *rmn.DRPlacementControl, ramenConfig *rmn.RamenConfig) error {
+	if len(drpc.Spec.ProtectedNamespaces) == 0 {
+		return nil
+	}
+
+	adminNamespaceName := ramenConfig.MultiNamespace.AdminNamespaceName
+	if adminNamespaceName == "" {
+		return fmt.Errorf("protectedNamespaces not allowed, no multiNamespace adminNamespaceName is configured")
+	}
+
+	if drpc.Namespace != adminNamespaceName {
+		return fmt.Errorf("protectedNamespaces allowed only for DRPCs in namespace %s", adminNamespaceName)
 	}
 
 	return nil
@@ -1453,14 +1607,14 @@ func (d *DRPCInstance) EnsureCleanup(clusterToSkip string) error {
 	return nil
 }
 
-func (d *DRPCInstance) namespaceExistsOnManagedCluster(cluster string) (bool, error) {
+func (d *DRPCInstance) namespaceExistsOnManagedCluster(namespaceName, cluster string) (bool, error) {
 	exists := true
 
 	// create ManagedClusterView to check if namespace exists
-	_, err := d.reconciler.MCVGetter.GetNamespaceFromManagedCluster(d.instance.Name, cluster, d.instance.Namespace, nil)
+	_, err := d.reconciler.MCVGetter.GetNamespaceFromManagedCluster(d.instance.Name, cluster, namespaceName, nil)
 	if err != nil {
 		if errors.IsNotFound(err) { // successfully detected that Namespace is not found by ManagedClusterView
-			d.log.Info(fmt.Sprintf("Namespace '%s' not found on cluster %s", d.instance.Namespace, cluster))
+			d.log.Info(fmt.Sprintf("Namespace '%s' not found on cluster %s", namespaceName, cluster))
 
 			return !exists, nil
 		}
@@ -1866,6 +2020,31 @@ func (d *DRPCInstance) shouldUpdateStatus() bool {
 	return !reflect.DeepEqual(d.instance.Status.ResourceConditions.Conditions, vrg.Status.Conditions)
 }
 
+// dataLaggingCheckDelay returns how long until the data, last synced at the
+// DRPC's last group sync time, lags
+func (d *DRPCInstance) dataLaggingCheckDelay() time.Duration {
+	if d.dataLaggingThreshold == 0 || d.instance.Status.LastGroupSyncTime == nil {
+		return 0
+	}
+
+	return time.Until(d.instance.Status.LastGroupSyncTime.Add(d.dataLaggingThreshold))
+}
+
+// dataLaggingCheckDue returns whether the data lags since the DRPC status was
+// last updated, so that the status is updated to flag it
+func (d *DRPCInstance) dataLaggingCheckDue() bool {
+	if d.dataLaggingThreshold == 0 || d.instance.Status.LastGroupSyncTime == nil {
+		return false
+	}
+
+	condition := findCondition(d.instance.Status.Conditions, rmn.ConditionDataLagging)
+	if condition != nil && condition.Status == metav1.ConditionTrue {
+		return false
+	}
+
+	return d.dataLaggingCheckDelay() <= 0
+}
+
 //nolint:exhaustive
 func (d *DRPCInstance) reportEvent(nextState rmn.DRState) {
 	eventReason := "unknown state"
@@ -2053,6 +2232,46 @@ var (
 			Buckets: prometheus.ExponentialBuckets(1.0, 2.0, 12), // start=1.0, factor=2.0, buckets=12
 		}),
 	)
+
+	rpo = &rpoCollector{
+		desc: prometheus.NewDesc(
+			"ramen_rpo_seconds",
+			"Seconds since the last group sync of the application data for individual DRPCs",
+			[]string{
+				"name",
+				"namespace",
+			},
+			nil,
+		),
+		lastGroupSyncTimes: make(map[types.NamespacedName]time.Time),
+	}
+
+	dataLagging = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ramen_data_lagging",
+			Help: "Whether the data of individual DRPCs is older than their data lagging threshold (1) or not (0)",
+		},
+		[]string{
+			"name",
+			"namespace",
+		},
+	)
+
+	dataLaggingThresholdSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ramen_data_lagging_threshold_seconds",
+			Help: "Age of the data beyond which individual DRPCs are flagged as lagging",
+		},
+		[]string{
+			"name",
+			"namespace",
+		},
+	)
+
+	dataLaggingTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ramen_data_lagging_total",
+		Help: "Number of times the data of any DRPC started lagging",
+	})
 )
 
 func init() {
@@ -2060,6 +2279,76 @@ func init() {
 	metrics.Registry.MustRegister(failoverTime.gauge, failoverTime.histogram)
 	metrics.Registry.MustRegister(relocateTime.gauge, relocateTime.histogram)
 	metrics.Registry.MustRegister(deployTime.gauge, deployTime.histogram)
+	metrics.Registry.MustRegister(rpo)
+	metrics.Registry.MustRegister(dataLagging, dataLaggingThresholdSeconds, dataLaggingTotal)
+}
+
+// rpoCollector reports the time elapsed since the last group sync time of each
+// DRPC, which is the data that would be lost if it were failed over now. The
+// age is computed when the metric is collected, rather than when the DRPC
+// status is updated, so that it does not go stale between reconciles.
+type rpoCollector struct {
+	desc               *prometheus.Desc
+	mutex              sync.Mutex
+	lastGroupSyncTimes map[types.NamespacedName]time.Time
+}
+
+func (c *rpoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *rpoCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, lastGroupSyncTime := range c.lastGroupSyncTimes {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue,
+			time.Since(lastGroupSyncTime).Seconds(), key.Name, key.Namespace)
+	}
+}
+
+func (c *rpoCollector) set(key types.NamespacedName, lastGroupSyncTime time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lastGroupSyncTimes[key] = lastGroupSyncTime
+}
+
+func (c *rpoCollector) delete(key types.NamespacedName) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.lastGroupSyncTimes, key)
+}
+
+// setRPOMetric records the last group sync time of the DRPC for its RPO metric
+func setRPOMetric(drpc *rmn.DRPlacementControl) {
+	if drpc.Status.LastGroupSyncTime == nil {
+		deleteRPOMetric(drpc)
+
+		return
+	}
+
+	rpo.set(types.NamespacedName{Name: drpc.Name, Namespace: drpc.Namespace}, drpc.Status.LastGroupSyncTime.Time)
+}
+
+func deleteRPOMetric(drpc *rmn.DRPlacementControl) {
+	rpo.delete(types.NamespacedName{Name: drpc.Name, Namespace: drpc.Namespace})
+}
+
+func setDataLaggingMetrics(drpc *rmn.DRPlacementControl, lagging bool, threshold time.Duration) {
+	value := 0.0
+	if lagging {
+		value = 1.0
+	}
+
+	dataLagging.WithLabelValues(drpc.Name, drpc.Namespace).Set(value)
+	dataLaggingThresholdSeconds.WithLabelValues(drpc.Name, drpc.Namespace).Set(threshold.Seconds())
+}
+
+func deleteDataLaggingMetrics(drpc *rmn.DRPlacementControl) {
+	dataLagging.DeleteLabelValues(drpc.Name, drpc.Namespace)
+	dataLaggingThresholdSeconds.DeleteLabelValues(drpc.Name, drpc.Namespace)
 }
 
 //nolint:exhaustive
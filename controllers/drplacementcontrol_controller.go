@@ -17,6 +17,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"time"
@@ -27,6 +28,7 @@ import (
 	errorswrapper "github.com/pkg/errors"
 	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
 	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -69,12 +71,13 @@ type ProgressCallback func(string, string)
 // DRPlacementControlReconciler reconciles a DRPlacementControl object
 type DRPlacementControlReconciler struct {
 	client.Client
-	APIReader     client.Reader
-	Log           logr.Logger
-	MCVGetter     rmnutil.ManagedClusterViewGetter
-	Scheme        *runtime.Scheme
-	Callback      ProgressCallback
-	eventRecorder *rmnutil.EventReporter
+	APIReader      client.Reader
+	Log            logr.Logger
+	MCVGetter      rmnutil.ManagedClusterViewGetter
+	ObjStoreGetter ObjectStoreGetter
+	Scheme         *runtime.Scheme
+	Callback       ProgressCallback
+	eventRecorder  *rmnutil.EventReporter
 }
 
 func ManifestWorkPredicateFunc() predicate.Funcs {
@@ -292,6 +295,9 @@ func (r *DRPlacementControlReconciler) SetupWithManager(mgr ctrl.Manager) error
 		Watches(&source.Kind{Type: &ocmworkv1.ManifestWork{}}, mwMapFun, builder.WithPredicates(mwPred)).
 		Watches(&source.Kind{Type: &viewv1beta1.ManagedClusterView{}}, mcvMapFun, builder.WithPredicates(mcvPred)).
 		Watches(&source.Kind{Type: &plrv1.PlacementRule{}}, usrPlRuleMapFun, builder.WithPredicates(usrPlRulePred)).
+		Watches(&source.Kind{Type: &rmn.DRPlacementControl{}},
+			handler.EnqueueRequestsFromMapFunc(r.drpcDependentsMapFunc),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
 }
 
@@ -341,9 +347,13 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, errorswrapper.Wrap(err, "failed to get DRPC object")
 	}
 
+	if restored, err := r.restoredStatusSet(ctx, drpc, logger); err != nil || restored {
+		return ctrl.Result{Requeue: restored}, err
+	}
+
 	usrPlRule, err := r.getUserPlacementRule(ctx, drpc, logger)
 	if err != nil {
-		r.recordFailure(drpc, usrPlRule, "Error", err.Error(), logger)
+		r.recordFailure(ctx, drpc, usrPlRule, "Error", err.Error(), logger)
 
 		return ctrl.Result{}, err
 	}
@@ -357,7 +367,7 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	d, err := r.createDRPCInstance(ctx, drpc, usrPlRule, logger)
 	if err != nil && !errorswrapper.Is(err, InitialWaitTimeForDRPCPlacementRule) {
-		r.recordFailure(drpc, usrPlRule, "Error", err.Error(), logger)
+		r.recordFailure(ctx, drpc, usrPlRule, "Error", err.Error(), logger)
 
 		return ctrl.Result{}, err
 	}
@@ -365,7 +375,7 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if errorswrapper.Is(err, InitialWaitTimeForDRPCPlacementRule) {
 		const initialWaitTime = 5
 
-		r.recordFailure(drpc, usrPlRule, "Waiting",
+		r.recordFailure(ctx, drpc, usrPlRule, "Waiting",
 			fmt.Sprintf("%v - wait time: %v", InitialWaitTimeForDRPCPlacementRule, initialWaitTime), logger)
 
 		return ctrl.Result{RequeueAfter: time.Second * initialWaitTime}, nil
@@ -374,12 +384,52 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 	return r.reconcileDRPCInstance(d, logger)
 }
 
-func (r *DRPlacementControlReconciler) recordFailure(drpc *rmn.DRPlacementControl,
+// restoredStatusSet sets the status of a DRPC restored from a hub backup from
+// its restored status annotation, unless it has a status already, and then
+// removes the annotation. It returns whether it did, so that the DRPC is
+// processed only once its status is restored.
+func (r *DRPlacementControlReconciler) restoredStatusSet(ctx context.Context, drpc *rmn.DRPlacementControl,
+	log logr.Logger,
+) (bool, error) {
+	value, ok := drpc.GetAnnotations()[DRPCRestoredStatusAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	if drpc.Status.Phase == "" {
+		restoredStatus := rmn.DRPlacementControlStatus{}
+
+		if err := json.Unmarshal([]byte(value), &restoredStatus); err != nil {
+			log.Error(err, "DRPC restored status annotation invalid; ignored", "value", value)
+		} else {
+			drpc.Status.Phase = restoredStatus.Phase
+			drpc.Status.PreferredDecision = restoredStatus.PreferredDecision
+
+			if err := r.Status().Update(ctx, drpc); err != nil {
+				return false, fmt.Errorf("failed to update DRPC restored status %w", err)
+			}
+
+			log.Info("DRPC status restored", "phase", drpc.Status.Phase)
+		}
+	}
+
+	delete(drpc.Annotations, DRPCRestoredStatusAnnotation)
+
+	if err := r.Update(ctx, drpc); err != nil {
+		return false, fmt.Errorf("failed to remove DRPC restored status annotation %w", err)
+	}
+
+	return true, nil
+}
+
+// recordFailure sets the DRPC Available condition False. Its data lag is not
+// checked, as its DRPolicy and the ramen config may not have been loaded.
+func (r *DRPlacementControlReconciler) recordFailure(ctx context.Context, drpc *rmn.DRPlacementControl,
 	usrPlRule *plrv1.PlacementRule, reason, msg string, log logr.Logger) {
 	needsUpdate := SetDRPCStatusCondition(&drpc.Status.Conditions, rmn.ConditionAvailable,
 		drpc.Generation, metav1.ConditionFalse, reason, msg)
 	if needsUpdate {
-		err := r.updateDRPCStatus(drpc, usrPlRule, log)
+		err := r.updateDRPCStatus(ctx, drpc, usrPlRule, 0, log)
 		if err != nil {
 			log.Info(fmt.Sprintf("Failed to update DRPC status (%v)", err))
 		}
@@ -394,6 +444,15 @@ func (r *DRPlacementControlReconciler) createDRPCInstance(ctx context.Context,
 		return nil, fmt.Errorf("failed to get DRPolicy %w", err)
 	}
 
+	_, ramenConfig, err := ConfigMapGet(ctx, r.APIReader)
+	if err != nil {
+		return nil, fmt.Errorf("configmap get: %w", err)
+	}
+
+	if err := drpcProtectedNamespacesAllowed(drpc, ramenConfig); err != nil {
+		return nil, err
+	}
+
 	if err := r.addLabelsAndFinalizers(ctx, drpc, usrPlRule, log); err != nil {
 		return nil, err
 	}
@@ -432,22 +491,19 @@ func (r *DRPlacementControlReconciler) createDRPCInstance(ctx context.Context,
 		return nil, err
 	}
 
-	_, ramenConfig, err := ConfigMapGet(ctx, r.APIReader)
-	if err != nil {
-		return nil, fmt.Errorf("configmap get: %w", err)
-	}
-
 	d := &DRPCInstance{
-		reconciler:        r,
-		ctx:               ctx,
-		log:               log,
-		instance:          drpc,
-		userPlacementRule: usrPlRule,
-		drpcPlacementRule: drpcPlRule,
-		drPolicy:          drPolicy,
-		drClusters:        drClusters,
-		vrgs:              vrgs,
-		volSyncDisabled:   ramenConfig.VolSync.Disabled,
+		reconciler:                  r,
+		ctx:                         ctx,
+		log:                         log,
+		instance:                    drpc,
+		userPlacementRule:           usrPlRule,
+		drpcPlacementRule:           drpcPlRule,
+		drPolicy:                    drPolicy,
+		drClusters:                  drClusters,
+		vrgs:                        vrgs,
+		volSyncDisabled:             ramenConfig.VolSync.Disabled,
+		volSyncSecretRotationPeriod: ramenConfig.VolSync.SecretRotationPeriod.Duration,
+		dataLaggingThreshold:        drpcDataLaggingThresholdOrZero(drPolicy, ramenConfig),
 		mwu: rmnutil.MWUtil{
 			Client:        r.Client,
 			Ctx:           ctx,
@@ -506,6 +562,11 @@ func (r *DRPlacementControlReconciler) reconcileDRPCInstance(d *DRPCInstance, lo
 	// Last status update time AFTER processing
 	afterProcessing := d.instance.Status.LastUpdateTime
 	requeueTimeDuration := r.getSanityCheckDelay(beforeProcessing, afterProcessing)
+
+	if delay := d.dataLaggingCheckDelay(); delay > 0 && delay < requeueTimeDuration {
+		requeueTimeDuration = delay
+	}
+
 	log.Info("Requeue time", "duration", requeueTimeDuration)
 
 	return ctrl.Result{RequeueAfter: requeueTimeDuration}, nil
@@ -626,6 +687,10 @@ func (r *DRPlacementControlReconciler) finalizeDRPC(ctx context.Context, drpc *r
 		return fmt.Errorf("failed to get DRPolicy while finalizing DRPC (%w)", err)
 	}
 
+	if err := r.finalizeDrill(drpc, mwu); err != nil {
+		return err
+	}
+
 	// delete manifestworks (VRGs)
 	for _, drClusterName := range rmnutil.DrpolicyClusterNames(drPolicy) {
 		err := mwu.DeleteManifestWorksForCluster(drClusterName)
@@ -644,10 +709,38 @@ func (r *DRPlacementControlReconciler) finalizeDRPC(ctx context.Context, drpc *r
 		return fmt.Errorf("waiting for VRGs count to go to zero")
 	}
 
+	deleteRPOMetric(drpc)
+	deleteDataLaggingMetrics(drpc)
+	deleteReadinessS3Probe(drpc)
+
 	// delete MCVs used in the previous call
 	return r.deleteAllManagedClusterViews(drpc, rmnutil.DrpolicyClusterNames(drPolicy))
 }
 
+// finalizeDrill deletes the drill VRG and namespace manifestworks, if a failover drill was started
+func (r *DRPlacementControlReconciler) finalizeDrill(drpc *rmn.DRPlacementControl, mwu rmnutil.MWUtil) error {
+	drill := drpc.Status.Drill
+	if drill == nil || drill.Cluster == "" {
+		return nil
+	}
+
+	if err := mwu.DeleteManifestWork(mwu.BuildManifestWorkName(rmnutil.MWTypeDrill), drill.Cluster); err != nil {
+		return fmt.Errorf("failed to delete drill VRG ManifestWork %w", err)
+	}
+
+	if err := mwu.DeleteManifestWork(
+		rmnutil.ManifestWorkName(drpc.Name, drill.Namespace, rmnutil.MWTypeNS), drill.Cluster); err != nil {
+		return fmt.Errorf("failed to delete drill namespace ManifestWork %w", err)
+	}
+
+	if err := r.MCVGetter.DeleteVRGManagedClusterView(drpc.Name, drill.Namespace, drill.Cluster,
+		rmnutil.MWTypeVRG); err != nil {
+		return fmt.Errorf("failed to delete drill VRG MCV %w", err)
+	}
+
+	return nil
+}
+
 func (r *DRPlacementControlReconciler) deleteAllManagedClusterViews(
 	drpc *rmn.DRPlacementControl, clusterNames []string) error {
 	// Only after the VRGs have been deleted, we delete the MCVs for the VRGs and the NS
@@ -658,10 +751,13 @@ func (r *DRPlacementControlReconciler) deleteAllManagedClusterViews(
 			return fmt.Errorf("failed to delete VRG MCV %w", err)
 		}
 
-		err = r.MCVGetter.DeleteNamespaceManagedClusterView(drpc.Name, drpc.Namespace, drClusterName, rmnutil.MWTypeNS)
-		// Delete MCV for Namespace
-		if err != nil {
-			return fmt.Errorf("failed to delete namespace MCV %w", err)
+		// Delete MCVs for Namespaces
+		for _, namespaceName := range drpcNamespaceNames(drpc) {
+			err = r.MCVGetter.DeleteNamespaceManagedClusterView(drpc.Name, namespaceName, drClusterName,
+				rmnutil.MWTypeNS)
+			if err != nil {
+				return fmt.Errorf("failed to delete namespace MCV %w", err)
+			}
 		}
 	}
 
@@ -941,6 +1037,81 @@ func (r *DRPlacementControlReconciler) getSanityCheckDelay(
 	return time.Until(beforeProcessing.Add(SanityCheckDelay))
 }
 
+// drpcDataLaggingThresholdOrZero returns the age beyond which the data of the
+// DRPCs of a DRPolicy lags, or 0 if their data is not replicated on a schedule
+func drpcDataLaggingThresholdOrZero(drPolicy *rmn.DRPolicy, ramenConfig *rmn.RamenConfig) time.Duration {
+	threshold, err := dataLaggingThreshold(drPolicy.Spec.SchedulingInterval, ramenConfig)
+	if err != nil {
+		return 0
+	}
+
+	return threshold
+}
+
+// updateDataLaggingCondition flags the DRPC, with a condition, an event and
+// metrics, while its data on the peer cluster is older than the configured
+// multiple of the scheduling interval. The condition is added the first time
+// the data lags, and is reset to False once the data is synced again. It is
+// Unknown while the sync time is unknown, as when a PVC never synced, or when
+// the primary VRG cannot be viewed and its sync time was not viewed before.
+// While the primary VRG cannot be viewed, the data lags from the sync time
+// viewed last. The DRPC is not checked if the threshold is 0.
+func (r *DRPlacementControlReconciler) updateDataLaggingCondition(drpc *rmn.DRPlacementControl,
+	threshold time.Duration, primaryViewed bool, log logr.Logger) {
+	if threshold == 0 {
+		return
+	}
+
+	condition := findCondition(drpc.Status.Conditions, rmn.ConditionDataLagging)
+	wasLagging := condition != nil && condition.Status == metav1.ConditionTrue
+
+	if drpc.Status.LastGroupSyncTime == nil {
+		reason, msg := rmn.ReasonSyncTimeUnknown, "Data sync time unknown, as not every protected PVC reports it"
+		if !primaryViewed {
+			reason, msg = rmn.ReasonPrimaryUnavailable, "Data sync time unknown, as the primary VRG is unavailable"
+		}
+
+		SetDRPCStatusCondition(&drpc.Status.Conditions, rmn.ConditionDataLagging, drpc.Generation,
+			metav1.ConditionUnknown, reason, msg)
+		deleteDataLaggingMetrics(drpc)
+
+		return
+	}
+
+	lastGroupSyncTime := drpc.Status.LastGroupSyncTime.Time
+	lagging := time.Since(lastGroupSyncTime) > threshold
+
+	setDataLaggingMetrics(drpc, lagging, threshold)
+
+	switch {
+	case lagging:
+		msg := fmt.Sprintf("Data last synced at %s, which is more than %v ago",
+			lastGroupSyncTime.UTC().Format(time.RFC3339), threshold)
+		if !primaryViewed {
+			msg += ", and the primary VRG is unavailable"
+		}
+
+		SetDRPCStatusCondition(&drpc.Status.Conditions, rmn.ConditionDataLagging, drpc.Generation,
+			metav1.ConditionTrue, rmn.ReasonLagging, msg)
+
+		if !wasLagging {
+			log.Info("Data is lagging", "lastGroupSyncTime", lastGroupSyncTime, "threshold", threshold)
+			dataLaggingTotal.Inc()
+			rmnutil.ReportIfNotPresent(r.eventRecorder, drpc, corev1.EventTypeWarning,
+				rmnutil.EventReasonDataLagging, msg)
+		}
+	case condition != nil && condition.Status != metav1.ConditionFalse:
+		msg := fmt.Sprintf("Data synced within %v", threshold)
+		SetDRPCStatusCondition(&drpc.Status.Conditions, rmn.ConditionDataLagging, drpc.Generation,
+			metav1.ConditionFalse, rmn.ReasonInSync, msg)
+
+		if wasLagging {
+			rmnutil.ReportIfNotPresent(r.eventRecorder, drpc, corev1.EventTypeNormal,
+				rmnutil.EventReasonDataInSync, msg)
+		}
+	}
+}
+
 func (r *DRPlacementControlReconciler) updateUserPlacementRuleStatus(
 	usrPlRule *plrv1.PlacementRule, newStatus plrv1.PlacementRuleStatus, log logr.Logger) error {
 	if !reflect.DeepEqual(newStatus, usrPlRule.Status) {
@@ -957,8 +1128,10 @@ func (r *DRPlacementControlReconciler) updateUserPlacementRuleStatus(
 	return nil
 }
 
-func (r *DRPlacementControlReconciler) updateDRPCStatus(
-	drpc *rmn.DRPlacementControl, usrPlRule *plrv1.PlacementRule, log logr.Logger) error {
+// updateDRPCStatus updates the DRPC status from its primary VRG, and checks
+// whether its data lags by dataLaggingThreshold, unless that is 0
+func (r *DRPlacementControlReconciler) updateDRPCStatus(ctx context.Context, drpc *rmn.DRPlacementControl,
+	usrPlRule *plrv1.PlacementRule, dataLaggingThreshold time.Duration, log logr.Logger) error {
 	log.Info("Updating DRPC status")
 
 	annotations := make(map[string]string)
@@ -975,6 +1148,14 @@ func (r *DRPlacementControlReconciler) updateDRPCStatus(
 			log.Info("Failed to get VRG from managed cluster", "errMsg", err)
 
 			drpc.Status.ResourceConditions = rmn.VRGConditions{}
+
+			// Keep the sync time viewed last while the VRG cannot be viewed,
+			// as when its cluster is down, for the data lag to be evaluated
+			if errors.IsNotFound(err) {
+				drpc.Status.LastGroupSyncTime = nil
+			} else {
+				r.updateDataLaggingCondition(drpc, dataLaggingThreshold, false, log)
+			}
 		} else {
 			drpc.Status.ResourceConditions.ResourceMeta.Kind = vrg.Kind
 			drpc.Status.ResourceConditions.ResourceMeta.Name = vrg.Name
@@ -984,13 +1165,27 @@ func (r *DRPlacementControlReconciler) updateDRPCStatus(
 
 			protectedPVCs := []string{}
 			for _, protectedPVC := range vrg.Status.ProtectedPVCs {
+				// PVCs in other protected namespaces are qualified by their namespace
+				if protectedPVC.Namespace != "" && protectedPVC.Namespace != vrg.Namespace {
+					protectedPVCs = append(protectedPVCs, protectedPVC.Namespace+"/"+protectedPVC.Name)
+
+					continue
+				}
+
 				protectedPVCs = append(protectedPVCs, protectedPVC.Name)
 			}
 
 			drpc.Status.ResourceConditions.ResourceMeta.ProtectedPVCs = protectedPVCs
+			drpc.Status.LastGroupSyncTime = vrg.Status.LastGroupSyncTime
+
+			if vrg.Spec.Async.Mode == rmn.AsyncModeEnabled {
+				r.updateDataLaggingCondition(drpc, dataLaggingThreshold, true, log)
+			}
 		}
 	}
 
+	setRPOMetric(drpc)
+
 	drpc.Status.LastUpdateTime = metav1.Now()
 	for i, condition := range drpc.Status.Conditions {
 		if condition.ObservedGeneration != drpc.Generation {
@@ -998,7 +1193,7 @@ func (r *DRPlacementControlReconciler) updateDRPCStatus(
 		}
 	}
 
-	if err := r.Status().Update(context.TODO(), drpc); err != nil {
+	if err := r.Status().Update(ctx, drpc); err != nil {
 		return errorswrapper.Wrap(err, "failed to update DRPC status")
 	}
 
@@ -161,6 +161,8 @@ func FakeProgressCallback(drpcName string, state string) {
 
 var restorePVs = true
 
+var resyncVRGs = true
+
 type FakeMCVGetter struct{}
 
 func getNamespaceObj(namespaceName string) *corev1.Namespace {
@@ -223,7 +225,8 @@ func (f FakeMCVGetter) GetVRGFromManagedCluster(resourceName, resourceNamespace,
 				ObservedGeneration: 1,
 			},
 		},
-		ProtectedPVCs: []rmn.ProtectedPVC{},
+		ProtectedPVCs:     []rmn.ProtectedPVC{},
+		LastGroupSyncTime: &metav1.Time{Time: time.Now().Add(-time.Minute)},
 	}
 	vrg := baseVRG.DeepCopy()
 	vrg.Status = vrgStatus
@@ -246,14 +249,35 @@ func (f FakeMCVGetter) GetVRGFromManagedCluster(resourceName, resourceNamespace,
 		return vrg, nil
 
 	case "ensureVRGIsSecondaryOnCluster":
+		if !resyncVRGs {
+			// the old primary's VRG is back, but has yet to be demoted
+			return vrg, nil
+		}
+
 		return moveVRGToSecondary(managedCluster, "vrg", false)
 
 	case "ensureDataProtectedOnCluster":
 		return moveVRGToSecondary(managedCluster, "vrg", true)
 
-	case "ensureVRGDeleted":
+	case "ensureVRGDeleted", "cleanupDrill":
 		return nil, errors.NewNotFound(schema.GroupResource{}, "requested resource not found in ManagedCluster")
 
+	case "updateDrillStatus":
+		vrg.Namespace = resourceNamespace
+		vrg.Status.Conditions = append(vrg.Status.Conditions, metav1.Condition{
+			Type:               controllers.VRGConditionTypeClusterDataReady,
+			Reason:             controllers.VRGConditionReasonClusterDataCorrupted,
+			Status:             metav1.ConditionFalse,
+			Message:            "Cluster data corrupted",
+			LastTransitionTime: metav1.Now(),
+			ObservedGeneration: vrg.Generation,
+		})
+
+		return vrg, nil
+
+	case "vrgClustersView":
+		return adoptionVRGView(resourceName, resourceNamespace, managedCluster)
+
 	case "getVRGsFromManagedClusters":
 		vrgFromMW, err := getVRGFromManifestWork(managedCluster)
 		if err != nil {
@@ -360,6 +384,8 @@ func getVRGFromManifestWork(managedCluster string) (*rmn.VolumeReplicationGroup,
 		ObservedGeneration: vrg.Generation,
 	})
 
+	vrg.Status.LastGroupSyncTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+
 	return vrg, nil
 }
 
@@ -450,6 +476,40 @@ func createDRPC(userPlacementRuleName, name, namespace, drPolicyName string) *rm
 	return drpc
 }
 
+// createDRPCDependency creates a DRPC, depending on others, that never
+// becomes available, as it refers to a PlacementRule that does not exist
+func createDRPCDependency(name string, dependsOn ...string) *rmn.DRPlacementControl {
+	drpc := &rmn.DRPlacementControl{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: DRPCNamespaceName},
+		Spec: rmn.DRPlacementControlSpec{
+			PlacementRef: corev1.ObjectReference{Name: name, Kind: "PlacementRule"},
+			DRPolicyRef:  corev1.ObjectReference{Name: AsyncDRPolicyName},
+		},
+	}
+
+	for _, dependencyName := range dependsOn {
+		drpc.Spec.DependsOn = append(drpc.Spec.DependsOn, rmn.DRPCReference{Name: dependencyName})
+	}
+
+	Expect(k8sClient.Create(context.TODO(), drpc)).Should(Succeed())
+
+	return drpc
+}
+
+func verifyDRPCDependenciesResolved(reason string, messages ...string) {
+	Eventually(func(g Gomega) {
+		drpc := getLatestDRPC()
+		_, condition := getDRPCCondition(&drpc.Status, rmn.ConditionDependenciesResolved)
+		g.Expect(condition).NotTo(BeNil())
+		g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		g.Expect(condition.Reason).To(Equal(reason))
+
+		for _, message := range messages {
+			g.Expect(condition.Message).To(ContainSubstring(message))
+		}
+	}, timeout, interval).Should(Succeed())
+}
+
 func deleteUserPlacementRule() {
 	userPlacementRule := getLatestUserPlacementRule(UserPlacementRuleName, DRPCNamespaceName)
 	Expect(k8sClient.Delete(context.TODO(), userPlacementRule)).Should(Succeed())
@@ -492,6 +552,48 @@ func setDRPCSpecExpectationTo(action rmn.DRAction, preferredCluster, failoverClu
 	}, timeout, interval).Should(BeTrue(), "failed to update DRPC DR action on time")
 }
 
+func updateDRPCSpec(update func(spec *rmn.DRPlacementControlSpec)) {
+	localRetries := 0
+	for localRetries < updateRetries {
+		latestDRPC := getLatestDRPC()
+
+		update(&latestDRPC.Spec)
+		err := k8sClient.Update(context.TODO(), latestDRPC)
+
+		if errors.IsConflict(err) {
+			localRetries++
+
+			time.Sleep(time.Millisecond * 5)
+
+			continue
+		}
+
+		Expect(err).NotTo(HaveOccurred())
+
+		break
+	}
+
+	Expect(localRetries).ToNot(Equal(updateRetries))
+}
+
+func verifyVRGClusterDataRetention(managedCluster string, retention *rmn.ClusterDataRetentionSpec) {
+	Eventually(func() *rmn.ClusterDataRetentionSpec {
+		vrg, err := getVRGFromManifestWork(managedCluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		return vrg.Spec.ClusterDataRetention
+	}, timeout, interval).Should(Equal(retention), "VRG cluster data retention not updated on time")
+}
+
+func verifyVRGKubeObjectProtection(managedCluster string, kubeObjectProtection *rmn.KubeObjectProtectionSpec) {
+	Eventually(func() *rmn.KubeObjectProtectionSpec {
+		vrg, err := getVRGFromManifestWork(managedCluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		return vrg.Spec.KubeObjectProtection
+	}, timeout, interval).Should(Equal(kubeObjectProtection), "VRG kube object protection not updated on time")
+}
+
 func getLatestDRPC() *rmn.DRPlacementControl {
 	drpcLookupKey := types.NamespacedName{
 		Name:      DRPCName,
@@ -1102,11 +1204,11 @@ func runFailoverAction(userPlacementRule *plrv1.PlacementRule, fromCluster, toCl
 	}
 
 	drpc := getLatestDRPC()
-	// At this point expect the DRPC status condition to have 2 types
-	// {Available and PeerReady}
+	// At this point expect the DRPC status condition to have 4 types
+	// {Available, PeerReady, ReadyForFailover and ReadyForRelocate}
 	// Final state is 'FailedOver'
 	Expect(drpc.Status.Phase).To(Equal(rmn.FailedOver))
-	Expect(len(drpc.Status.Conditions)).To(Equal(2))
+	Expect(len(drpc.Status.Conditions)).To(Equal(4))
 	_, condition := getDRPCCondition(&drpc.Status, rmn.ConditionAvailable)
 	Expect(condition.Reason).To(Equal(string(rmn.FailedOver)))
 
@@ -1122,12 +1224,12 @@ func clearDRActionAfterFailover(userPlacementRule *plrv1.PlacementRule, preferre
 	waitForUpdateDRPCStatus()
 
 	drpc := getLatestDRPC()
-	// At this point expect the DRPC status condition to have 2 types
-	// {Available and PeerReady}
+	// At this point expect the DRPC status condition to have 4 types
+	// {Available, PeerReady, ReadyForFailover and ReadyForRelocate}
 	// Final state didn't change and it is 'FailedOver' even though we tried to run
 	// initial deployment
 	Expect(drpc.Status.Phase).To(Equal(rmn.FailedOver))
-	Expect(len(drpc.Status.Conditions)).To(Equal(2))
+	Expect(len(drpc.Status.Conditions)).To(Equal(4))
 	_, condition := getDRPCCondition(&drpc.Status, rmn.ConditionAvailable)
 	Expect(condition.Reason).To(Equal(string(rmn.FailedOver)))
 
@@ -1175,11 +1277,11 @@ func runRelocateAction(userPlacementRule *plrv1.PlacementRule, fromCluster strin
 	}
 
 	drpc := getLatestDRPC()
-	// At this point expect the DRPC status condition to have 2 types
-	// {Available and PeerReady}
+	// At this point expect the DRPC status condition to have 4 types
+	// {Available, PeerReady, ReadyForFailover and ReadyForRelocate}
 	// Final state is 'Relocated'
 	Expect(drpc.Status.Phase).To(Equal(rmn.Relocated))
-	Expect(len(drpc.Status.Conditions)).To(Equal(2))
+	Expect(len(drpc.Status.Conditions)).To(Equal(4))
 	_, condition := getDRPCCondition(&drpc.Status, rmn.ConditionAvailable)
 	Expect(condition.Reason).To(Equal(string(rmn.Relocated)))
 
@@ -1197,12 +1299,12 @@ func clearDRActionAfterRelocate(userPlacementRule *plrv1.PlacementRule, preferre
 	waitForCompletion(string(rmn.Relocated))
 
 	drpc := getLatestDRPC()
-	// At this point expect the DRPC status condition to have 2 types
-	// {Available and PeerReady}
+	// At this point expect the DRPC status condition to have 4 types
+	// {Available, PeerReady, ReadyForFailover and ReadyForRelocate}
 	// Final state didn't change and it is 'Relocated' even though we tried to run
 	// initial deployment
 	Expect(drpc.Status.Phase).To(Equal(rmn.Relocated))
-	Expect(len(drpc.Status.Conditions)).To(Equal(2))
+	Expect(len(drpc.Status.Conditions)).To(Equal(4))
 	_, condition := getDRPCCondition(&drpc.Status, rmn.ConditionAvailable)
 	Expect(condition.Reason).To(Equal(string(rmn.Relocated)))
 
@@ -1226,6 +1328,66 @@ func relocateToPreferredCluster(userPlacementRule *plrv1.PlacementRule, fromClus
 	waitForCompletion(string(rmn.Relocated))
 }
 
+func waitForDRPCProgression(progression rmn.ProgressionStatus) {
+	Eventually(func() rmn.ProgressionStatus {
+		return getLatestDRPC().Status.Progression
+	}, timeout, interval).Should(Equal(progression), "failed to wait for DRPC progression")
+}
+
+func waitForFailbackUnfencing(userPlacementRule *plrv1.PlacementRule, fromCluster string) {
+	resyncVRGs = false
+
+	setDRPCSpecExpectationTo(rmn.ActionFailback, East1ManagedCluster, fromCluster)
+	waitForDRPCProgression(rmn.ProgressionUnfencingCluster)
+	verifyUserPlacementRuleDecisionUnchanged(userPlacementRule.Name, userPlacementRule.Namespace, fromCluster)
+
+	// Without UnfenceOnFailback, the failback leaves the fenced cluster as is
+	drcluster := getLatestDRCluster(East1ManagedCluster)
+	Expect(drcluster.Spec.ClusterFence).To(Equal(rmn.ClusterFenceStateFenced))
+}
+
+func waitForFailbackResync(userPlacementRule *plrv1.PlacementRule, fromCluster string) {
+	updateDRPCSpec(func(spec *rmn.DRPlacementControlSpec) {
+		spec.UnfenceOnFailback = true
+	})
+
+	Eventually(func() bool {
+		drcluster := getLatestDRCluster(East1ManagedCluster)
+		condition := getDRClusterCondition(&drcluster.Status, rmn.DRClusterConditionTypeFenced)
+
+		return drcluster.Spec.ClusterFence == rmn.ClusterFenceStateUnfenced &&
+			condition != nil && condition.ObservedGeneration == drcluster.Generation &&
+			condition.Status == metav1.ConditionFalse
+	}, timeout, interval).Should(BeTrue(), "failed to wait for the failback to unfence the DRCluster")
+
+	waitForDRPCProgression(rmn.ProgressionWaitingForResync)
+	verifyUserPlacementRuleDecisionUnchanged(userPlacementRule.Name, userPlacementRule.Namespace, fromCluster)
+	Expect(getLatestDRPC().Status.Phase).To(Equal(rmn.Initiating))
+}
+
+func runFailbackAction(userPlacementRule *plrv1.PlacementRule, fromCluster string) {
+	resyncVRGs = true
+
+	updateManifestWorkStatus(East1ManagedCluster, "vrg", ocmworkv1.WorkApplied)
+
+	verifyUserPlacementRuleDecision(userPlacementRule.Name, userPlacementRule.Namespace, East1ManagedCluster)
+	verifyDRPCStatusPreferredClusterExpectation(rmn.Relocated)
+	verifyVRGManifestWorkCreatedAsPrimary(East1ManagedCluster)
+
+	waitForVRGMWDeletion(fromCluster)
+
+	waitForCompletion(string(rmn.Relocated))
+
+	drpc := getLatestDRPC()
+	// A failback ends as a relocate does, with the application back on the preferred cluster
+	Expect(drpc.Status.Phase).To(Equal(rmn.Relocated))
+	_, condition := getDRPCCondition(&drpc.Status, rmn.ConditionAvailable)
+	Expect(condition.Reason).To(Equal(string(rmn.Relocated)))
+
+	userPlacementRule = getLatestUserPlacementRule(userPlacementRule.Name, userPlacementRule.Namespace)
+	Expect(userPlacementRule.Status.Decisions[0].ClusterName).To(Equal(East1ManagedCluster))
+}
+
 func recoverToFailoverCluster(userPlacementRule *plrv1.PlacementRule, fromCluster, toCluster string) {
 	setDRPCSpecExpectationTo(rmn.ActionFailover, fromCluster, toCluster)
 
@@ -1397,17 +1559,77 @@ func verifyInitialDRPCDeployment(userPlacementRule *plrv1.PlacementRule, drpc *r
 	waitForCompletion(string(rmn.Deployed))
 
 	latestDRPC := getLatestDRPC()
-	// At this point expect the DRPC status condition to have 2 types
-	// {Available and PeerReady}
+	// At this point expect the DRPC status condition to have 4 types
+	// {Available, PeerReady, ReadyForFailover and ReadyForRelocate}
 	// Final state is 'Deployed'
 	Expect(latestDRPC.Status.Phase).To(Equal(rmn.Deployed))
-	Expect(len(latestDRPC.Status.Conditions)).To(Equal(2))
+	Expect(len(latestDRPC.Status.Conditions)).To(Equal(4))
 	_, condition := getDRPCCondition(&latestDRPC.Status, rmn.ConditionAvailable)
 	Expect(condition.Reason).To(Equal(string(rmn.Deployed)))
 
+	drpolicy := &rmn.DRPolicy{}
+	Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: latestDRPC.Spec.DRPolicyRef.Name},
+		drpolicy)).To(Succeed())
+
+	for _, cluster := range drpolicy.Spec.DRClusters {
+		if cluster != preferredCluster {
+			verifyDRPCReadiness(latestDRPC, cluster)
+		}
+	}
+
 	val, err := rmnutil.GetMetricValueSingle("ramen_initial_deploy_time", dto.MetricType_GAUGE)
 	Expect(err).NotTo(HaveOccurred())
 	Expect(val).NotTo(Equal(0.0)) // failover time should be non-zero
+
+	Eventually(func() bool {
+		return getLatestDRPC().Status.LastGroupSyncTime != nil
+	}, timeout, interval).Should(BeTrue(), "DRPC status lastGroupSyncTime not set from the VRG")
+
+	val, err = rmnutil.GetMetricValueSingle("ramen_rpo_seconds", dto.MetricType_GAUGE)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(val).To(BeNumerically(">=", time.Minute.Seconds())) // RPO is at least the fake VRG sync age
+}
+
+// verifyDRPCReadiness verifies that a newly deployed DRPC is not ready for
+// either action, as the target cluster has no VRG yet
+func verifyDRPCReadiness(drpc *rmn.DRPlacementControl, targetCluster string) {
+	Expect(drpc.Status.Readiness).NotTo(BeNil())
+	Expect(drpc.Status.Readiness.TargetCluster).To(Equal(targetCluster))
+
+	checks := map[string]metav1.ConditionStatus{}
+	fencingActions := []rmn.DRAction{}
+
+	for _, check := range drpc.Status.Readiness.Checks {
+		checks[check.Name] = check.Status
+
+		if check.Name == rmn.ReadinessCheckTargetVRG {
+			Expect(check.Actions).To(ConsistOf(rmn.ActionFailover, rmn.ActionRelocate, rmn.ActionFailback))
+		}
+
+		if check.Name == rmn.ReadinessCheckFencing {
+			fencingActions = append(fencingActions, check.Actions...)
+		}
+	}
+
+	Expect(fencingActions).To(ConsistOf(rmn.ActionFailover, rmn.ActionRelocate, rmn.ActionFailback))
+	Expect(checks).To(HaveKeyWithValue(rmn.ReadinessCheckTargetVRG, metav1.ConditionFalse))
+	Expect(checks).To(HaveKey(rmn.ReadinessCheckClusterDataProtected))
+	Expect(checks).To(HaveKey(rmn.ReadinessCheckS3ProfileReachable))
+	Expect(checks).To(HaveKey(rmn.ReadinessCheckFencing))
+
+	for _, conditionType := range []string{rmn.ConditionReadyForFailover, rmn.ConditionReadyForRelocate} {
+		_, condition := getDRPCCondition(&drpc.Status, conditionType)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(rmn.ReadinessCheckTargetVRG))
+		Expect(condition.Message).To(HavePrefix(fmt.Sprintf("%s failed: no VRG found on cluster %s",
+			rmn.ReadinessCheckTargetVRG, targetCluster)))
+	}
+
+	// the readiness is not updated while the checks' results do not change
+	Consistently(func() metav1.Time {
+		return getLatestDRPC().Status.Readiness.CheckTime
+	}, 2*time.Second, interval).Should(Equal(drpc.Status.Readiness.CheckTime))
 }
 
 func verifyFailoverToSecondary(userPlacementRule *plrv1.PlacementRule, fromCluster, toCluster string,
@@ -1431,11 +1653,11 @@ func verifyFailoverToSecondary(userPlacementRule *plrv1.PlacementRule, fromClust
 	Expect(val).NotTo(Equal(0.0)) // failover time should be non-zero
 
 	drpc := getLatestDRPC()
-	// At this point expect the DRPC status condition to have 2 types
-	// {Available and PeerReady}
+	// At this point expect the DRPC status condition to have 4 types
+	// {Available, PeerReady, ReadyForFailover and ReadyForRelocate}
 	// Final state is 'FailedOver'
 	Expect(drpc.Status.Phase).To(Equal(rmn.FailedOver))
-	Expect(len(drpc.Status.Conditions)).To(Equal(2))
+	Expect(len(drpc.Status.Conditions)).To(Equal(4))
 	_, condition := getDRPCCondition(&drpc.Status, rmn.ConditionAvailable)
 	Expect(condition.Reason).To(Equal(string(rmn.FailedOver)))
 
@@ -1457,6 +1679,43 @@ var _ = Describe("DRPlacementControl Reconciler", func() {
 				userPlacementRule, drpc = InitialDeploymentAsync(DRPCNamespaceName, UserPlacementRuleName, East1ManagedCluster)
 				verifyInitialDRPCDeployment(userPlacementRule, drpc, East1ManagedCluster)
 			})
+			It("Should update the VRG with its cluster data retention", func() {
+				retention := &rmn.ClusterDataRetentionSpec{MaxCount: 3}
+				updateDRPCSpec(func(spec *rmn.DRPlacementControlSpec) { spec.ClusterDataRetention = retention })
+				verifyVRGClusterDataRetention(East1ManagedCluster, retention)
+				updateDRPCSpec(func(spec *rmn.DRPlacementControlSpec) { spec.ClusterDataRetention = nil })
+				verifyVRGClusterDataRetention(East1ManagedCluster, nil)
+			})
+			It("Should update the VRG with its kube object capture retention and capture to recover from", func() {
+				captureNumber := int64(2)
+				kubeObjectProtection := &rmn.KubeObjectProtectionSpec{
+					CaptureRetentionCount:    4,
+					RecoverFromCaptureNumber: &captureNumber,
+				}
+				updateDRPCSpec(func(spec *rmn.DRPlacementControlSpec) {
+					spec.KubeObjectProtection = kubeObjectProtection
+				})
+				verifyVRGKubeObjectProtection(East1ManagedCluster, kubeObjectProtection)
+				updateDRPCSpec(func(spec *rmn.DRPlacementControlSpec) { spec.KubeObjectProtection = nil })
+				verifyVRGKubeObjectProtection(East1ManagedCluster, nil)
+			})
+			It("Should fail a drill whose VRG reports corrupted cluster data", func() {
+				updateDRPCSpec(func(spec *rmn.DRPlacementControlSpec) {
+					spec.Drill = &rmn.DrillSpec{Cluster: West1ManagedCluster}
+				})
+				Eventually(func() rmn.DrillPhase {
+					drill := getLatestDRPC().Status.Drill
+					if drill == nil {
+						return ""
+					}
+
+					return drill.Phase
+				}, timeout, interval).Should(Equal(rmn.DrillFailed), "drill not failed on time")
+				updateDRPCSpec(func(spec *rmn.DRPlacementControlSpec) { spec.Drill = nil })
+				Eventually(func() *rmn.DrillStatus {
+					return getLatestDRPC().Status.Drill
+				}, timeout, interval).Should(BeNil(), "drill not cleaned up on time")
+			})
 		})
 		When("DRAction changes to Failover", func() {
 			It("Should not failover to Secondary (West1ManagedCluster) till PV manifest is applied", func() {
@@ -1524,6 +1783,39 @@ var _ = Describe("DRPlacementControl Reconciler", func() {
 				runRelocateAction(userPlacementRule, West1ManagedCluster, false, false)
 			})
 		})
+		When("A DRPC outside the admin namespace lists protected namespaces", func() {
+			It("Should fail DRPC reconciliation and not add a finalizer", func() {
+				const placementName = "multi-namespace-placement-rule"
+				createNamespace(appNamespace2)
+				placementRule := createPlacementRule(placementName, DRPC2NamespaceName)
+				drpc2 := &rmn.DRPlacementControl{
+					ObjectMeta: metav1.ObjectMeta{Name: DRPCName, Namespace: DRPC2NamespaceName},
+					Spec: rmn.DRPlacementControlSpec{
+						PlacementRef:        corev1.ObjectReference{Name: placementName, Kind: "PlacementRule"},
+						DRPolicyRef:         corev1.ObjectReference{Name: AsyncDRPolicyName},
+						ProtectedNamespaces: []string{DRPCNamespaceName},
+					},
+				}
+				Expect(k8sClient.Create(context.TODO(), drpc2)).Should(Succeed())
+				Eventually(func() string {
+					drpcl := &rmn.DRPlacementControl{}
+					Expect(apiReader.Get(context.TODO(), client.ObjectKeyFromObject(drpc2), drpcl)).To(Succeed())
+					_, condition := getDRPCCondition(&drpcl.Status, rmn.ConditionAvailable)
+					if condition == nil {
+						return ""
+					}
+
+					return condition.Message
+				}, timeout, interval).Should(ContainSubstring("protectedNamespaces not allowed"))
+				checkIfDRPCFinalizerNotAdded(drpc2)
+				Expect(k8sClient.Delete(context.TODO(), drpc2)).Should(Succeed())
+				Expect(k8sClient.Delete(context.TODO(), placementRule)).Should(Succeed())
+				Eventually(func() bool {
+					return errors.IsNotFound(apiReader.Get(context.TODO(), client.ObjectKeyFromObject(drpc2),
+						&rmn.DRPlacementControl{}))
+				}, timeout, interval).Should(BeTrue())
+			})
+		})
 		When("Deleting DRPolicy with DRPC references", func() {
 			It("Should retain the deleted DRPolicy in the API server", func() {
 				// ----------------------------- DELETE DRPolicy  --------------------------------------
@@ -1780,4 +2072,124 @@ var _ = Describe("DRPlacementControl Reconciler", func() {
 			})
 		})
 	})
+	Context("DRPlacementControl Reconciler Failback", func() {
+		userPlacementRule := &plrv1.PlacementRule{}
+		drpc := &rmn.DRPlacementControl{}
+		Specify("DRClusters", func() {
+			populateDRClusters()
+		})
+		When("An Application is deployed for the first time", func() {
+			It("Should deploy to East1ManagedCluster", func() {
+				By("Initial Deployment")
+				userPlacementRule, drpc = InitialDeploymentSync(DRPCNamespaceName, UserPlacementRuleName, East1ManagedCluster)
+				verifyInitialDRPCDeployment(userPlacementRule, drpc, East1ManagedCluster)
+			})
+		})
+		When("DRAction changes to Failover", func() {
+			It("Should fence Primary (East1ManagedCluster) and failover to Secondary (East2ManagedCluster)", func() {
+				By("\n\n*** Failover - 1\n\n")
+				runFailoverAction(userPlacementRule, East1ManagedCluster, East2ManagedCluster, true, false)
+			})
+		})
+		When("DRAction is set to Failback to a fenced cluster", func() {
+			It("Should wait for Primary (East1ManagedCluster) to be unfenced", func() {
+				// ----------------------------- FAILBACK TO PRIMARY --------------------------------------
+				By("\n\n*** Failback - 1\n\n")
+				waitForFailbackUnfencing(userPlacementRule, East2ManagedCluster)
+			})
+		})
+		When("UnfenceOnFailback is set", func() {
+			It("Should unfence Primary (East1ManagedCluster) and wait for its VRG to resync", func() {
+				waitForFailbackResync(userPlacementRule, East2ManagedCluster)
+			})
+		})
+		When("The VRG on Primary (East1ManagedCluster) is resynced", func() {
+			It("Should fail back to Primary (East1ManagedCluster)", func() {
+				runFailbackAction(userPlacementRule, East2ManagedCluster)
+			})
+		})
+		When("Deleting user PlacementRule", func() {
+			It("Should cleanup DRPC", func() {
+				By("\n\n*** DELETE User PlacementRule ***\n\n")
+				deleteUserPlacementRule()
+				drpc := getLatestDRPC()
+				_, condition := getDRPCCondition(&drpc.Status, rmn.ConditionPeerReady)
+				Expect(condition).NotTo(BeNil())
+			})
+		})
+		When("Deleting DRPC", func() {
+			It("Should delete VRG from Primary (East1ManagedCluster)", func() {
+				By("\n\n*** DELETE DRPC ***\n\n")
+				deleteDRPC()
+				waitForCompletion("deleted")
+				Expect(getManifestWorkCount(East1ManagedCluster)).Should(Equal(1)) // Roles MW
+				// see runRelocateAction
+				resetdrCluster(East1ManagedCluster)
+				deleteDRPolicySync()
+				deleteDRClustersSync()
+			})
+		})
+	})
+	Context("DRPlacementControl Reconciler Dependencies", func() {
+		userPlacementRule := &plrv1.PlacementRule{}
+		drpc := &rmn.DRPlacementControl{}
+		dependency := &rmn.DRPlacementControl{}
+		dependencyCycle := &rmn.DRPlacementControl{}
+		drpcKey := types.NamespacedName{Namespace: DRPCNamespaceName, Name: DRPCName}.String()
+		dependencyKey := types.NamespacedName{Namespace: DRPCNamespaceName, Name: "dependency"}.String()
+		dependencyCycleKey := types.NamespacedName{Namespace: DRPCNamespaceName, Name: "dependency-cycle"}.String()
+		Specify("DRClusters", func() {
+			populateDRClusters()
+		})
+		When("An Application is deployed for the first time", func() {
+			It("Should deploy to East1ManagedCluster", func() {
+				By("Initial Deployment")
+				userPlacementRule, drpc = InitialDeploymentAsync(DRPCNamespaceName, UserPlacementRuleName, East1ManagedCluster)
+				verifyInitialDRPCDeployment(userPlacementRule, drpc, East1ManagedCluster)
+			})
+		})
+		When("DRAction changes to Failover with a dependency that is not available", func() {
+			It("Should wait for the dependency, but not for a missing one or one it depends on itself through", func() {
+				dependency = createDRPCDependency("dependency")
+				dependencyCycle = createDRPCDependency("dependency-cycle", DRPCName)
+				updateDRPCSpec(func(spec *rmn.DRPlacementControlSpec) {
+					spec.DependsOn = []rmn.DRPCReference{
+						{Name: dependencyCycle.Name}, {Name: dependency.Name}, {Name: "dependency-missing"},
+					}
+				})
+				setDRPCSpecExpectationTo(rmn.ActionFailover, East1ManagedCluster, West1ManagedCluster)
+				updateManifestWorkStatus(West1ManagedCluster, "vrg", ocmworkv1.WorkApplied)
+				waitForDRPCProgression(rmn.ProgressionWaitingForDeps)
+				verifyDRPCDependenciesResolved(rmn.ReasonDependencyCycle,
+					fmt.Sprintf("dependency cycle %s -> %s -> %s", drpcKey, dependencyCycleKey, drpcKey),
+					fmt.Sprintf("DRPC %s/dependency-missing not found", DRPCNamespaceName))
+				Expect(getLatestDRPC().Status.Phase).To(Equal(rmn.FailingOver))
+				userPlacementRule = getLatestUserPlacementRule(userPlacementRule.Name, userPlacementRule.Namespace)
+				Expect(userPlacementRule.Status.Decisions).NotTo(ContainElement(
+					HaveField("ClusterName", West1ManagedCluster)))
+			})
+		})
+		When("The dependency waited for is deleted", func() {
+			It("Should failover to Secondary (West1ManagedCluster)", func() {
+				Expect(k8sClient.Delete(context.TODO(), dependency)).To(Succeed())
+				recoverToFailoverCluster(userPlacementRule, East1ManagedCluster, West1ManagedCluster)
+				verifyDRPCDependenciesResolved(rmn.ReasonDependencyCycle,
+					fmt.Sprintf("DRPC %s not found", dependencyKey))
+			})
+		})
+		When("Deleting DRPC", func() {
+			It("Should delete VRG from Secondary (West1ManagedCluster)", func() {
+				Expect(k8sClient.Delete(context.TODO(), dependencyCycle)).To(Succeed())
+				deleteUserPlacementRule()
+				deleteDRPC()
+				waitForCompletion("deleted")
+				Expect(getManifestWorkCount(West1ManagedCluster)).Should(Equal(1)) // Roles MW
+				deleteDRPolicyAsync()
+				ensureDRPolicyIsDeleted(drpc.Spec.DRPolicyRef.Name)
+			})
+		})
+		Specify("delete drclusters", func() {
+			deleteDRClustersAsync()
+		})
+	})
 })
@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// checkDependenciesAvailable returns an error naming the first DRPC, of those
+// this DRPC depends on, whose application is not available on a cluster. A
+// dependency that does not exist or is being deleted, or through which this
+// DRPC depends on itself, is not waited for, as it would hold the DRPC
+// forever; the DependenciesResolved condition reports each of them instead.
+func (d *DRPCInstance) checkDependenciesAvailable(clusterName string) error {
+	if len(d.instance.Spec.DependsOn) == 0 {
+		meta.RemoveStatusCondition(&d.instance.Status.Conditions, rmn.ConditionDependenciesResolved)
+
+		return nil
+	}
+
+	var availableErr error
+
+	reason, messages := rmn.ReasonSuccess, []string{}
+	self := types.NamespacedName{Namespace: d.instance.Namespace, Name: d.instance.Name}
+
+	for _, ref := range d.instance.Spec.DependsOn {
+		key := drpcDependencyKey(d.instance, ref)
+		if key == self {
+			reason, messages = drpcDependencyUnresolved(reason, messages, rmn.ReasonDependencyCycle,
+				fmt.Sprintf("dependency cycle %s -> %s", self, self))
+
+			continue
+		}
+
+		dependency := &rmn.DRPlacementControl{}
+		if err := d.reconciler.Get(d.ctx, key, dependency); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return fmt.Errorf("DRPC %s get: %w", key, err)
+			}
+
+			reason, messages = drpcDependencyUnresolved(reason, messages, rmn.ReasonDependencyNotFound,
+				fmt.Sprintf("DRPC %s not found", key))
+
+			continue
+		}
+
+		if !dependency.GetDeletionTimestamp().IsZero() {
+			reason, messages = drpcDependencyUnresolved(reason, messages, rmn.ReasonDependencyNotFound,
+				fmt.Sprintf("DRPC %s is being deleted", key))
+
+			continue
+		}
+
+		cycle, err := d.dependencyCycle(dependency, sets.NewString(key.String()))
+		if err != nil {
+			return err
+		}
+
+		if cycle != nil {
+			reason, messages = drpcDependencyUnresolved(reason, messages, rmn.ReasonDependencyCycle,
+				fmt.Sprintf("dependency cycle %s", strings.Join(append([]string{self.String(), key.String()},
+					cycle...), " -> ")))
+
+			continue
+		}
+
+		if availableErr == nil && !drpcAvailableOnCluster(dependency, clusterName) {
+			d.log.Info("Waiting for dependency", "DRPC", key, "cluster", clusterName)
+
+			availableErr = fmt.Errorf("DRPC %s not available on cluster %s", key, clusterName)
+		}
+	}
+
+	status, message := metav1.ConditionTrue, "Dependencies resolved"
+	if reason != rmn.ReasonSuccess {
+		status, message = metav1.ConditionFalse, strings.Join(messages, "; ")
+	}
+
+	d.setDRPCCondition(&d.instance.Status.Conditions, rmn.ConditionDependenciesResolved, d.instance.Generation,
+		status, reason, message)
+
+	return availableErr
+}
+
+// drpcDependencyUnresolved returns the reason of the first unresolved
+// dependency, and appends the message of another one
+func drpcDependencyUnresolved(reason string, messages []string, dependencyReason, message string,
+) (string, []string) {
+	if reason == rmn.ReasonSuccess {
+		reason = dependencyReason
+	}
+
+	return reason, append(messages, message)
+}
+
+// dependencyCycle returns the DRPCs through which a DRPC depends on this
+// DRPC, ending with this DRPC, or nil if it does not. The DRPCs visited
+// already are not visited again.
+func (d *DRPCInstance) dependencyCycle(drpc *rmn.DRPlacementControl, visited sets.String) ([]string, error) {
+	self := types.NamespacedName{Namespace: d.instance.Namespace, Name: d.instance.Name}
+
+	for _, ref := range drpc.Spec.DependsOn {
+		key := drpcDependencyKey(drpc, ref)
+		if key == self {
+			return []string{key.String()}, nil
+		}
+
+		if visited.Has(key.String()) {
+			continue
+		}
+
+		visited.Insert(key.String())
+
+		dependency := &rmn.DRPlacementControl{}
+		if err := d.reconciler.Get(d.ctx, key, dependency); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("DRPC %s get: %w", key, err)
+		}
+
+		cycle, err := d.dependencyCycle(dependency, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		if cycle != nil {
+			return append([]string{key.String()}, cycle...), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// drpcDependencyKey returns the namespaced name of a DRPC a DRPC depends on
+func drpcDependencyKey(drpc *rmn.DRPlacementControl, ref rmn.DRPCReference) types.NamespacedName {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = drpc.Namespace
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+// drpcAvailableOnCluster returns whether a DRPC placed its application on a
+// cluster, and reports it available for its current generation
+func drpcAvailableOnCluster(drpc *rmn.DRPlacementControl, clusterName string) bool {
+	if drpc.Status.PreferredDecision.ClusterName != clusterName {
+		return false
+	}
+
+	available := findCondition(drpc.Status.Conditions, rmn.ConditionAvailable)
+
+	return available != nil && available.Status == metav1.ConditionTrue &&
+		available.ObservedGeneration == drpc.Generation
+}
+
+// drpcDependentsMapFunc requests the reconciliation of the DRPCs that depend
+// on a DRPC that changed, as they may be waiting for it to be available
+func (r *DRPlacementControlReconciler) drpcDependentsMapFunc(obj client.Object) []reconcile.Request {
+	drpcs := &rmn.DRPlacementControlList{}
+	if err := r.Client.List(context.TODO(), drpcs); err != nil {
+		ctrl.Log.Info("Failed to list DRPCs", "error", err)
+
+		return []reconcile.Request{}
+	}
+
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	requests := make([]reconcile.Request, 0)
+
+	for i := range drpcs.Items {
+		drpc := &drpcs.Items[i]
+
+		for _, ref := range drpc.Spec.DependsOn {
+			if drpcDependencyKey(drpc, ref) == key {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name},
+				})
+
+				break
+			}
+		}
+	}
+
+	return requests
+}
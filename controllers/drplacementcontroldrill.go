@@ -0,0 +1,311 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const drillNamespaceSuffix = "-drill"
+
+// processDrill rehearses a failover of the application to a peer cluster, as
+// requested by spec.drill. A drill VRG is deployed to an isolated namespace on
+// the drill cluster, which restores the latest replicated PV cluster data (and
+// kube objects) of the application there. The primary VRG is left untouched.
+// Once spec.drill is removed, the drill VRG and its namespace are deleted.
+func (d *DRPCInstance) processDrill() (bool, error) {
+	const done = true
+
+	if d.instance.Spec.Drill == nil {
+		return d.cleanupDrill()
+	}
+
+	drillCluster, drillNamespace, err := d.drillTarget()
+	if err != nil {
+		d.setDrillFailed(err.Error())
+
+		return done, err
+	}
+
+	status := d.instance.Status.Drill
+	if status != nil && (status.Cluster != drillCluster || status.Namespace != drillNamespace) {
+		d.log.Info("Drill target changed, cleaning up previous drill",
+			"cluster", status.Cluster, "namespace", status.Namespace)
+
+		if _, err := d.cleanupDrill(); err != nil {
+			return !done, err
+		}
+
+		return !done, nil
+	}
+
+	if status == nil {
+		d.instance.Status.Drill = &rmn.DrillStatus{
+			Cluster:   drillCluster,
+			Namespace: drillNamespace,
+			Phase:     rmn.DrillRunning,
+			StartTime: &metav1.Time{Time: time.Now()},
+			Message:   "Starting drill",
+		}
+
+		rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeNormal,
+			rmnutil.EventReasonDrillStarted,
+			fmt.Sprintf("Started failover drill to namespace %q on cluster %q", drillNamespace, drillCluster))
+	}
+
+	if err := d.createDrillManifestWorks(drillCluster, drillNamespace); err != nil {
+		d.setDrillFailed(err.Error())
+
+		return !done, err
+	}
+
+	return d.updateDrillStatus(drillCluster, drillNamespace)
+}
+
+// drillTarget validates spec.drill and returns the drill cluster and namespace
+func (d *DRPCInstance) drillTarget() (string, string, error) {
+	drill := d.instance.Spec.Drill
+
+	drillCluster := drill.Cluster
+	if drillCluster == "" {
+		drillCluster = d.instance.Spec.FailoverCluster
+	}
+
+	if drillCluster == "" {
+		return "", "", fmt.Errorf("drill cluster not set and failover cluster not set")
+	}
+
+	if !containsString(rmnutil.DrpolicyClusterNames(d.drPolicy), drillCluster) {
+		return "", "", fmt.Errorf("drill cluster %s is not in DRPolicy %s", drillCluster, d.drPolicy.Name)
+	}
+
+	if drillCluster == d.getCurrentHomeClusterName() {
+		return "", "", fmt.Errorf("drill cluster %s is the current home cluster", drillCluster)
+	}
+
+	drillNamespace := drill.Namespace
+	if drillNamespace == "" {
+		drillNamespace = d.instance.Namespace + drillNamespaceSuffix
+	}
+
+	if drillNamespace == d.instance.Namespace {
+		return "", "", fmt.Errorf("drill namespace %s must differ from the application namespace", drillNamespace)
+	}
+
+	return drillCluster, drillNamespace, nil
+}
+
+func (d *DRPCInstance) drillAnnotations() map[string]string {
+	annotations := make(map[string]string)
+
+	annotations[DRPCNameAnnotation] = d.instance.Name
+	annotations[DRPCNamespaceAnnotation] = d.instance.Namespace
+
+	return annotations
+}
+
+func (d *DRPCInstance) createDrillManifestWorks(drillCluster, drillNamespace string) error {
+	annotations := d.drillAnnotations()
+
+	err := d.mwu.CreateOrUpdateNamespaceManifest(d.instance.Name, drillNamespace, drillCluster, annotations)
+	if err != nil {
+		return fmt.Errorf("failed to create drill namespace '%s' on cluster %s: %w", drillNamespace, drillCluster, err)
+	}
+
+	vrg := d.generateDrillVRG(drillNamespace)
+
+	if err := d.mwu.CreateOrUpdateDrillVRGManifestWork(
+		d.instance.Name, d.instance.Namespace,
+		drillCluster, vrg, annotations); err != nil {
+		return fmt.Errorf("failed to create or update drill VolumeReplicationGroup manifest in namespace %s (%w)",
+			drillCluster, err)
+	}
+
+	return nil
+}
+
+// generateDrillVRG returns a primary VRG for the drill namespace that restores
+// the cluster data of the application VRG. VolSync PVCs are not part of a drill,
+// as their data is not in the S3 store, and no action applies to the drill VRG.
+// The PVs of PVCs in other protected namespaces are restored to the drill
+// namespace as well, whereas their kube objects are not recovered.
+func (d *DRPCInstance) generateDrillVRG(drillNamespace string) rmn.VolumeReplicationGroup {
+	vrg := d.generateVRG(rmn.Primary)
+	vrg.Namespace = drillNamespace
+	vrg.Spec.Action = ""
+	vrg.Spec.VolSync.Disabled = true
+	vrg.Spec.Drill = &rmn.VRGDrillSpec{
+		SourceNamespace: d.instance.Namespace,
+		VerifierImage:   d.instance.Spec.Drill.VerifierImage,
+	}
+	vrg.Spec.ProtectedNamespaces = nil
+
+	return vrg
+}
+
+func (d *DRPCInstance) updateDrillStatus(drillCluster, drillNamespace string) (bool, error) {
+	const done = true
+
+	status := d.instance.Status.Drill
+
+	vrg, err := d.reconciler.MCVGetter.GetVRGFromManagedCluster(d.instance.Name, drillNamespace, drillCluster,
+		d.drillAnnotations())
+	if err != nil {
+		d.log.Info("Drill VRG not available yet", "cluster", drillCluster, "error", err.Error())
+
+		return !done, nil
+	}
+
+	status.ResourceConditions.ResourceMeta.Kind = vrg.Kind
+	status.ResourceConditions.ResourceMeta.Name = vrg.Name
+	status.ResourceConditions.ResourceMeta.Namespace = vrg.Namespace
+	status.ResourceConditions.ResourceMeta.Generation = vrg.Generation
+	status.ResourceConditions.Conditions = vrg.Status.Conditions
+
+	if status.Phase == rmn.DrillSucceeded {
+		return done, nil
+	}
+
+	clusterDataReady := findCondition(vrg.Status.Conditions, VRGConditionTypeClusterDataReady)
+	dataReady := findCondition(vrg.Status.Conditions, VRGConditionTypeDataReady)
+
+	for _, condition := range []*metav1.Condition{clusterDataReady, dataReady} {
+		if drillConditionFailed(condition, vrg.Generation) {
+			d.setDrillFailed(condition.Message)
+
+			return !done, nil
+		}
+	}
+
+	for _, condition := range []*metav1.Condition{clusterDataReady, dataReady} {
+		if condition == nil || condition.Status != metav1.ConditionTrue ||
+			condition.ObservedGeneration != vrg.Generation {
+			status.Phase = rmn.DrillRunning
+			status.Message = "Waiting for the drill VRG to restore and verify the application data"
+
+			return !done, nil
+		}
+	}
+
+	status.Phase = rmn.DrillSucceeded
+	status.CompletionTime = &metav1.Time{Time: time.Now()}
+	status.Message = fmt.Sprintf("Application data restored and verified readable in namespace %q on cluster %q",
+		drillNamespace, drillCluster)
+
+	d.log.Info("Drill completed", "cluster", drillCluster, "namespace", drillNamespace,
+		"duration", status.CompletionTime.Sub(status.StartTime.Time))
+	rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeNormal,
+		rmnutil.EventReasonDrillSuccess, status.Message)
+
+	return done, nil
+}
+
+// drillConditionFailed returns true if the drill VRG condition is False for
+// its current generation with a reason other than one of progress, such as
+// Error or Corrupted
+func drillConditionFailed(condition *metav1.Condition, generation int64) bool {
+	if condition == nil || condition.ObservedGeneration != generation ||
+		condition.Status != metav1.ConditionFalse {
+		return false
+	}
+
+	switch condition.Reason {
+	case VRGConditionReasonInitializing,
+		VRGConditionReasonProgressing,
+		VRGConditionReasonReplicating,
+		VRGConditionReasonUploading:
+		return false
+	}
+
+	return true
+}
+
+func (d *DRPCInstance) setDrillFailed(msg string) {
+	if d.instance.Status.Drill == nil {
+		d.instance.Status.Drill = &rmn.DrillStatus{}
+	}
+
+	d.instance.Status.Drill.Phase = rmn.DrillFailed
+	d.instance.Status.Drill.Message = msg
+
+	rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeWarning,
+		rmnutil.EventReasonDrillFailed, msg)
+}
+
+// cleanupDrill deletes the drill VRG, waits for it to be gone from the drill
+// cluster, so that its finalizer removes the PVs it restored, and then deletes
+// the drill namespace.
+func (d *DRPCInstance) cleanupDrill() (bool, error) {
+	const done = true
+
+	status := d.instance.Status.Drill
+	if status == nil {
+		return done, nil
+	}
+
+	if status.Cluster == "" {
+		// Drill never started, nothing to clean up
+		d.instance.Status.Drill = nil
+
+		return done, nil
+	}
+
+	status.Phase = rmn.DrillCleaningUp
+	status.Message = "Deleting drill resources"
+
+	if err := d.mwu.DeleteManifestWork(d.mwu.BuildManifestWorkName(rmnutil.MWTypeDrill), status.Cluster); err != nil {
+		return !done, err
+	}
+
+	_, err := d.reconciler.MCVGetter.GetVRGFromManagedCluster(d.instance.Name, status.Namespace, status.Cluster,
+		d.drillAnnotations())
+	if err == nil {
+		d.log.Info("Waiting for drill VRG to be deleted", "cluster", status.Cluster)
+
+		return !done, nil
+	}
+
+	if !errors.IsNotFound(err) {
+		return !done, fmt.Errorf("failed to get drill VRG from cluster %s (%w)", status.Cluster, err)
+	}
+
+	if err := d.mwu.DeleteManifestWork(
+		rmnutil.ManifestWorkName(d.instance.Name, status.Namespace, rmnutil.MWTypeNS), status.Cluster); err != nil {
+		return !done, err
+	}
+
+	if err := d.reconciler.MCVGetter.DeleteVRGManagedClusterView(d.instance.Name, status.Namespace,
+		status.Cluster, rmnutil.MWTypeVRG); err != nil {
+		return !done, fmt.Errorf("failed to delete drill VRG MCV %w", err)
+	}
+
+	rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeNormal,
+		rmnutil.EventReasonDrillCleanedUp,
+		fmt.Sprintf("Cleaned up failover drill in namespace %q on cluster %q", status.Namespace, status.Cluster))
+
+	d.instance.Status.Drill = nil
+
+	return done, nil
+}
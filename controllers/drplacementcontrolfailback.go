@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// RunFailback relocates a failed over application back to its preferred
+// cluster, the old primary. Before the relocate is started:
+//  1. the preferred cluster is unfenced, if the DRPC requests it, or else is
+//     waited for to be unfenced, as a fenced cluster can neither be demoted
+//     nor resync
+//  2. the failover's cleanup is ensured and, for VolSync, the replication
+//     destinations are set up on the preferred cluster
+//  3. the VRG on the preferred cluster is waited for to be secondary with its
+//     data resynced from the current primary
+//
+// Each step is reported as the DRPC progression, and the relocate's steps
+// follow.
+func (d *DRPCInstance) RunFailback() (bool, error) {
+	d.log.Info("Entering RunFailback", "state", d.getLastDRState(), "progression", d.getProgression())
+
+	const done = true
+
+	//nolint:exhaustive
+	switch d.getLastDRState() {
+	case rmn.Relocating, rmn.Relocated:
+		return d.RunRelocate()
+	case rmn.FailedOver:
+		d.instance.Status.ActionStartTime = &metav1.Time{Time: time.Now()}
+		d.instance.Status.ActionDuration = nil
+		d.setDRState(rmn.Initiating)
+		d.setProgression("")
+	case rmn.Initiating:
+	default:
+		msg := fmt.Sprintf("failback requires a failed over application, not a %s one", d.getLastDRState())
+		d.setDRPCCondition(&d.instance.Status.Conditions, rmn.ConditionAvailable, d.instance.Generation,
+			d.getConditionStatusForTypeAvailable(), string(d.instance.Status.Phase), msg)
+
+		return done, fmt.Errorf(msg)
+	}
+
+	preferredCluster := d.instance.Spec.PreferredCluster
+
+	curHomeCluster, err := d.validateAndSelectCurrentPrimary(preferredCluster)
+	if err != nil {
+		d.setDRPCCondition(&d.instance.Status.Conditions, rmn.ConditionAvailable, d.instance.Generation,
+			d.getConditionStatusForTypeAvailable(), string(d.instance.Status.Phase), err.Error())
+
+		return !done, err
+	}
+
+	// Without a primary elsewhere, there is nothing left to prepare; the relocate resumes
+	if curHomeCluster == "" || curHomeCluster == preferredCluster {
+		return d.RunRelocate()
+	}
+
+	if err := d.prepareFailback(curHomeCluster, preferredCluster); err != nil {
+		d.setDRPCCondition(&d.instance.Status.Conditions, rmn.ConditionAvailable, d.instance.Generation,
+			d.getConditionStatusForTypeAvailable(), string(d.instance.Status.Phase), err.Error())
+
+		return !done, err
+	}
+
+	return d.RunRelocate()
+}
+
+func (d *DRPCInstance) prepareFailback(homeCluster, preferredCluster string) error {
+	unfenced, err := d.failbackUnfence(preferredCluster)
+	if err != nil {
+		return err
+	}
+
+	if !unfenced {
+		d.setProgression(rmn.ProgressionUnfencingCluster)
+
+		return fmt.Errorf("waiting for cluster %s to be unfenced", preferredCluster)
+	}
+
+	if err := d.ensureCleanupAndVolSyncReplicationSetup(homeCluster); err != nil {
+		d.setProgression(rmn.ProgressionCleaningUp)
+
+		return err
+	}
+
+	if !d.checkResyncCompletionAsSecondary(preferredCluster) {
+		d.setProgression(rmn.ProgressionWaitingForResync)
+
+		return fmt.Errorf("waiting for VRG on cluster %s to be secondary and resynced", preferredCluster)
+	}
+
+	return nil
+}
+
+// failbackUnfence returns whether the cluster to fail back to is unfenced,
+// and requests that it be unfenced if Ramen fenced it and the DRPC allows it
+func (d *DRPCInstance) failbackUnfence(clusterName string) (bool, error) {
+	drcluster := drClusterFind(d.drClusters, clusterName)
+	if drcluster == nil {
+		return false, fmt.Errorf("drcluster %s not found", clusterName)
+	}
+
+	if drClusterUnfenced(drcluster) {
+		return true, nil
+	}
+
+	if !d.instance.Spec.UnfenceOnFailback || drcluster.Spec.ClusterFence != rmn.ClusterFenceStateFenced {
+		d.log.Info("Failback waiting for cluster to be unfenced", "cluster", clusterName,
+			"clusterFence", drcluster.Spec.ClusterFence)
+
+		return false, nil
+	}
+
+	drcluster.Spec.ClusterFence = rmn.ClusterFenceStateUnfenced
+	if err := d.reconciler.Update(d.ctx, drcluster); err != nil {
+		return false, fmt.Errorf("drcluster %s update: %w", clusterName, err)
+	}
+
+	msg := fmt.Sprintf("Unfencing cluster %s to fail back to it", clusterName)
+	d.log.Info(msg)
+	rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeNormal,
+		rmnutil.EventReasonFailbackUnfencing, msg)
+
+	return false, nil
+}
+
+// checkResyncCompletionAsSecondary returns whether the old primary's VRG on a
+// cluster has been demoted to secondary and, if it was kept for VolSync, has
+// its data protected, meaning its replication destinations have an image of
+// the current primary's data. A VRG deleted by a VolRep failover's cleanup
+// is resynced by the relocate itself.
+func (d *DRPCInstance) checkResyncCompletionAsSecondary(clusterName string) bool {
+	if !d.ensureVRGIsSecondaryOnCluster(clusterName) {
+		return false
+	}
+
+	if _, ok := d.vrgs[clusterName]; !ok {
+		return true
+	}
+
+	return d.ensureDataProtectedOnCluster(clusterName)
+}
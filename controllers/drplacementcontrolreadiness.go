@@ -0,0 +1,435 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// readinessS3ProbeInterval is the minimum time between two listings of the
+// target cluster's object store by the readiness checks of a DRPC
+const readinessS3ProbeInterval = 5 * time.Minute
+
+var (
+	readinessActionsAll      = []rmn.DRAction{rmn.ActionFailover, rmn.ActionRelocate, rmn.ActionFailback}
+	readinessActionsFailover = []rmn.DRAction{rmn.ActionFailover}
+	readinessActionsRelocate = []rmn.DRAction{rmn.ActionRelocate}
+	readinessActionsFailback = []rmn.DRAction{rmn.ActionFailback}
+)
+
+// readinessS3Probe is the result of a listing of an object store by the
+// readiness checks of a DRPC
+type readinessS3Probe struct {
+	s3ProfileName string
+	time          time.Time
+	check         rmn.ReadinessCheck
+}
+
+// readinessS3Probes holds the latest object store listing of each DRPC, so
+// that it is repeated at most once a readinessS3ProbeInterval
+var readinessS3Probes = struct {
+	sync.Mutex
+	probes map[types.NamespacedName]readinessS3Probe
+}{probes: map[types.NamespacedName]readinessS3Probe{}}
+
+func deleteReadinessS3Probe(drpc *rmn.DRPlacementControl) {
+	readinessS3Probes.Lock()
+	defer readinessS3Probes.Unlock()
+
+	delete(readinessS3Probes.probes, types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name})
+}
+
+// updateReadiness reports, in the status readiness and the ReadyForFailover
+// and ReadyForRelocate conditions, whether the application can be failed over
+// or relocated to the peer of its current cluster. It changes nothing else;
+// the checks predict what the failover and relocate code paths require. The
+// readiness, and its check time, change only when the checks' results do, so
+// that the status is not updated otherwise.
+func (d *DRPCInstance) updateReadiness() {
+	currentCluster := d.instance.Status.PreferredDecision.ClusterName
+	targetCluster := ""
+
+	for _, cluster := range rmnutil.DrpolicyClusterNames(d.drPolicy) {
+		if currentCluster != "" && cluster != currentCluster {
+			targetCluster = cluster
+
+			break
+		}
+	}
+
+	if targetCluster == "" {
+		d.instance.Status.Readiness = nil
+		meta.RemoveStatusCondition(&d.instance.Status.Conditions, rmn.ConditionReadyForFailover)
+		meta.RemoveStatusCondition(&d.instance.Status.Conditions, rmn.ConditionReadyForRelocate)
+
+		return
+	}
+
+	checks := d.readinessChecks(currentCluster, targetCluster)
+
+	readiness := d.instance.Status.Readiness
+	if readiness == nil || readiness.TargetCluster != targetCluster || !reflect.DeepEqual(readiness.Checks, checks) {
+		d.instance.Status.Readiness = &rmn.ReadinessStatus{
+			TargetCluster: targetCluster,
+			CheckTime:     metav1.Now(),
+			Checks:        checks,
+		}
+	}
+
+	d.setReadinessCondition(rmn.ConditionReadyForFailover, rmn.ActionFailover, targetCluster, checks)
+	d.setReadinessCondition(rmn.ConditionReadyForRelocate, rmn.ActionRelocate, targetCluster, checks)
+}
+
+func (d *DRPCInstance) readinessChecks(currentCluster, targetCluster string) []rmn.ReadinessCheck {
+	checks := []rmn.ReadinessCheck{}
+
+	targetVRG := d.vrgs[targetCluster]
+	if targetVRG == nil {
+		checks = append(checks, rmn.ReadinessCheck{
+			Name:    rmn.ReadinessCheckTargetVRG,
+			Actions: readinessActionsAll,
+			Status:  metav1.ConditionFalse,
+			Message: fmt.Sprintf("no VRG found on cluster %s", targetCluster),
+		})
+	} else {
+		checks = append(checks,
+			rmn.ReadinessCheck{
+				Name:    rmn.ReadinessCheckTargetVRG,
+				Actions: readinessActionsAll,
+				Status:  metav1.ConditionTrue,
+				Message: fmt.Sprintf("VRG on cluster %s is %s", targetCluster, targetVRG.Status.State),
+			},
+			vrgConditionReadinessCheck(targetVRG, VRGConditionTypeDataReady),
+			vrgConditionReadinessCheck(targetVRG, VRGConditionTypeDataProtected),
+		)
+
+		if check, ok := volSyncLatestImageReadinessCheck(targetVRG); ok {
+			checks = append(checks, check)
+		}
+	}
+
+	checks = append(checks, clusterDataProtectedReadinessCheck(d.vrgs[currentCluster], currentCluster))
+	checks = append(checks, d.s3ProfileReadinessCheck(targetCluster))
+	checks = append(checks, d.fencingReadinessChecks(currentCluster, targetCluster)...)
+
+	if check, ok := kubeObjectsCaptureAgeReadinessCheck(d.vrgs[currentCluster]); ok {
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// vrgConditionReadinessCheck passes if a VRG condition is true for the VRG's
+// generation, and is unknown if the condition is not reported for it
+func vrgConditionReadinessCheck(vrg *rmn.VolumeReplicationGroup, conditionType string) rmn.ReadinessCheck {
+	check := rmn.ReadinessCheck{Name: conditionType, Actions: readinessActionsAll}
+
+	condition := findCondition(vrg.Status.Conditions, conditionType)
+
+	switch {
+	case condition == nil:
+		check.Status = metav1.ConditionUnknown
+		check.Message = "condition not reported"
+	case condition.ObservedGeneration != vrg.Generation:
+		check.Status = metav1.ConditionUnknown
+		check.Message = fmt.Sprintf("condition observed generation %d is not the VRG's generation %d",
+			condition.ObservedGeneration, vrg.Generation)
+	default:
+		check.Status = condition.Status
+		check.Message = condition.Message
+	}
+
+	return check
+}
+
+// clusterDataProtectedReadinessCheck passes if the primary VRG, which uploads
+// the PV cluster data the target cluster restores, reports it protected. It is
+// unknown if the primary VRG is not found.
+func clusterDataProtectedReadinessCheck(vrg *rmn.VolumeReplicationGroup, currentCluster string,
+) rmn.ReadinessCheck {
+	if vrg == nil {
+		return rmn.ReadinessCheck{
+			Name:    rmn.ReadinessCheckClusterDataProtected,
+			Actions: readinessActionsAll,
+			Status:  metav1.ConditionUnknown,
+			Message: fmt.Sprintf("no VRG found on cluster %s", currentCluster),
+		}
+	}
+
+	return vrgConditionReadinessCheck(vrg, VRGConditionTypeClusterDataProtected)
+}
+
+// volSyncLatestImageReadinessCheck passes if every VolSync replication
+// destination of a secondary VRG has a latest image, which the VRG reports in
+// its DataProtected condition. It applies only to VRGs with destinations.
+func volSyncLatestImageReadinessCheck(vrg *rmn.VolumeReplicationGroup) (rmn.ReadinessCheck, bool) {
+	if len(vrg.Spec.VolSync.RDSpec) == 0 {
+		return rmn.ReadinessCheck{}, false
+	}
+
+	check := rmn.ReadinessCheck{
+		Name:    rmn.ReadinessCheckVolSyncLatestImage,
+		Actions: readinessActionsAll,
+		Status:  metav1.ConditionUnknown,
+		Message: "replication destinations' latest images not reported",
+	}
+
+	condition := findCondition(vrg.Status.Conditions, VRGConditionTypeDataProtected)
+	if condition == nil || condition.ObservedGeneration != vrg.Generation {
+		return check, true
+	}
+
+	check.Status = condition.Status
+	check.Message = fmt.Sprintf("%d replication destinations: %s", len(vrg.Spec.VolSync.RDSpec), condition.Message)
+
+	return check, true
+}
+
+// s3ProfileReadinessCheck passes if the object store of the target cluster's
+// S3 profile, from which the VRG restores the PV cluster data, can be listed.
+// The store is listed at most once a readinessS3ProbeInterval for the same
+// profile, the check repeating the previous listing's result in between.
+func (d *DRPCInstance) s3ProfileReadinessCheck(targetCluster string) rmn.ReadinessCheck {
+	check := rmn.ReadinessCheck{
+		Name:    rmn.ReadinessCheckS3ProfileReachable,
+		Actions: readinessActionsAll,
+		Status:  metav1.ConditionFalse,
+	}
+
+	s3ProfileName := ""
+
+	for i := range d.drClusters {
+		if d.drClusters[i].Name == targetCluster {
+			s3ProfileName = d.drClusters[i].Spec.S3ProfileName
+
+			break
+		}
+	}
+
+	if s3ProfileName == "" {
+		check.Message = fmt.Sprintf("no S3 profile for cluster %s", targetCluster)
+
+		return check
+	}
+
+	namespacedName := types.NamespacedName{Namespace: d.instance.Namespace, Name: d.instance.Name}
+
+	readinessS3Probes.Lock()
+	probe, ok := readinessS3Probes.probes[namespacedName]
+	readinessS3Probes.Unlock()
+
+	if ok && probe.s3ProfileName == s3ProfileName && time.Since(probe.time) < readinessS3ProbeInterval {
+		return probe.check
+	}
+
+	check = d.s3ProfileProbe(check, s3ProfileName)
+
+	readinessS3Probes.Lock()
+	readinessS3Probes.probes[namespacedName] = readinessS3Probe{
+		s3ProfileName: s3ProfileName,
+		time:          time.Now(),
+		check:         check,
+	}
+	readinessS3Probes.Unlock()
+
+	return check
+}
+
+func (d *DRPCInstance) s3ProfileProbe(check rmn.ReadinessCheck, s3ProfileName string) rmn.ReadinessCheck {
+	objectStorer, _, err := d.reconciler.ObjStoreGetter.ObjectStore(d.ctx, d.reconciler.APIReader,
+		s3ProfileName, "drpc readiness", d.log)
+	if err != nil {
+		check.Message = fmt.Sprintf("S3 profile %s: %v", s3ProfileName, err)
+
+		return check
+	}
+
+	if _, err := objectStorer.ListKeys(d.instance.Namespace + "/" + d.instance.Name + "/"); err != nil {
+		check.Message = fmt.Sprintf("S3 profile %s: %v", s3ProfileName, err)
+
+		return check
+	}
+
+	check.Status = metav1.ConditionTrue
+	check.Message = fmt.Sprintf("S3 profile %s lists the objects of the VRG", s3ProfileName)
+
+	return check
+}
+
+// fencingReadinessChecks return, for a failover, whether the current cluster
+// is fenced if in the target cluster's region, as a metro failover requires,
+// and, for a relocate or a failback, whether the current cluster is unfenced,
+// since it runs the final sync. No action may target a fenced cluster, except
+// a failback that unfences it.
+func (d *DRPCInstance) fencingReadinessChecks(currentCluster, targetCluster string) []rmn.ReadinessCheck {
+	failover := rmn.ReadinessCheck{Name: rmn.ReadinessCheckFencing, Actions: readinessActionsFailover}
+	relocate := rmn.ReadinessCheck{Name: rmn.ReadinessCheckFencing, Actions: readinessActionsRelocate}
+	failback := rmn.ReadinessCheck{Name: rmn.ReadinessCheckFencing, Actions: readinessActionsFailback}
+
+	targetFenced, err := d.checkClusterFenced(targetCluster, d.drClusters)
+	if err != nil {
+		failover.Status, failover.Message = metav1.ConditionUnknown, err.Error()
+		relocate.Status, relocate.Message = metav1.ConditionUnknown, err.Error()
+		failback.Status, failback.Message = metav1.ConditionUnknown, err.Error()
+
+		return []rmn.ReadinessCheck{failover, relocate, failback}
+	}
+
+	currentFenced, err := d.checkClusterFenced(currentCluster, d.drClusters)
+	if err != nil {
+		failover.Status, failover.Message = metav1.ConditionUnknown, err.Error()
+		relocate.Status, relocate.Message = metav1.ConditionUnknown, err.Error()
+		failback.Status, failback.Message = metav1.ConditionUnknown, err.Error()
+
+		return []rmn.ReadinessCheck{failover, relocate, failback}
+	}
+
+	metro := isMetroAction(d.drPolicy, d.drClusters, currentCluster, targetCluster)
+
+	switch {
+	case targetFenced:
+		failover.Status, failover.Message = metav1.ConditionFalse, fmt.Sprintf("cluster %s is fenced", targetCluster)
+	case metro && !currentFenced:
+		failover.Status, failover.Message = metav1.ConditionFalse,
+			fmt.Sprintf("cluster %s, in the region of cluster %s, is not fenced", currentCluster, targetCluster)
+	default:
+		failover.Status, failover.Message = metav1.ConditionTrue, "fencing allows failover"
+	}
+
+	switch {
+	case targetFenced:
+		relocate.Status, relocate.Message = metav1.ConditionFalse, fmt.Sprintf("cluster %s is fenced", targetCluster)
+	case currentFenced:
+		relocate.Status, relocate.Message = metav1.ConditionFalse,
+			fmt.Sprintf("cluster %s is fenced", currentCluster)
+	default:
+		relocate.Status, relocate.Message = metav1.ConditionTrue, "fencing allows relocate"
+	}
+
+	switch {
+	case targetFenced && !d.instance.Spec.UnfenceOnFailback:
+		failback.Status, failback.Message = metav1.ConditionFalse,
+			fmt.Sprintf("cluster %s is fenced and unfenceOnFailback is not set", targetCluster)
+	case currentFenced:
+		failback.Status, failback.Message = metav1.ConditionFalse,
+			fmt.Sprintf("cluster %s is fenced", currentCluster)
+	default:
+		failback.Status, failback.Message = metav1.ConditionTrue, "fencing allows failback"
+	}
+
+	return []rmn.ReadinessCheck{failover, relocate, failback}
+}
+
+// kubeObjectsCaptureAgeReadinessCheck passes if the kube objects capture the
+// primary VRG would be recovered from is younger than twice the capture
+// interval. It applies only to primary VRGs that protect kube objects.
+func kubeObjectsCaptureAgeReadinessCheck(vrg *rmn.VolumeReplicationGroup) (rmn.ReadinessCheck, bool) {
+	if vrg == nil || vrg.Spec.KubeObjectProtection == nil {
+		return rmn.ReadinessCheck{}, false
+	}
+
+	check := rmn.ReadinessCheck{
+		Name:    rmn.ReadinessCheckKubeObjectsCaptureAge,
+		Actions: readinessActionsAll,
+		Status:  metav1.ConditionFalse,
+		Message: "no capture to recover from",
+	}
+
+	capture := vrg.Status.KubeObjectProtection.CaptureToRecoverFrom
+	if capture == nil {
+		return check, true
+	}
+
+	// the message does not state the age, which would change the check at each run
+	threshold := 2 * kubeObjectsCaptureInterval(vrg.Spec.KubeObjectProtection)
+	check.Message = fmt.Sprintf("capture %d started at %s; the age threshold is %v", capture.Number,
+		capture.StartTime.UTC().Format(time.RFC3339), threshold)
+
+	if time.Since(capture.StartTime.Time) <= threshold {
+		check.Status = metav1.ConditionTrue
+	}
+
+	return check, true
+}
+
+// setReadinessCondition sets a readiness condition to false if any check of
+// the action did not pass: with the first failed check of the action as its
+// reason, or with ReasonReadinessUnknown if none failed but some could not be
+// evaluated. It sets it to true otherwise.
+func (d *DRPCInstance) setReadinessCondition(conditionType string, action rmn.DRAction, targetCluster string,
+	checks []rmn.ReadinessCheck,
+) {
+	status := metav1.ConditionTrue
+	reason := rmn.ReasonReady
+	messages := []string{}
+
+	for _, check := range checks {
+		if check.Status == metav1.ConditionTrue || !readinessActionsHas(check.Actions, action) {
+			continue
+		}
+
+		switch {
+		case check.Status == metav1.ConditionFalse && (status == metav1.ConditionTrue ||
+			reason == rmn.ReasonReadinessUnknown):
+			status = metav1.ConditionFalse
+			reason = check.Name
+		case status == metav1.ConditionTrue:
+			status = metav1.ConditionFalse
+			reason = rmn.ReasonReadinessUnknown
+		}
+
+		messages = append(messages, fmt.Sprintf("%s %s: %s", check.Name, readinessCheckResult(check.Status),
+			check.Message))
+	}
+
+	message := fmt.Sprintf("Ready for %s to cluster %s", strings.ToLower(string(action)), targetCluster)
+	if len(messages) != 0 {
+		message = strings.Join(messages, "; ")
+	}
+
+	SetDRPCStatusCondition(&d.instance.Status.Conditions, conditionType, d.instance.Generation, status, reason,
+		message)
+}
+
+func readinessCheckResult(status metav1.ConditionStatus) string {
+	if status == metav1.ConditionFalse {
+		return "failed"
+	}
+
+	return "unknown"
+}
+
+func readinessActionsHas(actions []rmn.DRAction, action rmn.DRAction) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
@@ -61,9 +61,17 @@ func (d *DRPCInstance) ensureVolSyncReplicationCommon(srcCluster string) error {
 	// Since we will use VolSync - create/ensure & propagate a shared ssh rsync secret to both the src and dst clusters
 	sshSecretNameHub := fmt.Sprintf("%s-vs-secret-hub", d.instance.GetName())
 
-	// Ensure/Create the secret on the hub
+	clustersToPropagateSecret := []string{}
+	clustersSecretRotatedAt := []string{}
+
+	for clusterName, vrg := range d.vrgs {
+		clustersToPropagateSecret = append(clustersToPropagateSecret, clusterName)
+		clustersSecretRotatedAt = append(clustersSecretRotatedAt, vrg.Status.VolSyncSecretRotatedAt)
+	}
+
+	// Ensure/Create the secret on the hub, rotating its keys only once both clusters report having the last ones
 	sshSecretHub, err := volsync.ReconcileVolSyncReplicationSecret(d.ctx, d.reconciler.Client, d.instance,
-		sshSecretNameHub, d.instance.GetNamespace(), d.log)
+		sshSecretNameHub, d.instance.GetNamespace(), d.volSyncSecretRotationPeriod, clustersSecretRotatedAt, d.log)
 	if err != nil {
 		d.log.Error(err, "Unable to create ssh secret on hub for VolSync")
 
@@ -74,11 +82,6 @@ func (d *DRPCInstance) ensureVolSyncReplicationCommon(srcCluster string) error {
 	// Note that VRG spec will not contain the ssh secret name, we're going to name based on the VRG name itself
 	sshSecretNameCluster := volsync.GetVolSyncSSHSecretNameFromVRGName(d.instance.GetName()) // VRG name == DRPC name
 
-	clustersToPropagateSecret := []string{}
-	for clusterName := range d.vrgs {
-		clustersToPropagateSecret = append(clustersToPropagateSecret, clusterName)
-	}
-
 	err = volsync.PropagateSecretToClusters(d.ctx, d.reconciler.Client, sshSecretHub,
 		d.instance, clustersToPropagateSecret, sshSecretNameCluster, d.instance.GetNamespace(), d.log)
 	if err != nil {
@@ -87,6 +90,39 @@ func (d *DRPCInstance) ensureVolSyncReplicationCommon(srcCluster string) error {
 		return fmt.Errorf("%w", err)
 	}
 
+	return d.ensureVolSyncSecretRotatedAt(srcCluster, sshSecretHub.GetAnnotations()[volsync.SecretRotatedAtAnnotation])
+}
+
+// ensureVolSyncSecretRotatedAt records in the source cluster's VRG, once both VRGs report having the keys of the
+// secret on the hub, that both clusters have them, so that its replication sources, paused until then, resume
+func (d *DRPCInstance) ensureVolSyncSecretRotatedAt(srcCluster, rotatedAt string) error {
+	for clusterName, vrg := range d.vrgs {
+		if vrg.Status.VolSyncSecretRotatedAt != rotatedAt {
+			d.log.Info("Waiting for the VolSync secret keys to be propagated", "cluster", clusterName,
+				"clusterRotatedAt", vrg.Status.VolSyncSecretRotatedAt, "rotatedAt", rotatedAt)
+
+			return nil
+		}
+	}
+
+	vrg, err := d.getVRGFromManifestWork(srcCluster)
+	if err != nil {
+		return fmt.Errorf("failed to get VRG ManifestWork of cluster %s (%w)", srcCluster, err)
+	}
+
+	if vrg.Spec.VolSync.SecretRotatedAt == rotatedAt {
+		return nil
+	}
+
+	vrg.Spec.VolSync.SecretRotatedAt = rotatedAt
+
+	if err := d.updateManifestWork(srcCluster, vrg); err != nil {
+		return fmt.Errorf("failed to update VolSync secret keys of VRG on cluster %s (%w)", srcCluster, err)
+	}
+
+	d.log.Info("Updated VRG with the VolSync secret keys both clusters have", "cluster", srcCluster,
+		"rotatedAt", rotatedAt)
+
 	return nil
 }
 
@@ -151,7 +151,7 @@ func drClusterListMustHaveSecrets(
 	// that should still be present. This is done as multiple profiles MAY point to the same secret
 	for _, s3Profile := range ramenConfig.S3StoreProfiles {
 		if mustHaveS3Profiles.Has(s3Profile.S3ProfileName) {
-			mustHaveS3Secrets = mustHaveS3Secrets.Insert(s3Profile.S3SecretRef.Name)
+			mustHaveS3Secrets = mustHaveS3Secrets.Union(s3ProfileSecretNames(s3Profile))
 		}
 	}
 
@@ -204,7 +204,7 @@ func drPolicySecretNames(drpolicy *rmn.DRPolicy,
 
 		for _, s3Profile := range rmnCfg.S3StoreProfiles {
 			if s3ProfileName == s3Profile.S3ProfileName {
-				secretNames.Insert(s3Profile.S3SecretRef.Name)
+				secretNames = secretNames.Union(s3ProfileSecretNames(s3Profile))
 
 				mcProfileFound = true
 
@@ -220,3 +220,20 @@ func drPolicySecretNames(drpolicy *rmn.DRPolicy,
 
 	return secretNames, nil
 }
+
+// s3ProfileSecretNames returns the names of the secrets of the given s3
+// profile: its s3 secret, which profiles of object stores other than s3 may not
+// have, and its encryption key secret, if any
+func s3ProfileSecretNames(s3Profile rmn.S3StoreProfile) sets.String {
+	secretNames := sets.String{}
+
+	if s3Profile.S3SecretRef.Name != "" {
+		secretNames.Insert(s3Profile.S3SecretRef.Name)
+	}
+
+	if s3Profile.Encryption.KeySecretRef.Name != "" {
+		secretNames.Insert(s3Profile.Encryption.KeySecretRef.Name)
+	}
+
+	return secretNames
+}
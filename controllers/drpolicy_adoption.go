@@ -0,0 +1,399 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+// DRPolicyAdoptAnnotation set to "true" on a DRPolicy requests that the hub
+// adopt the applications the policy protects. It is meant for a freshly
+// installed hub that replaces a lost one, and is to be removed once the
+// policy's ProtectedApplicationsAdopted condition is true.
+const DRPolicyAdoptAnnotation = "drpolicy.ramendr.openshift.io/adopt-protected-applications"
+
+// drPolicyAdoptRetryDelay is the delay before adoption is retried for VRGs
+// whose home cluster is not known yet, or that are secondary on every cluster
+const drPolicyAdoptRetryDelay = time.Minute
+
+// adoptionResult is the result of the adoption of a VRG's application
+type adoptionResult int
+
+const (
+	// adoptionDone means the VRG's DRPC exists, is created, or will not be
+	// created because the VRG is on none of the DRPolicy's clusters
+	adoptionDone adoptionResult = iota
+
+	// adoptionPending means the VRG's home cluster is not known yet
+	adoptionPending
+
+	// adoptionBlocked means the VRG is secondary on every cluster it is on,
+	// such as after a relocation interrupted by the loss of the hub, so that
+	// its DRPC cannot tell where the application is to run
+	adoptionBlocked
+)
+
+func drPolicyAdoptRequested(drpolicy *ramen.DRPolicy) bool {
+	return drpolicy.GetAnnotations()[DRPolicyAdoptAnnotation] == "true"
+}
+
+// protectedApplicationsAdopt reconstructs a DRPC, and its user PlacementRule
+// if absent, for each VRG of the DRPolicy, in the object stores of its
+// clusters, that has no DRPC yet. The DRPC prefers the cluster where the VRG
+// is primary so that it is adopted as deployed there and may be failed over.
+func (r *DRPolicyReconciler) protectedApplicationsAdopt(u *drpolicyUpdater, drclusters []ramen.DRCluster,
+) (ctrl.Result, error) {
+	vrgs, err := r.drPolicyProtectedVRGsDownload(u, drclusters)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("protected vrgs download: %w",
+			u.adoptedSetFalse("ObjectStoresReadFailed", err))
+	}
+
+	pending, blocked := 0, 0
+
+	for i := range vrgs {
+		result, err := r.drpcAdopt(u, &vrgs[i])
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("drpc adopt: %w", u.adoptedSetFalse("AdoptionFailed", err))
+		}
+
+		switch result {
+		case adoptionPending:
+			pending++
+		case adoptionBlocked:
+			blocked++
+		case adoptionDone:
+		}
+	}
+
+	if blocked > 0 {
+		return ctrl.Result{RequeueAfter: drPolicyAdoptRetryDelay}, u.statusConditionSet(
+			ramen.DRPolicyProtectedApplicationsAdopted, metav1.ConditionFalse, "AdoptionBlocked",
+			fmt.Sprintf("%d of %d protected applications are secondary on every cluster and need their DRPCs "+
+				"created by an admin; %d are waiting for their home cluster", blocked, len(vrgs), pending))
+	}
+
+	if pending > 0 {
+		return ctrl.Result{RequeueAfter: drPolicyAdoptRetryDelay}, u.statusConditionSet(
+			ramen.DRPolicyProtectedApplicationsAdopted, metav1.ConditionFalse, "Adopting",
+			fmt.Sprintf("%d of %d protected applications waiting for their home cluster", pending, len(vrgs)))
+	}
+
+	return ctrl.Result{}, u.statusConditionSet(ramen.DRPolicyProtectedApplicationsAdopted, metav1.ConditionTrue,
+		"Succeeded", fmt.Sprintf("%d protected applications found", len(vrgs)))
+}
+
+func (r *DRPolicyReconciler) drPolicyProtectedVRGsDownload(u *drpolicyUpdater, drclusters []ramen.DRCluster,
+) ([]ramen.VolumeReplicationGroup, error) {
+	s3ProfileNames := util.DRPolicyS3Profiles(u.object, drclusters)
+	objectStorers := make([]ObjectStorer, 0, s3ProfileNames.Len())
+
+	for _, s3ProfileName := range s3ProfileNames.List() {
+		objectStorer, _, err := r.ObjectStoreGetter.ObjectStore(u.ctx, r.APIReader, s3ProfileName,
+			"drpolicy adoption", u.log)
+		if err != nil {
+			// the store of a lost cluster may be lost too; its VRGs are in the others
+			u.log.Info("Object store unavailable for adoption", "profile", s3ProfileName, "error", err.Error())
+
+			continue
+		}
+
+		objectStorers = append(objectStorers, objectStorer)
+	}
+
+	if len(objectStorers) == 0 {
+		return nil, fmt.Errorf("no object store available of profiles %v", s3ProfileNames.List())
+	}
+
+	return DRPolicyProtectedVRGs(objectStorers, u.object, s3ProfileNames)
+}
+
+// DRPolicyProtectedVRGs returns the VRGs, in the given object stores, of the
+// applications protected by the given DRPolicy, each VRG once. A VRG is
+// protected by the DRPolicy named in its DRPolicy annotation or, lacking one,
+// by a DRPolicy whose clusters' S3 profiles are the VRG's. Drill VRGs are not.
+func DRPolicyProtectedVRGs(objectStorers []ObjectStorer, drpolicy *ramen.DRPolicy, s3ProfileNames sets.String,
+) ([]ramen.VolumeReplicationGroup, error) {
+	vrgs := make([]ramen.VolumeReplicationGroup, 0)
+	namespaceAndVrgNamesFound := sets.NewString()
+
+	for _, objectStorer := range objectStorers {
+		vrgPrefixes, err := vrgPrefixesList(objectStorer)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, vrgPrefix := range vrgPrefixes {
+			namespaceAndVrgName := strings.TrimSuffix(vrgPrefix, "/")
+			if namespaceAndVrgNamesFound.Has(namespaceAndVrgName) {
+				continue
+			}
+
+			storedVrgs, err := DownloadVRGs(objectStorer, vrgPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("vrgs %s download: %w", namespaceAndVrgName, err)
+			}
+
+			for i := range storedVrgs {
+				vrg := &storedVrgs[i]
+				if !vrgProtectedByDRPolicy(vrg, drpolicy, s3ProfileNames) {
+					continue
+				}
+
+				namespaceAndVrgNamesFound.Insert(namespaceAndVrgName)
+
+				vrgs = append(vrgs, *vrg)
+			}
+		}
+	}
+
+	return vrgs, nil
+}
+
+// vrgPrefixesList returns the key prefix, namespace/vrgName/, of each VRG in
+// the given object store, listing the prefixes of the keys rather than the
+// keys of the VRGs' objects
+func vrgPrefixesList(objectStorer ObjectStorer) ([]string, error) {
+	namespacePrefixes, err := objectStorer.ListKeyPrefixes("", "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("list namespace prefixes: %w", err)
+	}
+
+	vrgPrefixes := make([]string, 0, len(namespacePrefixes))
+
+	for _, namespacePrefix := range namespacePrefixes {
+		if namespacePrefix == hubBackupKeyPrefix {
+			continue
+		}
+
+		prefixes, err := objectStorer.ListKeyPrefixes(namespacePrefix, "", 0)
+		if err != nil {
+			return nil, fmt.Errorf("list VRG prefixes of %s: %w", namespacePrefix, err)
+		}
+
+		vrgPrefixes = append(vrgPrefixes, prefixes...)
+	}
+
+	return vrgPrefixes, nil
+}
+
+func vrgProtectedByDRPolicy(vrg *ramen.VolumeReplicationGroup, drpolicy *ramen.DRPolicy,
+	s3ProfileNames sets.String,
+) bool {
+	if vrg.Spec.Drill != nil {
+		return false
+	}
+
+	if drpolicyName, ok := vrg.GetAnnotations()[DRPolicyNameAnnotation]; ok {
+		return drpolicyName == drpolicy.Name
+	}
+
+	return sets.NewString(vrg.Spec.S3Profiles...).Equal(s3ProfileNames)
+}
+
+// drpcAdopt creates the VRG's DRPC, unless it exists, preferring the VRG's home
+// cluster, and returns whether it is adopted, or why not
+func (r *DRPolicyReconciler) drpcAdopt(u *drpolicyUpdater, vrg *ramen.VolumeReplicationGroup,
+) (adoptionResult, error) {
+	log := u.log.WithValues("VRG", types.NamespacedName{Namespace: vrg.Namespace, Name: vrg.Name})
+
+	err := r.APIReader.Get(u.ctx, types.NamespacedName{Namespace: vrg.Namespace, Name: vrg.Name},
+		&ramen.DRPlacementControl{})
+	if err == nil {
+		return adoptionDone, nil
+	}
+
+	if !k8serrors.IsNotFound(err) {
+		return adoptionPending, fmt.Errorf("drpc %s/%s get: %w", vrg.Namespace, vrg.Name, err)
+	}
+
+	clusters := r.vrgClustersView(u.object, vrg, log)
+
+	homeClusterName, result := adoptionHomeCluster(clusters)
+
+	switch {
+	case result == adoptionPending:
+		log.Info("Adoption delayed; home cluster not known yet", "clusters", clusters)
+
+		return result, nil
+	case result == adoptionBlocked:
+		log.Info("Adoption blocked; VRG secondary on every cluster", "clusters", clusters)
+
+		return result, nil
+	case homeClusterName == "":
+		log.Info("Adoption skipped; VRG not on any cluster")
+
+		return result, nil
+	}
+
+	if err := r.adoptionNamespaceCreate(u, vrg.Namespace); err != nil {
+		return adoptionPending, err
+	}
+
+	placementRuleName, err := r.adoptionPlacementRuleGetOrCreate(u, vrg, log)
+	if err != nil {
+		return adoptionPending, err
+	}
+
+	drpc := &ramen.DRPlacementControl{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        vrg.Name,
+			Namespace:   vrg.Namespace,
+			Annotations: map[string]string{DRPCAdoptedAnnotation: "true"},
+		},
+		Spec: ramen.DRPlacementControlSpec{
+			PlacementRef:        corev1.ObjectReference{Kind: "PlacementRule", Name: placementRuleName},
+			DRPolicyRef:         corev1.ObjectReference{Name: u.object.Name},
+			PreferredCluster:    homeClusterName,
+			PVCSelector:         vrg.Spec.PVCSelector,
+			ProtectedNamespaces: vrg.Spec.ProtectedNamespaces,
+		},
+	}
+
+	if err := r.Client.Create(u.ctx, drpc); err != nil {
+		return adoptionPending, fmt.Errorf("drpc %s/%s create: %w", drpc.Namespace, drpc.Name, err)
+	}
+
+	log.Info("DRPC adopted", "home cluster", homeClusterName, "PlacementRule", placementRuleName)
+
+	return adoptionDone, nil
+}
+
+// vrgClusters are the clusters of a DRPolicy where a VRG is primary, those
+// where it is secondary, and those where it could not be viewed
+type vrgClusters struct {
+	Primary   []string
+	Secondary []string
+	Unviewed  []string
+}
+
+// vrgClustersView returns the DRPolicy's clusters where the VRG is primary or
+// secondary and those where it could not be viewed
+func (r *DRPolicyReconciler) vrgClustersView(drpolicy *ramen.DRPolicy, vrg *ramen.VolumeReplicationGroup,
+	log logr.Logger,
+) vrgClusters {
+	// the DRPC reuses the views once adopted
+	annotations := map[string]string{
+		DRPCNameAnnotation:      vrg.Name,
+		DRPCNamespaceAnnotation: vrg.Namespace,
+	}
+	clusters := vrgClusters{Primary: []string{}, Secondary: []string{}, Unviewed: []string{}}
+
+	for _, clusterName := range util.DrpolicyClusterNames(drpolicy) {
+		clusterVrg, err := r.MCVGetter.GetVRGFromManagedCluster(vrg.Name, vrg.Namespace, clusterName, annotations)
+
+		switch {
+		case k8serrors.IsNotFound(err):
+		case err != nil:
+			log.Info("VRG view unavailable", "cluster", clusterName, "error", err.Error())
+
+			clusters.Unviewed = append(clusters.Unviewed, clusterName)
+		case clusterVrg.Spec.ReplicationState == ramen.Primary:
+			clusters.Primary = append(clusters.Primary, clusterName)
+		case clusterVrg.Spec.ReplicationState == ramen.Secondary:
+			clusters.Secondary = append(clusters.Secondary, clusterName)
+		}
+	}
+
+	return clusters
+}
+
+// adoptionHomeCluster returns the cluster where a VRG is primary or, if it is
+// primary on none that could be viewed, the only cluster that could not be,
+// presumably a lost primary. The cluster is empty if the VRG is on none. The
+// adoption is pending if the cluster cannot be told yet, and blocked if the
+// VRG is secondary on every cluster.
+func adoptionHomeCluster(clusters vrgClusters) (string, adoptionResult) {
+	switch {
+	case len(clusters.Primary) == 1:
+		return clusters.Primary[0], adoptionDone
+	case len(clusters.Primary) > 1:
+		return "", adoptionPending
+	case len(clusters.Unviewed) == 1:
+		return clusters.Unviewed[0], adoptionDone
+	case len(clusters.Unviewed) > 1:
+		return "", adoptionPending
+	case len(clusters.Secondary) > 0:
+		return "", adoptionBlocked
+	default:
+		return "", adoptionDone
+	}
+}
+
+func (r *DRPolicyReconciler) adoptionNamespaceCreate(u *drpolicyUpdater, namespaceName string) error {
+	err := r.Client.Create(u.ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName}})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("namespace %s create: %w", namespaceName, err)
+	}
+
+	return nil
+}
+
+// adoptionPlacementRuleGetOrCreate returns the name of the VRG's user
+// PlacementRule, creating it, for the ramen scheduler, if it does not exist
+func (r *DRPolicyReconciler) adoptionPlacementRuleGetOrCreate(u *drpolicyUpdater,
+	vrg *ramen.VolumeReplicationGroup, log logr.Logger,
+) (string, error) {
+	name := vrg.GetAnnotations()[PlacementRuleNameAnnotation]
+	if name == "" {
+		name = vrg.Name
+	}
+
+	err := r.APIReader.Get(u.ctx, types.NamespacedName{Namespace: vrg.Namespace, Name: name}, &plrv1.PlacementRule{})
+	if err == nil {
+		return name, nil
+	}
+
+	if !k8serrors.IsNotFound(err) {
+		return "", fmt.Errorf("placementrule %s/%s get: %w", vrg.Namespace, name, err)
+	}
+
+	clusterReplicas := int32(1)
+	plRule := &plrv1.PlacementRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   vrg.Namespace,
+			Annotations: map[string]string{DRPCAdoptedAnnotation: "true"},
+		},
+		Spec: plrv1.PlacementRuleSpec{
+			GenericPlacementFields: plrv1.GenericPlacementFields{ClusterReplicas: &clusterReplicas},
+			SchedulerName:          RamenScheduler,
+		},
+	}
+
+	if err := r.Client.Create(u.ctx, plRule); err != nil {
+		return "", fmt.Errorf("placementrule %s/%s create: %w", plRule.Namespace, plRule.Name, err)
+	}
+
+	log.Info("PlacementRule created for adoption", "name", name)
+
+	return name, nil
+}
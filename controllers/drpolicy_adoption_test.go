@@ -0,0 +1,316 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// adoptionVRGStates holds, for each VRG name, its replication state on each
+// cluster where adoption views it. A VRG absent from a cluster is not found
+// there, and one with an empty state cannot be viewed there.
+var adoptionVRGStates = map[string]map[string]ramen.ReplicationState{}
+
+func adoptionVRGView(name, namespaceName, clusterName string) (*ramen.VolumeReplicationGroup, error) {
+	state, ok := adoptionVRGStates[name][clusterName]
+
+	switch {
+	case !ok:
+		return nil, k8serrors.NewNotFound(schema.GroupResource{}, name)
+	case state == "":
+		return nil, fmt.Errorf("cluster %s unavailable", clusterName)
+	}
+
+	return &ramen.VolumeReplicationGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespaceName, Name: name},
+		Spec:       ramen.VolumeReplicationGroupSpec{ReplicationState: state},
+	}, nil
+}
+
+var _ = Describe("DRPolicy adoption", func() {
+	var (
+		rootDir     string
+		objectStore controllers.ObjectStorer
+	)
+
+	drpolicy := &ramen.DRPolicy{ObjectMeta: metav1.ObjectMeta{Name: "adoption-drpolicy"}}
+	s3ProfileNames := sets.NewString("adoption-s3profile-east", "adoption-s3profile-west")
+
+	vrg := func(name string, annotations map[string]string, profileNames ...string) ramen.VolumeReplicationGroup {
+		return ramen.VolumeReplicationGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "adoption-app", Name: name, Annotations: annotations},
+			Spec:       ramen.VolumeReplicationGroupSpec{S3Profiles: profileNames},
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		rootDir, err = os.MkdirTemp("", "ramen-adoption-")
+		Expect(err).NotTo(HaveOccurred())
+
+		objectStore = s3ProfileObjectStoreGet(ramen.S3StoreProfile{
+			S3ProfileName:        "fs-adoption-s3profile",
+			Type:                 ramen.FileSystemObjectStoreType,
+			S3Bucket:             bucketNameSucc,
+			S3CompatibleEndpoint: "file://" + rootDir,
+		})
+	})
+
+	AfterEach(func() {
+		s3ProfileObjectStoresRemove()
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	It("finds the VRGs the DRPolicy protects", func() {
+		drill := vrg("drill", map[string]string{controllers.DRPolicyNameAnnotation: drpolicy.Name},
+			s3ProfileNames.List()...)
+		drill.Spec.Drill = &ramen.VRGDrillSpec{SourceNamespace: "adoption-app"}
+
+		for _, storedVrg := range []ramen.VolumeReplicationGroup{
+			vrg("annotated", map[string]string{controllers.DRPolicyNameAnnotation: drpolicy.Name}),
+			vrg("annotated-other", map[string]string{controllers.DRPolicyNameAnnotation: "other"},
+				s3ProfileNames.List()...),
+			vrg("unannotated", nil, s3ProfileNames.List()...),
+			vrg("unannotated-other", nil, "adoption-s3profile-east"),
+			drill,
+		} {
+			Expect(controllers.VrgObjectProtect(objectStore, storedVrg)).To(Succeed())
+		}
+
+		vrgs, err := controllers.DRPolicyProtectedVRGs(
+			[]controllers.ObjectStorer{objectStore, objectStore}, drpolicy, s3ProfileNames)
+		Expect(err).NotTo(HaveOccurred())
+
+		names := make([]string, len(vrgs))
+		for i := range vrgs {
+			names[i] = vrgs[i].Name
+		}
+
+		Expect(names).To(ConsistOf("annotated", "unannotated"))
+	})
+})
+
+var _ = Describe("DRPolicy adoption reconcile", func() {
+	const namespaceName = "adoption-reconcile-app"
+
+	drclusters := []*ramen.DRCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ad-east"}, Spec: ramen.DRClusterSpec{Region: "ad-east"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ad-west"}, Spec: ramen.DRClusterSpec{Region: "ad-west"}},
+	}
+	drpolicy := &ramen.DRPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ad-drpolicy",
+			Annotations: map[string]string{controllers.DRPolicyAdoptAnnotation: "true"},
+		},
+		Spec: ramen.DRPolicySpec{DRClusters: []string{"ad-east", "ad-west"}, SchedulingInterval: "1m"},
+	}
+	// the replication state of each VRG on each cluster, as in adoptionVRGStates
+	vrgStates := map[string]map[string]ramen.ReplicationState{
+		"primary":      {"ad-east": ramen.Secondary, "ad-west": ramen.Primary},
+		"lost-primary": {"ad-east": ramen.Secondary, "ad-west": ""},
+		"secondary":    {"ad-east": ramen.Secondary, "ad-west": ramen.Secondary},
+		"primaries":    {"ad-east": ramen.Primary, "ad-west": ramen.Primary},
+		"absent":       {},
+	}
+
+	var objectStore controllers.ObjectStorer
+
+	drpcKey := func(name string) types.NamespacedName {
+		return types.NamespacedName{Namespace: namespaceName, Name: name}
+	}
+	drpcGet := func(name string) (*ramen.DRPlacementControl, error) {
+		drpc := &ramen.DRPlacementControl{}
+
+		return drpc, apiReader.Get(context.TODO(), drpcKey(name), drpc)
+	}
+	drpcAdoptedExpect := func(name, preferredClusterName string) {
+		Eventually(func(g Gomega) {
+			drpc, err := drpcGet(name)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(drpc.Spec.PreferredCluster).To(Equal(preferredClusterName))
+			g.Expect(drpc.Spec.DRPolicyRef.Name).To(Equal(drpolicy.Name))
+			g.Expect(drpc.GetAnnotations()).To(HaveKeyWithValue(controllers.DRPCAdoptedAnnotation, "true"))
+		}, timeout, interval).Should(Succeed())
+	}
+	drpcAbsentExpect := func(name string) {
+		_, err := drpcGet(name)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue(), "drpc %s: %v", name, err)
+	}
+	adoptedConditionExpect := func(status metav1.ConditionStatus, reason, messageSubstring string) {
+		Eventually(func(g Gomega) {
+			latest := &ramen.DRPolicy{}
+			g.Expect(apiReader.Get(context.TODO(), types.NamespacedName{Name: drpolicy.Name}, latest)).To(Succeed())
+
+			condition := meta.FindStatusCondition(latest.Status.Conditions,
+				ramen.DRPolicyProtectedApplicationsAdopted)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(status))
+			g.Expect(condition.Reason).To(Equal(reason))
+			g.Expect(condition.Message).To(ContainSubstring(messageSubstring))
+		}, timeout, interval).Should(Succeed())
+	}
+	// drpolicyReconcile has the DRPolicy reconciled sooner than adoption is
+	// retried, by updating its labels
+	drpolicyReconcile := func(label string) {
+		Eventually(func() error {
+			latest := &ramen.DRPolicy{}
+			if err := apiReader.Get(context.TODO(), types.NamespacedName{Name: drpolicy.Name}, latest); err != nil {
+				return err
+			}
+
+			latest.SetLabels(map[string]string{"adoption-test": label})
+
+			return k8sClient.Update(context.TODO(), latest)
+		}, timeout, interval).Should(Succeed())
+	}
+
+	Specify("DRClusters, stored VRGs and an annotated DRPolicy", func() {
+		// the policy's s3 profiles differ from those of other tests' unannotated VRGs
+		for i, drcluster := range drclusters {
+			drcluster.Spec.S3ProfileName = s3Profiles[i].S3ProfileName
+			createNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: drcluster.Name}})
+			Expect(k8sClient.Create(context.TODO(), drcluster)).To(Succeed())
+		}
+
+		var err error
+
+		objectStore, _, err = fakeObjectStoreGetter{}.ObjectStore(context.TODO(), apiReader,
+			s3Profiles[0].S3ProfileName, "adoption-test", testLogger)
+		Expect(err).NotTo(HaveOccurred())
+
+		for name, states := range vrgStates {
+			adoptionVRGStates[name] = states
+
+			Expect(controllers.VrgObjectProtect(objectStore, ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   namespaceName,
+					Name:        name,
+					Annotations: map[string]string{controllers.DRPolicyNameAnnotation: drpolicy.Name},
+				},
+				Spec: ramen.VolumeReplicationGroupSpec{
+					PVCSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+					S3Profiles:  []string{s3Profiles[0].S3ProfileName, s3Profiles[1].S3ProfileName},
+				},
+			})).To(Succeed())
+		}
+
+		Expect(k8sClient.Create(context.TODO(), drpolicy)).To(Succeed())
+	})
+
+	When("a VRG is primary on a cluster", func() {
+		It("adopts its DRPC preferring that cluster, with a ramen scheduled PlacementRule", func() {
+			drpcAdoptedExpect("primary", "ad-west")
+
+			plRule := &plrv1.PlacementRule{}
+			Expect(apiReader.Get(context.TODO(), drpcKey("primary"), plRule)).To(Succeed())
+			Expect(plRule.Spec.SchedulerName).To(Equal(controllers.RamenScheduler))
+
+			drpc, err := drpcGet("primary")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drpc.Spec.PVCSelector.MatchLabels).To(HaveKeyWithValue("app", "primary"))
+		})
+	})
+
+	When("a VRG is primary on no cluster viewed and only one cluster cannot be viewed", func() {
+		It("adopts its DRPC preferring the unviewed cluster, presumably a lost primary", func() {
+			drpcAdoptedExpect("lost-primary", "ad-west")
+		})
+	})
+
+	When("a VRG is secondary on every cluster or primary on several", func() {
+		It("does not adopt its DRPC and reports the adoption blocked and pending", func() {
+			adoptedConditionExpect(metav1.ConditionFalse, "AdoptionBlocked",
+				"1 of 5 protected applications are secondary on every cluster")
+			adoptedConditionExpect(metav1.ConditionFalse, "AdoptionBlocked", "1 are waiting for their home cluster")
+			drpcAbsentExpect("secondary")
+			drpcAbsentExpect("primaries")
+		})
+	})
+
+	When("a VRG is on no cluster", func() {
+		It("does not adopt its DRPC", func() {
+			drpcAbsentExpect("absent")
+		})
+	})
+
+	When("the blocked application's DRPC is created and the other is primary on one cluster", func() {
+		It("reports every application adopted", func() {
+			Expect(k8sClient.Create(context.TODO(), &ramen.DRPlacementControl{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespaceName, Name: "secondary"},
+				Spec: ramen.DRPlacementControlSpec{
+					PlacementRef:     corev1.ObjectReference{Kind: "PlacementRule", Name: "secondary"},
+					DRPolicyRef:      corev1.ObjectReference{Name: drpolicy.Name},
+					PreferredCluster: "ad-east",
+					PVCSelector:      metav1.LabelSelector{MatchLabels: map[string]string{"app": "secondary"}},
+				},
+			})).To(Succeed())
+			adoptionVRGStates["primaries"] = map[string]ramen.ReplicationState{
+				"ad-east": ramen.Primary, "ad-west": ramen.Secondary,
+			}
+			drpolicyReconcile("unblocked")
+
+			adoptedConditionExpect(metav1.ConditionTrue, "Succeeded", "5 protected applications found")
+			drpcAdoptedExpect("primaries", "ad-east")
+		})
+	})
+
+	Specify("delete DRPCs, the DRPolicy, DRClusters and stored VRGs", func() {
+		deleteAndWait := func(object client.Object) {
+			if err := k8sClient.Delete(context.TODO(), object); err != nil {
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			}
+
+			Eventually(func() bool {
+				return k8serrors.IsNotFound(apiReader.Get(context.TODO(), client.ObjectKeyFromObject(object), object))
+			}, timeout, interval).Should(BeTrue())
+		}
+
+		for name := range vrgStates {
+			deleteAndWait(&ramen.DRPlacementControl{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespaceName, Name: name},
+			})
+
+			delete(adoptionVRGStates, name)
+		}
+
+		deleteAndWait(drpolicy)
+
+		for _, drcluster := range drclusters {
+			deleteAndWait(drcluster)
+		}
+
+		Expect(objectStore.DeleteObjects(namespaceName + "/")).To(Succeed())
+		Expect(objectStore.ListKeys(namespaceName + "/")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,488 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	ocmclv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	autoFailoverGracePeriodDefault            = 5 * time.Minute
+	autoFailoverMinIntervalDefault            = 30 * time.Second
+	autoFailoverMaxConcurrentFailoversDefault = 5
+
+	// autoFailoverRetryDelay is the delay before an application whose
+	// automatic failover is deferred, or waits for fencing, is reconsidered
+	autoFailoverRetryDelay = 30 * time.Second
+)
+
+// autoFailoverState tallies, across the applications of a DRPolicy, the
+// failovers in progress and the failovers blocked until an admin acts
+type autoFailoverState struct {
+	failingOver int
+	blocked     []string
+}
+
+func drPolicyAutoFailoverEnabled(drpolicy *ramen.DRPolicy) bool {
+	return drpolicy.Spec.AutoFailover != nil && drpolicy.Spec.AutoFailover.Enabled
+}
+
+func durationOrDefault(duration *metav1.Duration, durationDefault time.Duration) time.Duration {
+	if duration == nil {
+		return durationDefault
+	}
+
+	return duration.Duration
+}
+
+// requeueAfterMin returns the shorter of two requeue delays, zero meaning no
+// requeue
+func requeueAfterMin(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+
+	return a
+}
+
+// managedClusterUnavailableSince returns whether a ManagedCluster's available
+// condition is other than true, and the time it became so. A ManagedCluster
+// lacking the condition has not reported yet and is not deemed unavailable.
+func managedClusterUnavailableSince(managedCluster *ocmclv1.ManagedCluster) (time.Time, bool) {
+	condition := meta.FindStatusCondition(managedCluster.Status.Conditions,
+		ocmclv1.ManagedClusterConditionAvailable)
+	if condition == nil || condition.Status == metav1.ConditionTrue {
+		return time.Time{}, false
+	}
+
+	return condition.LastTransitionTime.Time, true
+}
+
+// autoFailoverTargetCluster returns the first cluster of the DRPolicy, other
+// than the current one, that is neither unavailable nor fenced, or an empty
+// string if there is none
+func autoFailoverTargetCluster(drpolicy *ramen.DRPolicy, drclusters []ramen.DRCluster,
+	unavailableSince map[string]time.Time, currentClusterName string,
+) string {
+	for _, clusterName := range util.DrpolicyClusterNames(drpolicy) {
+		if clusterName == currentClusterName {
+			continue
+		}
+
+		if _, unavailable := unavailableSince[clusterName]; unavailable {
+			continue
+		}
+
+		drcluster := drClusterFind(drclusters, clusterName)
+		if drcluster == nil || drClusterFenced(drcluster) {
+			continue
+		}
+
+		return clusterName
+	}
+
+	return ""
+}
+
+func drClusterFind(drclusters []ramen.DRCluster, name string) *ramen.DRCluster {
+	for i := range drclusters {
+		if drclusters[i].Name == name {
+			return &drclusters[i]
+		}
+	}
+
+	return nil
+}
+
+// drpcAutoFailoverEligible returns whether a DRPC is settled on its current
+// cluster, so that it may be failed over from it
+func drpcAutoFailoverEligible(drpc *ramen.DRPlacementControl) bool {
+	if !drpc.GetDeletionTimestamp().IsZero() || drpc.Status.PreferredDecision.ClusterName == "" {
+		return false
+	}
+
+	switch drpc.Status.Phase {
+	case ramen.Deployed, ramen.FailedOver, ramen.Relocated:
+		return true
+	default:
+		return false
+	}
+}
+
+// drpcAutoFailedOver returns whether a DRPC is still failed over to the
+// cluster an automatic failover chose, so that it is not failed over again,
+// possibly back to a cluster it was failed over from, until an admin changes
+// its action or failover cluster or removes the annotation
+func drpcAutoFailedOver(drpc *ramen.DRPlacementControl) (string, bool) {
+	clusterName, ok := drpc.GetAnnotations()[DRPCAutoFailedOverAnnotation]
+
+	return clusterName, ok && drpc.Spec.Action == ramen.ActionFailover && drpc.Spec.FailoverCluster == clusterName
+}
+
+// autoFailoverDataLagged returns why a DRPC's data, replicated asynchronously,
+// may not be failed over from an unavailable cluster, or an empty string if it
+// may. Its data must have synced within the data lagging threshold before the
+// cluster became unavailable, lest the failover lose more data than the
+// admin accepted. A zero threshold accepts any lag.
+func autoFailoverDataLagged(drpc *ramen.DRPlacementControl, unavailableSince time.Time,
+	threshold time.Duration,
+) string {
+	if threshold == 0 {
+		return ""
+	}
+
+	lastSyncTime := drpc.Status.LastGroupSyncTime
+	if lastSyncTime == nil {
+		return "its last data sync time is unknown"
+	}
+
+	if lag := unavailableSince.Sub(lastSyncTime.Time); lag > threshold {
+		return fmt.Sprintf("its data synced last at %s, %s before its cluster became unavailable, more than the "+
+			"data lagging threshold of %s", lastSyncTime.Format(time.RFC3339), lag.Round(time.Second), threshold)
+	}
+
+	return ""
+}
+
+// applicationsAutoFailover fails over each application of the DRPolicy whose
+// current cluster's ManagedCluster has been unavailable for the grace period
+// to a surviving cluster of the policy. A metro peer is fenced first, and an
+// asynchronous peer must have data within the data lagging threshold. Failovers
+// are started no more often than the minimum interval and no more than the
+// maximum are in progress at once; the rest are deferred. Failovers blocked
+// until an admin acts are reported by the policy's AutoFailoverBlocked
+// condition.
+func (r *DRPolicyReconciler) applicationsAutoFailover(u *drpolicyUpdater, drclusters []ramen.DRCluster,
+	dataLaggingThreshold time.Duration,
+) (ctrl.Result, error) {
+	gracePeriod := durationOrDefault(u.object.Spec.AutoFailover.GracePeriod, autoFailoverGracePeriodDefault)
+
+	unavailableSince, err := r.managedClustersUnavailableSince(u)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(unavailableSince) == 0 {
+		return ctrl.Result{}, u.autoFailoverBlockedSet(nil)
+	}
+
+	drpcs := ramen.DRPlacementControlList{}
+	if err := r.Client.List(u.ctx, &drpcs); err != nil {
+		return ctrl.Result{}, fmt.Errorf("drpcs list: %w", err)
+	}
+
+	state := &autoFailoverState{}
+	candidates := make([]*ramen.DRPlacementControl, 0)
+	requeueAfter := time.Duration(0)
+
+	for i := range drpcs.Items {
+		drpc := &drpcs.Items[i]
+		if drpc.Spec.DRPolicyRef.Name != u.object.Name {
+			continue
+		}
+
+		if drpc.Spec.Action == ramen.ActionFailover && drpc.Status.Phase != ramen.FailedOver {
+			state.failingOver++
+
+			continue
+		}
+
+		since, unavailable := unavailableSince[drpc.Status.PreferredDecision.ClusterName]
+		if !unavailable || !drpcAutoFailoverEligible(drpc) {
+			continue
+		}
+
+		if wait := time.Until(since.Add(gracePeriod)); wait > 0 {
+			requeueAfter = requeueAfterMin(requeueAfter, wait)
+
+			continue
+		}
+
+		if clusterName, autoFailedOver := drpcAutoFailedOver(drpc); autoFailedOver {
+			r.autoFailoverBlocked(state, drpc, u.log, fmt.Sprintf("it was failed over automatically to cluster %s "+
+				"before; an admin must change its action or failover cluster, or remove its %s annotation",
+				clusterName, DRPCAutoFailedOverAnnotation))
+
+			continue
+		}
+
+		candidates = append(candidates, drpc)
+	}
+
+	// Fail over DRPCs of higher priority first, as the maximum of concurrent failovers may defer the others
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Spec.Priority > candidates[j].Spec.Priority
+	})
+
+	for _, drpc := range candidates {
+		wait, err := r.drpcAutoFailover(u, drpc, drclusters, unavailableSince, dataLaggingThreshold, state)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		requeueAfter = requeueAfterMin(requeueAfter, wait)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, u.autoFailoverBlockedSet(state.blocked)
+}
+
+// autoFailoverBlockedSet sets the DRPolicy's AutoFailoverBlocked condition
+// true, listing the applications whose failover awaits an admin, if any, and
+// false otherwise
+func (u *drpolicyUpdater) autoFailoverBlockedSet(blocked []string) error {
+	if len(blocked) == 0 {
+		return u.statusConditionSet(ramen.DRPolicyAutoFailoverBlocked, metav1.ConditionFalse, "NotBlocked",
+			"no automatic failover awaits an admin")
+	}
+
+	return u.statusConditionSet(ramen.DRPolicyAutoFailoverBlocked, metav1.ConditionTrue, "AdminActionRequired",
+		fmt.Sprintf("%d automatic failovers await an admin: %s", len(blocked), strings.Join(blocked, "; ")))
+}
+
+// autoFailoverBlockedRemove removes the DRPolicy's AutoFailoverBlocked
+// condition, once automatic failover is disabled
+func (u *drpolicyUpdater) autoFailoverBlockedRemove() error {
+	if meta.FindStatusCondition(u.object.Status.Conditions, ramen.DRPolicyAutoFailoverBlocked) == nil {
+		return nil
+	}
+
+	meta.RemoveStatusCondition(&u.object.Status.Conditions, ramen.DRPolicyAutoFailoverBlocked)
+
+	return u.statusUpdate()
+}
+
+// managedClustersUnavailableSince returns the time each unavailable cluster of
+// the DRPolicy became so
+func (r *DRPolicyReconciler) managedClustersUnavailableSince(u *drpolicyUpdater) (map[string]time.Time, error) {
+	unavailableSince := make(map[string]time.Time)
+
+	for _, clusterName := range util.DrpolicyClusterNames(u.object) {
+		managedCluster := &ocmclv1.ManagedCluster{}
+		if err := r.Client.Get(u.ctx, types.NamespacedName{Name: clusterName}, managedCluster); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("managed cluster %s get: %w", clusterName, err)
+		}
+
+		if since, unavailable := managedClusterUnavailableSince(managedCluster); unavailable {
+			unavailableSince[clusterName] = since
+		}
+	}
+
+	return unavailableSince, nil
+}
+
+// drpcAutoFailover starts the failover of a DRPC from its unavailable current
+// cluster unless it must wait, in which case it returns the delay before the
+// DRPC is to be reconsidered
+func (r *DRPolicyReconciler) drpcAutoFailover(u *drpolicyUpdater, drpc *ramen.DRPlacementControl,
+	drclusters []ramen.DRCluster, unavailableSince map[string]time.Time, dataLaggingThreshold time.Duration,
+	state *autoFailoverState,
+) (time.Duration, error) {
+	spec := u.object.Spec.AutoFailover
+	currentClusterName := drpc.Status.PreferredDecision.ClusterName
+	log := u.log.WithValues("DRPC", types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name})
+
+	targetClusterName := autoFailoverTargetCluster(u.object, drclusters, unavailableSince, currentClusterName)
+	if targetClusterName == "" {
+		r.autoFailoverDeferred(drpc, log, fmt.Sprintf("no surviving cluster to fail over to from cluster %s",
+			currentClusterName))
+
+		return autoFailoverRetryDelay, nil
+	}
+
+	if isMetroAction(u.object, drclusters, currentClusterName, targetClusterName) {
+		fenced, err := r.autoFailoverFence(u, drpc, drclusters, currentClusterName, log, state)
+		if err != nil || !fenced {
+			return autoFailoverRetryDelay, err
+		}
+	} else if reason := autoFailoverDataLagged(drpc, unavailableSince[currentClusterName],
+		dataLaggingThreshold); reason != "" {
+		r.autoFailoverBlocked(state, drpc, log, reason)
+
+		return autoFailoverRetryDelay, nil
+	}
+
+	maxConcurrentFailovers := spec.MaxConcurrentFailovers
+	if maxConcurrentFailovers == 0 {
+		maxConcurrentFailovers = autoFailoverMaxConcurrentFailoversDefault
+	}
+
+	if state.failingOver >= maxConcurrentFailovers {
+		r.autoFailoverDeferred(drpc, log, fmt.Sprintf("%d failovers in progress, the maximum", state.failingOver))
+
+		return autoFailoverRetryDelay, nil
+	}
+
+	if last := u.object.Status.LastAutoFailoverTime; last != nil {
+		minInterval := durationOrDefault(spec.MinInterval, autoFailoverMinIntervalDefault)
+		if wait := time.Until(last.Add(minInterval)); wait > 0 {
+			r.autoFailoverDeferred(drpc, log, fmt.Sprintf("previous automatic failover started at %s",
+				last.Format(time.RFC3339)))
+
+			return wait, nil
+		}
+	}
+
+	drpc.Spec.Action = ramen.ActionFailover
+	drpc.Spec.FailoverCluster = targetClusterName
+
+	if drpc.Annotations == nil {
+		drpc.Annotations = map[string]string{}
+	}
+
+	drpc.Annotations[DRPCAutoFailedOverAnnotation] = targetClusterName
+
+	if err := r.Client.Update(u.ctx, drpc); err != nil {
+		return 0, fmt.Errorf("drpc %s/%s update: %w", drpc.Namespace, drpc.Name, err)
+	}
+
+	state.failingOver++
+
+	u.object.Status.LastAutoFailoverTime = &metav1.Time{Time: time.Now()}
+	if err := u.statusUpdate(); err != nil {
+		return 0, fmt.Errorf("last automatic failover time update: %w", err)
+	}
+
+	msg := fmt.Sprintf("Failing over %s/%s from unavailable cluster %s to cluster %s", drpc.Namespace, drpc.Name,
+		currentClusterName, targetClusterName)
+	log.Info(msg)
+	util.ReportIfNotPresent(r.eventRecorder, drpc, corev1.EventTypeWarning, util.EventReasonAutoFailoverStarted, msg)
+	util.ReportIfNotPresent(r.eventRecorder, u.object, corev1.EventTypeWarning, util.EventReasonAutoFailoverStarted,
+		msg)
+
+	return 0, nil
+}
+
+func (r *DRPolicyReconciler) autoFailoverDeferred(drpc *ramen.DRPlacementControl, log logr.Logger, reason string) {
+	msg := "Automatic failover deferred: " + reason
+	log.Info(msg)
+	util.ReportIfNotPresent(r.eventRecorder, drpc, corev1.EventTypeNormal, util.EventReasonAutoFailoverDeferred, msg)
+}
+
+// autoFailoverBlocked defers a DRPC's failover until an admin acts, and
+// records it for the DRPolicy's AutoFailoverBlocked condition
+func (r *DRPolicyReconciler) autoFailoverBlocked(state *autoFailoverState, drpc *ramen.DRPlacementControl,
+	log logr.Logger, reason string,
+) {
+	state.blocked = append(state.blocked, fmt.Sprintf("%s/%s: %s", drpc.Namespace, drpc.Name, reason))
+	r.autoFailoverDeferred(drpc, log, reason)
+}
+
+// autoFailoverFence returns whether an unavailable cluster is fenced, and
+// requests that it be if it is not yet. A failover to a metro peer requires
+// it, lest the application run on both clusters of the shared storage. The
+// DRPC's failover is deferred, with the reason, while the fence is in progress,
+// and blocked until an admin acts while the fence awaits confirmation or cannot
+// complete, as a cluster fenced by cordoning its own nodes cannot while it is
+// unavailable.
+func (r *DRPolicyReconciler) autoFailoverFence(u *drpolicyUpdater, drpc *ramen.DRPlacementControl,
+	drclusters []ramen.DRCluster, clusterName string, log logr.Logger, state *autoFailoverState,
+) (bool, error) {
+	drcluster := drClusterFind(drclusters, clusterName)
+	if drcluster == nil {
+		return false, fmt.Errorf("drcluster %s not found", clusterName)
+	}
+
+	if drClusterFenced(drcluster) {
+		return true, nil
+	}
+
+	if drcluster.Spec.ClusterFence != ramen.ClusterFenceStateFenced &&
+		drcluster.Spec.ClusterFence != ramen.ClusterFenceStateManuallyFenced {
+		drcluster.Spec.ClusterFence = ramen.ClusterFenceStateFenced
+		if err := r.Client.Update(u.ctx, drcluster); err != nil {
+			return false, fmt.Errorf("drcluster %s update: %w", clusterName, err)
+		}
+
+		msg := fmt.Sprintf("Fencing unavailable cluster %s to fail over its metro applications", clusterName)
+		u.log.Info(msg)
+		util.ReportIfNotPresent(r.eventRecorder, drcluster, corev1.EventTypeWarning,
+			util.EventReasonAutoFailoverFencing, msg)
+	}
+
+	if drcluster.Spec.ClusterFence == ramen.ClusterFenceStateFenced {
+		if confirmed, message := fenceConfirmed(drcluster, time.Now()); !confirmed {
+			r.autoFailoverBlocked(state, drpc, log, fmt.Sprintf("fencing cluster %s is not confirmed: %s",
+				clusterName, message))
+
+			return false, nil
+		}
+
+		if drcluster.Spec.FencingProvider == ramen.FencingProviderNodeCordon {
+			r.autoFailoverBlocked(state, drpc, log, fmt.Sprintf("cluster %s is fenced by cordoning its own nodes, "+
+				"which it cannot while unavailable; an admin must fence it and set its clusterFence to %s",
+				clusterName, ramen.ClusterFenceStateManuallyFenced))
+
+			return false, nil
+		}
+	}
+
+	r.autoFailoverDeferred(drpc, log, fmt.Sprintf("waiting for cluster %s to be fenced", clusterName))
+
+	return false, nil
+}
+
+// managedClusterMapFunc requests the reconciliation of the DRPolicies, with
+// automatic failover enabled, that include a ManagedCluster whose status
+// changed
+func (r *DRPolicyReconciler) managedClusterMapFunc(managedCluster client.Object) []reconcile.Request {
+	drpolicies := &ramen.DRPolicyList{}
+	if err := r.Client.List(context.TODO(), drpolicies); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+
+	for i := range drpolicies.Items {
+		drpolicy := &drpolicies.Items[i]
+		if !drPolicyAutoFailoverEnabled(drpolicy) {
+			continue
+		}
+
+		for _, clusterName := range util.DrpolicyClusterNames(drpolicy) {
+			if clusterName == managedCluster.GetName() {
+				requests = append(requests,
+					reconcile.Request{NamespacedName: types.NamespacedName{Name: drpolicy.Name}})
+
+				break
+			}
+		}
+	}
+
+	return requests
+}
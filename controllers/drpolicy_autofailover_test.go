@@ -0,0 +1,421 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ocmclv1 "github.com/open-cluster-management/api/cluster/v1"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("DRPolicy automatic failover", func() {
+	const namespaceName = "autofailover-app"
+
+	drclusters := []*ramen.DRCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "af-west1"},
+			Spec:       ramen.DRClusterSpec{Region: "af-west"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "af-central1"},
+			Spec:       ramen.DRClusterSpec{Region: "af-central"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "af-east1"},
+			Spec:       ramen.DRClusterSpec{Region: "af-east", RequireFenceConfirmation: true},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "af-east2"},
+			Spec:       ramen.DRClusterSpec{Region: "af-east"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "af-north1"},
+			Spec:       ramen.DRClusterSpec{Region: "af-north", FencingProvider: ramen.FencingProviderNodeCordon},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "af-north2"},
+			Spec:       ramen.DRClusterSpec{Region: "af-north"},
+		},
+	}
+	asyncDRPolicy := &ramen.DRPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "af-async"},
+		Spec: ramen.DRPolicySpec{
+			DRClusters:         []string{"af-west1", "af-central1"},
+			SchedulingInterval: "1m",
+			AutoFailover: &ramen.AutoFailoverSpec{
+				Enabled:                true,
+				GracePeriod:            &metav1.Duration{Duration: time.Minute},
+				MinInterval:            &metav1.Duration{Duration: time.Hour},
+				MaxConcurrentFailovers: 1,
+			},
+		},
+	}
+	metroDRPolicy := &ramen.DRPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "af-metro"},
+		Spec: ramen.DRPolicySpec{
+			DRClusters:         []string{"af-east1", "af-east2"},
+			SchedulingInterval: "1m",
+			AutoFailover: &ramen.AutoFailoverSpec{
+				Enabled:     true,
+				GracePeriod: &metav1.Duration{Duration: time.Minute},
+			},
+		},
+	}
+	cordonDRPolicy := &ramen.DRPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "af-metro-cordon"},
+		Spec: ramen.DRPolicySpec{
+			DRClusters:         []string{"af-north1", "af-north2"},
+			SchedulingInterval: "1m",
+			AutoFailover: &ramen.AutoFailoverSpec{
+				Enabled:     true,
+				GracePeriod: &metav1.Duration{Duration: time.Minute},
+			},
+		},
+	}
+	drpcs := []*ramen.DRPlacementControl{}
+
+	drpcCreate := func(name, drpolicyName, clusterName string, priority int) *ramen.DRPlacementControl {
+		drpc := &ramen.DRPlacementControl{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespaceName, Name: name},
+			Spec: ramen.DRPlacementControlSpec{
+				PlacementRef: corev1.ObjectReference{Name: "af-placement"},
+				DRPolicyRef:  corev1.ObjectReference{Name: drpolicyName},
+				Priority:     priority,
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), drpc)).To(Succeed())
+
+		drpcStatusUpdate(drpc, ramen.Deployed, clusterName)
+
+		return drpc
+	}
+	drpcGet := func(drpc *ramen.DRPlacementControl) *ramen.DRPlacementControl {
+		latest := &ramen.DRPlacementControl{}
+		Expect(apiReader.Get(context.TODO(), types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name},
+			latest)).To(Succeed())
+
+		return latest
+	}
+	drpcFailoverExpect := func(drpc *ramen.DRPlacementControl, clusterName string) {
+		Eventually(func(g Gomega) {
+			latest := drpcGet(drpc)
+			g.Expect(latest.Spec.Action).To(Equal(ramen.ActionFailover))
+			g.Expect(latest.Spec.FailoverCluster).To(Equal(clusterName))
+		}, timeout, interval).Should(Succeed())
+	}
+	drpcActionNoneExpect := func(drpc *ramen.DRPlacementControl) {
+		Expect(drpcGet(drpc).Spec.Action).To(BeEmpty())
+	}
+	drpcDeferredEventExpect := func(drpc *ramen.DRPlacementControl, messageSubstring string) {
+		Eventually(func() bool {
+			events := &corev1.EventList{}
+			Expect(k8sClient.List(context.TODO(), events, client.InNamespace(drpc.Namespace))).To(Succeed())
+
+			for _, event := range events.Items {
+				if event.InvolvedObject.Name == drpc.Name &&
+					event.Reason == util.EventReasonAutoFailoverDeferred &&
+					strings.Contains(event.Message, messageSubstring) {
+					return true
+				}
+			}
+
+			return false
+		}, timeout, interval).Should(BeTrue(), "no deferred event %q for drpc %s", messageSubstring, drpc.Name)
+	}
+	drpolicyAutoFailoverBlockedExpect := func(drpolicy *ramen.DRPolicy, status metav1.ConditionStatus,
+		messageSubstring string,
+	) {
+		Eventually(func(g Gomega) {
+			latest := &ramen.DRPolicy{}
+			g.Expect(apiReader.Get(context.TODO(), types.NamespacedName{Name: drpolicy.Name}, latest)).To(Succeed())
+
+			condition := meta.FindStatusCondition(latest.Status.Conditions, ramen.DRPolicyAutoFailoverBlocked)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(status))
+			g.Expect(condition.Message).To(ContainSubstring(messageSubstring))
+		}, timeout, interval).Should(Succeed())
+	}
+	// managedClusterAvailableSet sets a ManagedCluster's available condition
+	// to a status since a time, with a message that differs at each call so
+	// that its DRPolicies are reconciled
+	managedClusterAvailableSet := func(name string, status metav1.ConditionStatus, since time.Time,
+		message string,
+	) {
+		managedCluster := &ocmclv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if err := k8sClient.Create(context.TODO(), managedCluster); err != nil {
+			Expect(k8serrors.IsAlreadyExists(err)).To(BeTrue())
+		}
+
+		Eventually(func() error {
+			if err := apiReader.Get(context.TODO(), types.NamespacedName{Name: name}, managedCluster); err != nil {
+				return err
+			}
+
+			meta.SetStatusCondition(&managedCluster.Status.Conditions, metav1.Condition{
+				Type:    ocmclv1.ManagedClusterConditionAvailable,
+				Status:  status,
+				Reason:  "ManagedClusterLeaseUpdateStopped",
+				Message: message,
+			})
+			meta.FindStatusCondition(managedCluster.Status.Conditions,
+				ocmclv1.ManagedClusterConditionAvailable).LastTransitionTime = metav1.NewTime(since)
+
+			return k8sClient.Status().Update(context.TODO(), managedCluster)
+		}, timeout, interval).Should(Succeed())
+	}
+	managedClusterUnavailableSet := func(name string, since time.Time, message string) {
+		managedClusterAvailableSet(name, metav1.ConditionUnknown, since, message)
+	}
+	// drclusterFenceManuallyAndFailoverExpect fences an unavailable metro
+	// cluster manually, as an admin would, and expects its application to fail
+	// over to its peer
+	drclusterFenceManuallyAndFailoverExpect := func(clusterName string, drpc *ramen.DRPlacementControl,
+		peerClusterName string,
+	) {
+		Eventually(func() error {
+			drcluster := drclusterGet(clusterName)
+			drcluster.Spec.ClusterFence = ramen.ClusterFenceStateManuallyFenced
+
+			return k8sClient.Update(context.TODO(), drcluster)
+		}, timeout, interval).Should(Succeed())
+		Eventually(func() bool {
+			drcluster := drclusterGet(clusterName)
+			condition := meta.FindStatusCondition(drcluster.Status.Conditions, ramen.DRClusterConditionTypeFenced)
+
+			return condition != nil && condition.Status == metav1.ConditionTrue &&
+				condition.ObservedGeneration == drcluster.Generation
+		}, timeout, interval).Should(BeTrue())
+
+		// the DRPolicy reconciler's cache may not have the fenced DRCluster
+		// yet as it reconciles a ManagedCluster update, so it is updated
+		// until the application fails over
+		updates := 0
+		Eventually(func() ramen.DRAction {
+			updates++
+			managedClusterUnavailableSet(clusterName, time.Now().Add(-time.Hour), fmt.Sprintf("fenced %d", updates))
+
+			return drpcGet(drpc).Spec.Action
+		}, timeout, time.Second).Should(Equal(ramen.ActionFailover))
+		drpcFailoverExpect(drpc, peerClusterName)
+	}
+
+	Specify("DRClusters, DRPolicies and DRPCs", func() {
+		for _, drcluster := range drclusters {
+			drcluster.Spec.S3ProfileName = s3Profiles[0].S3ProfileName
+			createNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: drcluster.Name}})
+			Expect(k8sClient.Create(context.TODO(), drcluster)).To(Succeed())
+		}
+
+		Expect(k8sClient.Create(context.TODO(), asyncDRPolicy)).To(Succeed())
+		Expect(k8sClient.Create(context.TODO(), metroDRPolicy)).To(Succeed())
+		Expect(k8sClient.Create(context.TODO(), cordonDRPolicy)).To(Succeed())
+
+		createNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName}})
+		drpcs = append(drpcs,
+			drpcCreate("af-drpc-high", asyncDRPolicy.Name, "af-west1", 1),
+			drpcCreate("af-drpc-low", asyncDRPolicy.Name, "af-west1", 0),
+			drpcCreate("af-drpc-metro", metroDRPolicy.Name, "af-east1", 0),
+			drpcCreate("af-drpc-lagging", asyncDRPolicy.Name, "af-west1", 2),
+			drpcCreate("af-drpc-cordon", cordonDRPolicy.Name, "af-north1", 0),
+		)
+		drpcLastGroupSyncTimeUpdate(drpcs[3], time.Now().Add(-2*time.Hour))
+	})
+
+	When("a cluster is unavailable for less than the grace period", func() {
+		It("does not fail over its applications", func() {
+			managedClusterUnavailableSet("af-west1", time.Now(), "lost")
+
+			Consistently(func() ramen.DRAction {
+				return drpcGet(drpcs[0]).Spec.Action
+			}, 2*time.Second, interval).Should(BeEmpty())
+			drpcActionNoneExpect(drpcs[1])
+		})
+	})
+
+	When("a cluster is unavailable for the grace period", func() {
+		It("fails over its application of highest priority to a surviving cluster, up to the maximum", func() {
+			managedClusterUnavailableSet("af-west1", time.Now().Add(-time.Hour), "lost an hour ago")
+
+			drpcFailoverExpect(drpcs[0], "af-central1")
+			Eventually(func() *metav1.Time {
+				drpolicy := &ramen.DRPolicy{}
+				Expect(apiReader.Get(context.TODO(), types.NamespacedName{Name: asyncDRPolicy.Name},
+					drpolicy)).To(Succeed())
+
+				return drpolicy.Status.LastAutoFailoverTime
+			}, timeout, interval).ShouldNot(BeNil())
+
+			drpcDeferredEventExpect(drpcs[1], "1 failovers in progress, the maximum")
+			drpcActionNoneExpect(drpcs[1])
+		})
+
+		It("blocks the failover of its application whose data lags, and reports it in the DRPolicy", func() {
+			drpcDeferredEventExpect(drpcs[3], "more than the data lagging threshold")
+			drpcActionNoneExpect(drpcs[3])
+			drpolicyAutoFailoverBlockedExpect(asyncDRPolicy, metav1.ConditionTrue, "af-drpc-lagging")
+		})
+
+		It("defers the failover of its other applications for the minimum interval", func() {
+			drpcStatusUpdate(drpcs[0], ramen.FailedOver, "af-central1")
+			managedClusterUnavailableSet("af-west1", time.Now().Add(-time.Hour), "still lost")
+
+			drpcDeferredEventExpect(drpcs[1], "previous automatic failover started at")
+			drpcActionNoneExpect(drpcs[1])
+
+			Eventually(func() error {
+				drpolicy := &ramen.DRPolicy{}
+				if err := apiReader.Get(context.TODO(), types.NamespacedName{Name: asyncDRPolicy.Name},
+					drpolicy); err != nil {
+					return err
+				}
+
+				drpolicy.Spec.AutoFailover.MinInterval = &metav1.Duration{Duration: time.Second}
+
+				return k8sClient.Update(context.TODO(), drpolicy)
+			}, timeout, interval).Should(Succeed())
+
+			drpcFailoverExpect(drpcs[1], "af-central1")
+		})
+	})
+
+	When("the cluster an application was failed over to automatically is unavailable", func() {
+		It("does not fail it over again until an admin acts", func() {
+			drpcStatusUpdate(drpcs[1], ramen.FailedOver, "af-central1")
+			managedClusterAvailableSet("af-west1", metav1.ConditionTrue, time.Now(), "recovered")
+			managedClusterUnavailableSet("af-central1", time.Now().Add(-time.Hour), "lost an hour ago")
+
+			drpcDeferredEventExpect(drpcs[0], "it was failed over automatically to cluster af-central1 before")
+			drpcDeferredEventExpect(drpcs[1], "it was failed over automatically to cluster af-central1 before")
+			drpcFailoverExpect(drpcs[0], "af-central1")
+			drpcFailoverExpect(drpcs[1], "af-central1")
+			drpolicyAutoFailoverBlockedExpect(asyncDRPolicy, metav1.ConditionTrue, "2 automatic failovers await")
+		})
+	})
+
+	When("a metro cluster is unavailable for the grace period", func() {
+		It("requests its fence and defers the failover while the fence is not confirmed", func() {
+			managedClusterUnavailableSet("af-east1", time.Now().Add(-time.Hour), "lost an hour ago")
+
+			Eventually(func() ramen.ClusterFenceState {
+				return drclusterGet("af-east1").Spec.ClusterFence
+			}, timeout, interval).Should(Equal(ramen.ClusterFenceStateFenced))
+
+			drpcDeferredEventExpect(drpcs[2], "fencing cluster af-east1 is not confirmed")
+			drpcActionNoneExpect(drpcs[2])
+		})
+
+		It("fails over its application to its metro peer once it is fenced", func() {
+			drclusterFenceManuallyAndFailoverExpect("af-east1", drpcs[2], "af-east2")
+			drpolicyAutoFailoverBlockedExpect(metroDRPolicy, metav1.ConditionFalse, "")
+		})
+	})
+
+	When("a metro cluster fenced by cordoning its nodes is unavailable for the grace period", func() {
+		It("blocks the failover of its application, and reports it in the DRPolicy", func() {
+			managedClusterUnavailableSet("af-north1", time.Now().Add(-time.Hour), "lost an hour ago")
+
+			drpcDeferredEventExpect(drpcs[4], "is fenced by cordoning its own nodes")
+			drpcActionNoneExpect(drpcs[4])
+			drpolicyAutoFailoverBlockedExpect(cordonDRPolicy, metav1.ConditionTrue, "an admin must fence it")
+		})
+
+		It("fails over its application to its metro peer once an admin fences it", func() {
+			drclusterFenceManuallyAndFailoverExpect("af-north1", drpcs[4], "af-north2")
+			drpolicyAutoFailoverBlockedExpect(cordonDRPolicy, metav1.ConditionFalse, "")
+		})
+	})
+
+	Specify("delete DRPCs, DRPolicies, DRClusters and ManagedClusters", func() {
+		deleteAndWait := func(object client.Object) {
+			Expect(k8sClient.Delete(context.TODO(), object)).To(Succeed())
+			Eventually(func() bool {
+				return k8serrors.IsNotFound(apiReader.Get(context.TODO(), client.ObjectKeyFromObject(object), object))
+			}, timeout, interval).Should(BeTrue())
+		}
+
+		for _, drpc := range drpcs {
+			deleteAndWait(drpc)
+		}
+
+		deleteAndWait(asyncDRPolicy)
+		deleteAndWait(metroDRPolicy)
+		deleteAndWait(cordonDRPolicy)
+
+		for _, drcluster := range drclusters {
+			deleteAndWait(drcluster)
+		}
+
+		for _, name := range []string{"af-west1", "af-central1", "af-east1", "af-north1"} {
+			deleteAndWait(&ocmclv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+	})
+})
+
+// drpcStatusUpdate sets a DRPC's phase and current cluster, as its reconciler
+// would once it placed the application there
+func drpcStatusUpdate(drpc *ramen.DRPlacementControl, phase ramen.DRState, clusterName string) {
+	Eventually(func() error {
+		latest := &ramen.DRPlacementControl{}
+		if err := apiReader.Get(context.TODO(), types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name},
+			latest); err != nil {
+			return err
+		}
+
+		latest.Status.Phase = phase
+		latest.Status.PreferredDecision.ClusterName = clusterName
+		latest.Status.LastUpdateTime = metav1.Now()
+		latest.Status.LastGroupSyncTime = &latest.Status.LastUpdateTime
+
+		return k8sClient.Status().Update(context.TODO(), latest)
+	}, timeout, interval).Should(Succeed())
+}
+
+// drpcLastGroupSyncTimeUpdate sets the time a DRPC's data was synced last, as
+// its reconciler would from its primary VRG
+func drpcLastGroupSyncTimeUpdate(drpc *ramen.DRPlacementControl, lastGroupSyncTime time.Time) {
+	Eventually(func() error {
+		latest := &ramen.DRPlacementControl{}
+		if err := apiReader.Get(context.TODO(), types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name},
+			latest); err != nil {
+			return err
+		}
+
+		latest.Status.LastGroupSyncTime = &metav1.Time{Time: lastGroupSyncTime}
+
+		return k8sClient.Status().Update(context.TODO(), latest)
+	}, timeout, interval).Should(Succeed())
+}
+
+func drclusterGet(name string) *ramen.DRCluster {
+	drcluster := &ramen.DRCluster{}
+	Expect(apiReader.Get(context.TODO(), types.NamespacedName{Name: name}, drcluster)).To(Succeed())
+
+	return drcluster
+}
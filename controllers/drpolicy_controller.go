@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	ocmclv1 "github.com/open-cluster-management/api/cluster/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -44,6 +45,8 @@ type DRPolicyReconciler struct {
 	APIReader         client.Reader
 	Scheme            *runtime.Scheme
 	ObjectStoreGetter ObjectStoreGetter
+	MCVGetter         util.ManagedClusterViewGetter
+	eventRecorder     *util.EventReporter
 }
 
 // ReasonValidationFailed is set when the DRPolicy could not be validated or is not valid
@@ -55,7 +58,10 @@ const ReasonValidationFailed = "ValidationFailed"
 //+kubebuilder:rbac:groups=ramendr.openshift.io,resources=drpolicies/finalizers,verbs=update
 // +kubebuilder:rbac:groups=work.open-cluster-management.io,resources=manifestworks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=list;watch
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ramendr.openshift.io,resources=drplacementcontrols,verbs=list;update
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;create
 // +kubebuilder:rbac:groups="policy.open-cluster-management.io",resources=placementbindings,verbs=list;watch
 // +kubebuilder:rbac:groups="policy.open-cluster-management.io",resources=policies,verbs=list;watch
 // +kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;update
@@ -117,7 +123,31 @@ func (r *DRPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, fmt.Errorf("drpolicy deploy: %w", u.validatedSetFalse("DrClustersDeployFailed", err))
 	}
 
-	return ctrl.Result{}, u.validatedSetTrue("Succeeded", "drpolicy validated")
+	if err := u.validatedSetTrue("Succeeded", "drpolicy validated"); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result := ctrl.Result{}
+
+	if drPolicyAdoptRequested(drpolicy) {
+		if result, err = r.protectedApplicationsAdopt(u, drclusters.Items); err != nil {
+			return result, err
+		}
+	}
+
+	if !drPolicyAutoFailoverEnabled(drpolicy) {
+		return result, u.autoFailoverBlockedRemove()
+	}
+
+	autoFailoverResult, err := r.applicationsAutoFailover(u, drclusters.Items,
+		drpcDataLaggingThresholdOrZero(drpolicy, ramenConfig))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("automatic failover: %w", err)
+	}
+
+	result.RequeueAfter = requeueAfterMin(result.RequeueAfter, autoFailoverResult.RequeueAfter)
+
+	return result, nil
 }
 
 func validateDRPolicy(ctx context.Context,
@@ -277,6 +307,15 @@ func (u *drpolicyUpdater) validatedSetFalse(reason string, err error) error {
 	return err
 }
 
+func (u *drpolicyUpdater) adoptedSetFalse(reason string, err error) error {
+	if err1 := u.statusConditionSet(ramen.DRPolicyProtectedApplicationsAdopted, metav1.ConditionFalse, reason,
+		err.Error()); err1 != nil {
+		return err1
+	}
+
+	return err
+}
+
 func (u *drpolicyUpdater) statusConditionSet(conditionType string,
 	status metav1.ConditionStatus,
 	reason, message string,
@@ -307,6 +346,8 @@ func (u *drpolicyUpdater) finalizerRemove() error {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DRPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.eventRecorder = util.NewEventReporter(mgr.GetEventRecorderFor("controller_DRPolicy"))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ramen.DRPolicy{}).
 		Watches(
@@ -319,6 +360,11 @@ func (r *DRPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.secretMapFunc),
 			builder.WithPredicates(createOrDeleteOrResourceVersionUpdatePredicate{}),
 		).
+		Watches(
+			&source.Kind{Type: &ocmclv1.ManagedCluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.managedClusterMapFunc),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		Complete(r)
 }
 
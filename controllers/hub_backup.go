@@ -0,0 +1,492 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+// hubBackupKeyPrefix is the key prefix of the hub objects backed up to an
+// object store. Having dots, it is not a namespace name, so it is not the key
+// prefix of any VRG.
+const hubBackupKeyPrefix = "hub.ramendr.openshift.io/"
+
+// hubBackupOwnerKey is the key of the HubBackupOwner of the hub objects
+// backed up to an object store
+const hubBackupOwnerKey = hubBackupKeyPrefix + "owner"
+
+// hubBackupStatusConfigMapName is the name of the config map, in the hub
+// operator's namespace, that records the hub's restore. Its UID identifies
+// the hub in the backups it owns.
+const hubBackupStatusConfigMapName = "ramen-hub-backup-status"
+
+const (
+	hubBackupRestoredS3ProfileNameKey = "restoredS3ProfileName"
+	hubBackupRestoreTimeKey           = "restoreTime"
+)
+
+const (
+	hubBackupIntervalDefault = 5 * time.Minute
+	hubRestoreRetryDelay     = time.Minute
+)
+
+// +kubebuilder:rbac:groups="",namespace=system,resources=configmaps,verbs=get;create;update
+
+// HubObjects are the hub objects backed up to the object stores of the
+// DRPolicies
+type HubObjects struct {
+	DRPolicies          []ramen.DRPolicy
+	DRClusters          []ramen.DRCluster
+	DRPlacementControls []ramen.DRPlacementControl
+}
+
+// HubBackupOwner identifies the hub that owns the backup in an object store,
+// which only that hub prunes of the objects it lacks
+type HubBackupOwner struct {
+	HubID string
+}
+
+// HubBackupRunnable returns a runnable that, if the ramen config's hub backup
+// restore profile is set and the hub has not restored before, first restores
+// the hub objects backed up to that profile's store, and then backs up the hub
+// objects at the configured interval to the store of each S3 profile of a
+// DRPolicy
+func HubBackupRunnable(c client.Client, apiReader client.Reader, objectStoreGetter ObjectStoreGetter,
+	log logr.Logger,
+) manager.RunnableFunc {
+	return func(ctx context.Context) error {
+		for !hubRestore(ctx, c, apiReader, objectStoreGetter, log) {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(hubRestoreRetryDelay):
+			}
+		}
+
+		for {
+			interval := hubBackup(ctx, c, apiReader, objectStoreGetter, log)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	}
+}
+
+// hubRestore returns whether the restore, if configured, is done. A restore
+// is recorded in the hub backup status config map, so that it is done once,
+// rather than at each start, which would recreate the objects deleted since.
+func hubRestore(ctx context.Context, c client.Client, apiReader client.Reader,
+	objectStoreGetter ObjectStoreGetter, log logr.Logger,
+) bool {
+	const done = true
+
+	_, ramenConfig, err := ConfigMapGet(ctx, apiReader)
+	if err != nil {
+		log.Info("Hub restore delayed; config map get failed", "error", err.Error())
+
+		return !done
+	}
+
+	s3ProfileName := ramenConfig.HubBackup.RestoreS3ProfileName
+	if s3ProfileName == "" {
+		return done
+	}
+
+	log = log.WithValues("profile", s3ProfileName)
+
+	statusConfigMap, err := hubBackupStatusGetOrCreate(ctx, c, apiReader)
+	if err != nil {
+		log.Info("Hub restore delayed; status get failed", "error", err.Error())
+
+		return !done
+	}
+
+	if restoredS3ProfileName, restored := statusConfigMap.Data[hubBackupRestoredS3ProfileNameKey]; restored {
+		log.Info("Hub restore skipped; restored before", "restored profile", restoredS3ProfileName,
+			"time", statusConfigMap.Data[hubBackupRestoreTimeKey])
+
+		return done
+	}
+
+	objectStorer, _, err := objectStoreGetter.ObjectStore(ctx, apiReader, s3ProfileName, "hub restore", log)
+	if err != nil {
+		log.Info("Hub restore delayed; object store get failed", "error", err.Error())
+
+		return !done
+	}
+
+	hubObjects, err := HubObjectsDownload(objectStorer)
+	if err != nil {
+		log.Info("Hub restore delayed; download failed", "error", err.Error())
+
+		return !done
+	}
+
+	if err := HubObjectsRestore(ctx, c, hubObjects, log); err != nil {
+		log.Info("Hub restore delayed; restore failed", "error", err.Error())
+
+		return !done
+	}
+
+	statusConfigMap.Data = map[string]string{
+		hubBackupRestoredS3ProfileNameKey: s3ProfileName,
+		hubBackupRestoreTimeKey:           time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if err := c.Update(ctx, statusConfigMap); err != nil {
+		log.Info("Hub restore delayed; status update failed", "error", err.Error())
+
+		return !done
+	}
+
+	log.Info("Hub restored", "DRPolicies", len(hubObjects.DRPolicies), "DRClusters", len(hubObjects.DRClusters),
+		"DRPlacementControls", len(hubObjects.DRPlacementControls))
+
+	return done
+}
+
+// hubBackupStatusGetOrCreate returns the hub backup status config map,
+// creating it if it does not exist
+func hubBackupStatusGetOrCreate(ctx context.Context, c client.Client, apiReader client.Reader,
+) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{Namespace: NamespaceName(), Name: hubBackupStatusConfigMapName}
+
+	err := apiReader.Get(ctx, namespacedName, configMap)
+	if err == nil {
+		return configMap, nil
+	}
+
+	if !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("config map %s get: %w", namespacedName, err)
+	}
+
+	configMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespacedName.Namespace, Name: namespacedName.Name},
+	}
+
+	if err := c.Create(ctx, configMap); err != nil {
+		return nil, fmt.Errorf("config map %s create: %w", namespacedName, err)
+	}
+
+	return configMap, nil
+}
+
+// hubBackup returns the time until the next backup. The hub takes over the
+// backups of other hubs once it has restored, or if the ramen config's hub
+// backup says to.
+func hubBackup(ctx context.Context, c client.Client, apiReader client.Reader, objectStoreGetter ObjectStoreGetter,
+	log logr.Logger,
+) time.Duration {
+	_, ramenConfig, err := ConfigMapGet(ctx, apiReader)
+	if err != nil {
+		log.Info("Hub backup skipped; config map get failed", "error", err.Error())
+
+		return hubBackupIntervalDefault
+	}
+
+	interval := hubBackupIntervalDefault
+	if ramenConfig.HubBackup.Interval.Duration > 0 {
+		interval = ramenConfig.HubBackup.Interval.Duration
+	}
+
+	if ramenConfig.HubBackup.Disabled {
+		return interval
+	}
+
+	statusConfigMap, err := hubBackupStatusGetOrCreate(ctx, c, apiReader)
+	if err != nil {
+		log.Info("Hub backup skipped; status get failed", "error", err.Error())
+
+		return interval
+	}
+
+	_, restored := statusConfigMap.Data[hubBackupRestoredS3ProfileNameKey]
+	hubID := string(statusConfigMap.UID)
+	takeOver := restored || ramenConfig.HubBackup.TakeOver
+
+	hubObjects, s3ProfileNames, err := hubObjectsList(ctx, apiReader)
+	if err != nil {
+		log.Info("Hub backup skipped; list failed", "error", err.Error())
+
+		return interval
+	}
+
+	for _, s3ProfileName := range s3ProfileNames.List() {
+		objectStorer, _, err := objectStoreGetter.ObjectStore(ctx, apiReader, s3ProfileName, "hub backup", log)
+		if err != nil {
+			log.Info("Hub backup skipped; object store get failed", "profile", s3ProfileName, "error", err.Error())
+
+			continue
+		}
+
+		owned, err := HubObjectsBackup(objectStorer, hubObjects, hubID, takeOver)
+		if err != nil {
+			log.Info("Hub backup failed", "profile", s3ProfileName, "error", err.Error())
+
+			continue
+		}
+
+		if !owned {
+			log.Info("Hub backup not pruned; store holds the backup of another hub, not restored by this one",
+				"profile", s3ProfileName)
+		}
+	}
+
+	return interval
+}
+
+// hubObjectsList returns the hub objects and the S3 profiles of the
+// DRPolicies
+func hubObjectsList(ctx context.Context, apiReader client.Reader) (HubObjects, sets.String, error) {
+	drpolicies := ramen.DRPolicyList{}
+	if err := apiReader.List(ctx, &drpolicies); err != nil {
+		return HubObjects{}, nil, fmt.Errorf("drpolicies list: %w", err)
+	}
+
+	drclusters := ramen.DRClusterList{}
+	if err := apiReader.List(ctx, &drclusters); err != nil {
+		return HubObjects{}, nil, fmt.Errorf("drclusters list: %w", err)
+	}
+
+	drpcs := ramen.DRPlacementControlList{}
+	if err := apiReader.List(ctx, &drpcs); err != nil {
+		return HubObjects{}, nil, fmt.Errorf("drpcs list: %w", err)
+	}
+
+	s3ProfileNames := sets.NewString()
+
+	for i := range drpolicies.Items {
+		s3ProfileNames = s3ProfileNames.Union(util.DRPolicyS3Profiles(&drpolicies.Items[i], drclusters.Items))
+	}
+
+	s3ProfileNames.Delete("")
+
+	return HubObjects{
+		DRPolicies:          drpolicies.Items,
+		DRClusters:          drclusters.Items,
+		DRPlacementControls: drpcs.Items,
+	}, s3ProfileNames, nil
+}
+
+// hubObjectMetaTidy returns the metadata of a hub object to back up, without
+// the finalizers, which the hub controllers add again on restore
+func hubObjectMetaTidy(objectMeta *metav1.ObjectMeta) metav1.ObjectMeta {
+	objectMetaTidied := ObjectMetaEmbedded(objectMeta)
+	objectMetaTidied.Finalizers = nil
+
+	return objectMetaTidied
+}
+
+// HubObjectsBackup uploads the given hub objects, with their spec and, for
+// DRPlacementControls, their phase and preferred decision, to the given store.
+// If the given hub owns the store's backup, it then deletes from it the hub
+// objects backed up before that are not given, so that a failed upload leaves
+// the prior backup intact. The hub owns an empty store's backup, and that of
+// another hub if it takes it over; it does not otherwise, lest a new hub erase
+// the backup of a lost one. It returns whether the hub owns the backup.
+func HubObjectsBackup(objectStorer ObjectStorer, hubObjects HubObjects, hubID string, takeOver bool,
+) (bool, error) {
+	keys, err := objectStorer.ListKeys(hubBackupKeyPrefix)
+	if err != nil {
+		return false, fmt.Errorf("list keys: %w", err)
+	}
+
+	owned, err := hubBackupOwned(objectStorer, keys, hubID)
+	if err != nil {
+		return false, err
+	}
+
+	owned = owned || takeOver || len(keys) == 0
+
+	objects := make(map[string]interface{})
+	objectAdd := func(name string, object interface{}) {
+		objects[typedKey(hubBackupKeyPrefix, name, reflect.TypeOf(object))] = object
+	}
+
+	for i := range hubObjects.DRPolicies {
+		drpolicy := &hubObjects.DRPolicies[i]
+		objectAdd(drpolicy.Name,
+			ramen.DRPolicy{ObjectMeta: hubObjectMetaTidy(&drpolicy.ObjectMeta), Spec: drpolicy.Spec})
+	}
+
+	for i := range hubObjects.DRClusters {
+		drcluster := &hubObjects.DRClusters[i]
+		objectAdd(drcluster.Name,
+			ramen.DRCluster{ObjectMeta: hubObjectMetaTidy(&drcluster.ObjectMeta), Spec: drcluster.Spec})
+	}
+
+	for i := range hubObjects.DRPlacementControls {
+		drpc := &hubObjects.DRPlacementControls[i]
+		objectAdd(drpc.Namespace+"/"+drpc.Name, ramen.DRPlacementControl{
+			ObjectMeta: hubObjectMetaTidy(&drpc.ObjectMeta),
+			Spec:       drpc.Spec,
+			Status: ramen.DRPlacementControlStatus{
+				Phase:             drpc.Status.Phase,
+				PreferredDecision: drpc.Status.PreferredDecision,
+			},
+		})
+	}
+
+	for key, object := range objects {
+		if err := objectStorer.UploadObject(key, object); err != nil {
+			return owned, fmt.Errorf("upload %s: %w", key, err)
+		}
+	}
+
+	if !owned {
+		return owned, nil
+	}
+
+	if err := objectStorer.UploadObject(hubBackupOwnerKey, HubBackupOwner{HubID: hubID}); err != nil {
+		return owned, fmt.Errorf("owner upload: %w", err)
+	}
+
+	for _, key := range keys {
+		if _, ok := objects[key]; ok || key == hubBackupOwnerKey {
+			continue
+		}
+
+		if err := objectStorer.DeleteObjects(key); err != nil {
+			return owned, fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+
+	return owned, nil
+}
+
+// hubBackupOwned returns whether the given hub owns the backup whose keys are
+// given
+func hubBackupOwned(objectStorer ObjectStorer, keys []string, hubID string) (bool, error) {
+	for _, key := range keys {
+		if key != hubBackupOwnerKey {
+			continue
+		}
+
+		owner := HubBackupOwner{}
+		if err := objectStorer.DownloadObject(hubBackupOwnerKey, &owner); err != nil {
+			return false, fmt.Errorf("owner download: %w", err)
+		}
+
+		return owner.HubID == hubID, nil
+	}
+
+	return false, nil
+}
+
+// HubObjectsDownload returns the hub objects backed up to the given store
+func HubObjectsDownload(objectStorer ObjectStorer) (HubObjects, error) {
+	hubObjects := HubObjects{}
+
+	if err := DownloadTypedObjects(objectStorer, hubBackupKeyPrefix, &hubObjects.DRPolicies); err != nil {
+		return hubObjects, fmt.Errorf("drpolicies download: %w", err)
+	}
+
+	if err := DownloadTypedObjects(objectStorer, hubBackupKeyPrefix, &hubObjects.DRClusters); err != nil {
+		return hubObjects, fmt.Errorf("drclusters download: %w", err)
+	}
+
+	if err := DownloadTypedObjects(objectStorer, hubBackupKeyPrefix, &hubObjects.DRPlacementControls); err != nil {
+		return hubObjects, fmt.Errorf("drpcs download: %w", err)
+	}
+
+	return hubObjects, nil
+}
+
+// HubObjectsRestore creates the given hub objects that do not exist yet,
+// DRClusters and DRPolicies before the DRPlacementControls that refer to
+// them. The namespace of a DRPlacementControl is created if absent, and its
+// backed up status is created with it in an annotation, which the
+// DRPlacementControl reconciler sets its status from before processing it.
+func HubObjectsRestore(ctx context.Context, c client.Client, hubObjects HubObjects, log logr.Logger) error {
+	for i := range hubObjects.DRClusters {
+		if _, err := hubObjectCreate(ctx, c, &hubObjects.DRClusters[i], log); err != nil {
+			return err
+		}
+	}
+
+	for i := range hubObjects.DRPolicies {
+		if _, err := hubObjectCreate(ctx, c, &hubObjects.DRPolicies[i], log); err != nil {
+			return err
+		}
+	}
+
+	for i := range hubObjects.DRPlacementControls {
+		drpc := &hubObjects.DRPlacementControls[i]
+
+		err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: drpc.Namespace}})
+		if err != nil && !k8serrors.IsAlreadyExists(err) {
+			return fmt.Errorf("namespace %s create: %w", drpc.Namespace, err)
+		}
+
+		restoredStatus, err := json.Marshal(ramen.DRPlacementControlStatus{
+			Phase:             drpc.Status.Phase,
+			PreferredDecision: drpc.Status.PreferredDecision,
+		})
+		if err != nil {
+			return fmt.Errorf("drpc %s/%s status encode: %w", drpc.Namespace, drpc.Name, err)
+		}
+
+		if drpc.Annotations == nil {
+			drpc.Annotations = map[string]string{}
+		}
+
+		drpc.Annotations[DRPCRestoredStatusAnnotation] = string(restoredStatus)
+		drpc.Status = ramen.DRPlacementControlStatus{}
+
+		if _, err := hubObjectCreate(ctx, c, drpc, log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hubObjectCreate returns whether the object is created; not if it exists
+func hubObjectCreate(ctx context.Context, c client.Client, object client.Object, log logr.Logger) (bool, error) {
+	if err := c.Create(ctx, object); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("%T %s/%s create: %w", object, object.GetNamespace(), object.GetName(), err)
+	}
+
+	log.Info("Hub object restored", "type", fmt.Sprintf("%T", object), "namespace", object.GetNamespace(),
+		"name", object.GetName())
+
+	return true, nil
+}
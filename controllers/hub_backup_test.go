@@ -0,0 +1,254 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Hub objects backup", func() {
+	var (
+		rootDir     string
+		objectStore controllers.ObjectStorer
+	)
+
+	drpolicy := ramen.DRPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-drpolicy", Finalizers: []string{"finalizer"}},
+		Spec: ramen.DRPolicySpec{
+			DRClusters:         []string{"backup-east", "backup-west"},
+			SchedulingInterval: "1m",
+		},
+		Status: ramen.DRPolicyStatus{Conditions: []metav1.Condition{{Type: "Validated"}}},
+	}
+	drcluster := ramen.DRCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-east"},
+		Spec:       ramen.DRClusterSpec{S3ProfileName: "backup-s3profile-east"},
+	}
+	drpc := ramen.DRPlacementControl{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backup-app", Name: "backup-drpc"},
+		Spec:       ramen.DRPlacementControlSpec{PreferredCluster: "backup-east"},
+		Status: ramen.DRPlacementControlStatus{
+			Phase:             ramen.Deployed,
+			PreferredDecision: plrv1.PlacementDecision{ClusterName: "backup-east"},
+			Conditions:        []metav1.Condition{{Type: "Available"}},
+		},
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		rootDir, err = os.MkdirTemp("", "ramen-hub-backup-")
+		Expect(err).NotTo(HaveOccurred())
+
+		objectStore = s3ProfileObjectStoreGet(ramen.S3StoreProfile{
+			S3ProfileName:        "fs-hub-backup-s3profile",
+			Type:                 ramen.FileSystemObjectStoreType,
+			S3Bucket:             bucketNameSucc,
+			S3CompatibleEndpoint: "file://" + rootDir,
+		})
+	})
+
+	AfterEach(func() {
+		s3ProfileObjectStoresRemove()
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	const (
+		hubID      = "hub"
+		otherHubID = "other-hub"
+	)
+
+	hubObjectsBackup := func(hubObjects controllers.HubObjects, hubID string, takeOver bool) bool {
+		owned, err := controllers.HubObjectsBackup(objectStore, hubObjects, hubID, takeOver)
+		Expect(err).NotTo(HaveOccurred())
+
+		return owned
+	}
+
+	It("uploads the hub objects' spec and key status and deletes backups of deleted objects", func() {
+		Expect(hubObjectsBackup(controllers.HubObjects{
+			DRPolicies:          []ramen.DRPolicy{drpolicy},
+			DRClusters:          []ramen.DRCluster{drcluster},
+			DRPlacementControls: []ramen.DRPlacementControl{drpc},
+		}, hubID, false)).To(BeTrue())
+
+		hubObjects, err := controllers.HubObjectsDownload(objectStore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hubObjects.DRPolicies).To(HaveLen(1))
+		Expect(hubObjects.DRPolicies[0].Name).To(Equal(drpolicy.Name))
+		Expect(hubObjects.DRPolicies[0].Finalizers).To(BeEmpty())
+		Expect(hubObjects.DRPolicies[0].Spec).To(Equal(drpolicy.Spec))
+		Expect(hubObjects.DRPolicies[0].Status.Conditions).To(BeEmpty())
+		Expect(hubObjects.DRClusters).To(HaveLen(1))
+		Expect(hubObjects.DRClusters[0].Spec).To(Equal(drcluster.Spec))
+		Expect(hubObjects.DRPlacementControls).To(HaveLen(1))
+		Expect(hubObjects.DRPlacementControls[0].Namespace).To(Equal(drpc.Namespace))
+		Expect(hubObjects.DRPlacementControls[0].Spec.PreferredCluster).To(Equal(drpc.Spec.PreferredCluster))
+		Expect(hubObjects.DRPlacementControls[0].Status.Phase).To(Equal(ramen.Deployed))
+		Expect(hubObjects.DRPlacementControls[0].Status.PreferredDecision).To(Equal(drpc.Status.PreferredDecision))
+		Expect(hubObjects.DRPlacementControls[0].Status.Conditions).To(BeEmpty())
+
+		Expect(hubObjectsBackup(controllers.HubObjects{
+			DRPolicies: []ramen.DRPolicy{drpolicy},
+			DRClusters: []ramen.DRCluster{drcluster},
+		}, hubID, false)).To(BeTrue())
+
+		hubObjects, err = controllers.HubObjectsDownload(objectStore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hubObjects.DRPolicies).To(HaveLen(1))
+		Expect(hubObjects.DRClusters).To(HaveLen(1))
+		Expect(hubObjects.DRPlacementControls).To(BeEmpty())
+	})
+
+	It("does not delete the objects of another hub's backup unless it takes the backup over", func() {
+		Expect(hubObjectsBackup(controllers.HubObjects{
+			DRPolicies:          []ramen.DRPolicy{drpolicy},
+			DRPlacementControls: []ramen.DRPlacementControl{drpc},
+		}, otherHubID, false)).To(BeTrue())
+
+		Expect(hubObjectsBackup(controllers.HubObjects{
+			DRClusters: []ramen.DRCluster{drcluster},
+		}, hubID, false)).To(BeFalse())
+
+		hubObjects, err := controllers.HubObjectsDownload(objectStore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hubObjects.DRPolicies).To(HaveLen(1))
+		Expect(hubObjects.DRClusters).To(HaveLen(1))
+		Expect(hubObjects.DRPlacementControls).To(HaveLen(1))
+
+		Expect(hubObjectsBackup(controllers.HubObjects{
+			DRClusters: []ramen.DRCluster{drcluster},
+		}, hubID, true)).To(BeTrue())
+
+		hubObjects, err = controllers.HubObjectsDownload(objectStore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hubObjects.DRPolicies).To(BeEmpty())
+		Expect(hubObjects.DRClusters).To(HaveLen(1))
+		Expect(hubObjects.DRPlacementControls).To(BeEmpty())
+
+		// the hub owns the backup it took over, and the other hub no longer does
+		Expect(hubObjectsBackup(controllers.HubObjects{}, hubID, false)).To(BeTrue())
+		Expect(hubObjectsBackup(controllers.HubObjects{
+			DRPolicies: []ramen.DRPolicy{drpolicy},
+		}, otherHubID, false)).To(BeFalse())
+
+		hubObjects, err = controllers.HubObjectsDownload(objectStore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hubObjects.DRPolicies).To(HaveLen(1))
+		Expect(hubObjects.DRClusters).To(BeEmpty())
+	})
+
+	It("restores a backup once, recording the restore", func() {
+		restoredDRCluster := ramen.DRCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-restored"},
+			Spec:       ramen.DRClusterSpec{S3ProfileName: s3Profiles[0].S3ProfileName},
+		}
+		Expect(hubObjectsBackup(controllers.HubObjects{DRClusters: []ramen.DRCluster{restoredDRCluster}},
+			otherHubID, false)).To(BeTrue())
+		createNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: restoredDRCluster.Name}})
+
+		ramenConfig.HubBackup.RestoreS3ProfileName = "fs-hub-backup-s3profile"
+		ramenConfig.HubBackup.Disabled = true
+		configMapUpdate()
+
+		defer func() {
+			ramenConfig.HubBackup.RestoreS3ProfileName = ""
+			ramenConfig.HubBackup.Disabled = false
+			configMapUpdate()
+		}()
+
+		// hubBackupRun runs the hub backup, which restores first, until the
+		// returned function is called
+		hubBackupRun := func() func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			done := make(chan struct{})
+
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+
+				Expect(controllers.HubBackupRunnable(k8sClient, apiReader, controllers.S3ObjectStoreGetter(),
+					testLogger)(ctx)).To(Succeed())
+			}()
+
+			return func() {
+				cancel()
+				<-done
+			}
+		}
+		drclusterKey := types.NamespacedName{Name: restoredDRCluster.Name}
+		statusKey := types.NamespacedName{Namespace: configMap.Namespace, Name: "ramen-hub-backup-status"}
+		status := &corev1.ConfigMap{}
+
+		stop := hubBackupRun()
+		Eventually(func() error {
+			return apiReader.Get(context.TODO(), drclusterKey, &ramen.DRCluster{})
+		}, timeout, interval).Should(Succeed())
+		Eventually(func() map[string]string {
+			Expect(apiReader.Get(context.TODO(), statusKey, status)).To(Succeed())
+
+			return status.Data
+		}, timeout, interval).Should(HaveKeyWithValue("restoredS3ProfileName", "fs-hub-backup-s3profile"))
+		stop()
+
+		Expect(k8sClient.Delete(context.TODO(), &restoredDRCluster)).To(Succeed())
+		Eventually(func() bool {
+			return k8serrors.IsNotFound(apiReader.Get(context.TODO(), drclusterKey, &ramen.DRCluster{}))
+		}, timeout, interval).Should(BeTrue())
+
+		stop = hubBackupRun()
+		Consistently(func() bool {
+			return k8serrors.IsNotFound(apiReader.Get(context.TODO(), drclusterKey, &ramen.DRCluster{}))
+		}, 2*time.Second, interval).Should(BeTrue())
+		stop()
+
+		Expect(k8sClient.Delete(context.TODO(), status)).To(Succeed())
+	})
+
+	It("restores a DRPC's status through an annotation its reconciler consumes", func() {
+		Expect(controllers.HubObjectsRestore(context.TODO(), k8sClient, controllers.HubObjects{
+			DRPlacementControls: []ramen.DRPlacementControl{*drpc.DeepCopy()},
+		}, testLogger)).To(Succeed())
+
+		restored := &ramen.DRPlacementControl{}
+		namespacedName := types.NamespacedName{Namespace: drpc.Namespace, Name: drpc.Name}
+
+		Eventually(func(g Gomega) {
+			g.Expect(apiReader.Get(context.TODO(), namespacedName, restored)).To(Succeed())
+			g.Expect(restored.Status.Phase).To(Equal(ramen.Deployed))
+			g.Expect(restored.Status.PreferredDecision).To(Equal(drpc.Status.PreferredDecision))
+			g.Expect(restored.GetAnnotations()).NotTo(HaveKey(controllers.DRPCRestoredStatusAnnotation))
+		}, timeout, interval).Should(Succeed())
+
+		Expect(k8sClient.Delete(context.TODO(), restored)).To(Succeed())
+		Eventually(func() bool {
+			return k8serrors.IsNotFound(apiReader.Get(context.TODO(), namespacedName, restored))
+		}, timeout, interval).Should(BeTrue())
+	})
+})
@@ -68,6 +68,104 @@ type KubeObjectsRequestProcessingError struct{ string }
 func (e KubeObjectsRequestProcessingError) Error() string   { return e.string }
 func (KubeObjectsRequestProcessingError) Is(err error) bool { return true }
 
+// KubeObjectsRequestsManager creates, queries and deletes the requests of an
+// engine that captures and recovers kube objects. A request that is still
+// being processed when created returns a KubeObjectsRequestProcessingError,
+// and is resumed by creating it again.
+type KubeObjectsRequestsManager interface {
+	// CapturesPath returns the path, relative to that of a capture, of the
+	// objects uploaded for it
+	CapturesPath() string
+	// ObjectStoreTypeSupported returns whether kube objects can be captured
+	// to and recovered from object stores of the given type
+	ObjectStoreTypeSupported(objectStoreType ramendrv1alpha1.ObjectStoreType) bool
+	// ProtectRequestNew and RecoverRequestNew return requests whose objects,
+	// if not nil, are watched for changes
+	ProtectRequestNew() KubeObjectsProtectRequest
+	RecoverRequestNew() KubeObjectsRecoverRequest
+	ProtectRequestsGet(ctx context.Context, reader client.Reader, requestNamespaceName string,
+		labels map[string]string) (KubeObjectsRequests, error)
+	ProtectRequestsDelete(ctx context.Context, writer client.Writer, requestNamespaceName string,
+		labels map[string]string) error
+	RecoverRequestsDelete(ctx context.Context, writer client.Writer, requestNamespaceName string,
+		labels map[string]string) error
+	ProtectRequestCreate(ctx context.Context, writer client.Writer, reader client.Reader, log logr.Logger,
+		objectStorer ObjectStorer, s3StoreProfile ramendrv1alpha1.S3StoreProfile, s3KeyPrefix string,
+		sourceNamespaceName string, objectsSpec ramendrv1alpha1.KubeObjectsSpec, requestNamespaceName string,
+		captureName string, labels map[string]string) (KubeObjectsProtectRequest, error)
+	RecoverRequestCreate(ctx context.Context, writer client.Writer, reader client.Reader, log logr.Logger,
+		objectStorer ObjectStorer, s3StoreProfile ramendrv1alpha1.S3StoreProfile, s3KeyPrefix string,
+		sourceNamespaceName string, targetNamespaceName string, objectsSpec ramendrv1alpha1.KubeObjectsSpec,
+		requestNamespaceName string, captureName string, recoverName string, labels map[string]string,
+	) (KubeObjectsRecoverRequest, error)
+}
+
+// veleroRequestsManager captures and recovers kube objects with Velero backups
+// and restores
+type veleroRequestsManager struct{}
+
+func (veleroRequestsManager) CapturesPath() string { return KubeObjectsCapturesPath }
+
+func (veleroRequestsManager) ObjectStoreTypeSupported(objectStoreType ramendrv1alpha1.ObjectStoreType) bool {
+	return objectStoreType == ramendrv1alpha1.S3ObjectStoreType
+}
+
+func (veleroRequestsManager) ProtectRequestNew() KubeObjectsProtectRequest {
+	return KubeObjectsCaptureRequestNew()
+}
+
+func (veleroRequestsManager) RecoverRequestNew() KubeObjectsRecoverRequest {
+	return KubeObjectsRecoverRequestNew()
+}
+
+func (veleroRequestsManager) ProtectRequestsGet(ctx context.Context, reader client.Reader,
+	requestNamespaceName string, labels map[string]string,
+) (KubeObjectsRequests, error) {
+	return KubeObjectsCaptureRequestsGet(ctx, reader, requestNamespaceName, labels)
+}
+
+func (veleroRequestsManager) ProtectRequestsDelete(ctx context.Context, writer client.Writer,
+	requestNamespaceName string, labels map[string]string,
+) error {
+	return KubeObjectsCaptureRequestsDelete(ctx, writer, requestNamespaceName, labels)
+}
+
+func (veleroRequestsManager) RecoverRequestsDelete(ctx context.Context, writer client.Writer,
+	requestNamespaceName string, labels map[string]string,
+) error {
+	return KubeObjectsRecoverRequestsDelete(ctx, writer, requestNamespaceName, labels)
+}
+
+func (veleroRequestsManager) ProtectRequestCreate(ctx context.Context, writer client.Writer,
+	reader client.Reader, log logr.Logger, objectStorer ObjectStorer, s3StoreProfile ramendrv1alpha1.S3StoreProfile,
+	s3KeyPrefix string, sourceNamespaceName string, objectsSpec ramendrv1alpha1.KubeObjectsSpec,
+	requestNamespaceName string, captureName string, labels map[string]string,
+) (KubeObjectsProtectRequest, error) {
+	return KubeObjectsProtect(ctx, writer, reader, log,
+		s3StoreProfile.S3CompatibleEndpoint,
+		s3StoreProfile.S3Bucket,
+		s3StoreProfile.S3Region,
+		s3KeyPrefix,
+		s3StoreProfile.VeleroNamespaceSecretName,
+		sourceNamespaceName, objectsSpec, requestNamespaceName, captureName, labels)
+}
+
+func (veleroRequestsManager) RecoverRequestCreate(ctx context.Context, writer client.Writer,
+	reader client.Reader, log logr.Logger, objectStorer ObjectStorer, s3StoreProfile ramendrv1alpha1.S3StoreProfile,
+	s3KeyPrefix string, sourceNamespaceName string, targetNamespaceName string,
+	objectsSpec ramendrv1alpha1.KubeObjectsSpec, requestNamespaceName string, captureName string,
+	recoverName string, labels map[string]string,
+) (KubeObjectsRecoverRequest, error) {
+	return KubeObjectsRecover(ctx, writer, reader, log,
+		s3StoreProfile.S3CompatibleEndpoint,
+		s3StoreProfile.S3Bucket,
+		s3StoreProfile.S3Region,
+		s3KeyPrefix,
+		s3StoreProfile.VeleroNamespaceSecretName,
+		sourceNamespaceName, targetNamespaceName, objectsSpec, requestNamespaceName,
+		captureName, recoverName, labels)
+}
+
 type (
 	veleroBackupRequest  struct{ backup *velero.Backup }
 	veleroRestoreRequest struct{ restore *velero.Restore }
@@ -0,0 +1,423 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +kubebuilder:rbac:groups="",resources=configmaps;secrets;serviceaccounts;services,verbs=get;list;create
+// +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;replicasets;statefulsets,verbs=get;list;create
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;create
+// +kubebuilder:rbac:groups=batch,resources=cronjobs;jobs,verbs=get;list;create
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses;networkpolicies,verbs=get;list;create
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;create
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;create
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The native kube objects engine captures the objects of a capture group by
+// listing them with the API server and uploading each to the object store at
+// "<capture path>native/<capture name>/<resource>.<group>/<object name>". It
+// recovers a recover group by downloading the objects of the capture group it
+// names and creating those that it selects, skipping those that exist already.
+// Requests are processed synchronously, so there are none in progress to
+// resume, watch or delete.
+
+const KubeObjectsNativeCapturesPath = "native/"
+
+// nativeKubeObjectsExcludedResources are never captured nor recovered: the
+// VRG protects PVs and PVCs itself, Ramen creates its own resources on
+// recovery, and events and endpoints are generated by the cluster
+var nativeKubeObjectsExcludedResources = []string{
+	"events",
+	"endpoints",
+	"endpointslices.discovery.k8s.io",
+	"persistentvolumeclaims",
+	"persistentvolumes",
+	"volumereplicationgroups.ramendr.openshift.io",
+	"volumereplications.replication.storage.openshift.io",
+}
+
+// nativeKubeObjectsResources are the only resources captured and recovered,
+// since Ramen is granted access to these only, by the RBAC rules above. Secrets
+// are captured only to object stores that encrypt them.
+var nativeKubeObjectsResources = []string{
+	"configmaps",
+	"secrets",
+	"serviceaccounts",
+	"services",
+	"daemonsets.apps",
+	"deployments.apps",
+	"replicasets.apps",
+	"statefulsets.apps",
+	"horizontalpodautoscalers.autoscaling",
+	"cronjobs.batch",
+	"jobs.batch",
+	"ingresses.networking.k8s.io",
+	"networkpolicies.networking.k8s.io",
+	"poddisruptionbudgets.policy",
+	"routes.route.openshift.io",
+}
+
+const nativeKubeObjectsSecretsResource = "secrets"
+
+type nativeRequest struct{ startTime, endTime metav1.Time }
+
+func (nativeRequest) Object() client.Object    { return nil }
+func (r nativeRequest) StartTime() metav1.Time { return r.startTime }
+func (r nativeRequest) EndTime() metav1.Time   { return r.endTime }
+
+func (nativeRequest) Deallocate(context.Context, client.Writer, logr.Logger) error { return nil }
+
+type nativeRequests struct{}
+
+func (nativeRequests) Count() int                   { return 0 }
+func (nativeRequests) Get(i int) KubeObjectsRequest { return nil }
+
+type nativeRequestsManager struct {
+	discovery discovery.DiscoveryInterface
+}
+
+// KubeObjectsNativeRequestsManagerNew returns the requests manager of the
+// native kube objects engine, which discovers the resources to capture with
+// the given discovery client
+func KubeObjectsNativeRequestsManagerNew(discoveryClient discovery.DiscoveryInterface) KubeObjectsRequestsManager {
+	return nativeRequestsManager{discovery: discoveryClient}
+}
+
+func (nativeRequestsManager) CapturesPath() string { return KubeObjectsNativeCapturesPath }
+
+func (nativeRequestsManager) ObjectStoreTypeSupported(ramendrv1alpha1.ObjectStoreType) bool {
+	return true
+}
+
+func (nativeRequestsManager) ProtectRequestNew() KubeObjectsProtectRequest { return nativeRequest{} }
+func (nativeRequestsManager) RecoverRequestNew() KubeObjectsRecoverRequest { return nativeRequest{} }
+
+func (nativeRequestsManager) ProtectRequestsGet(context.Context, client.Reader, string, map[string]string,
+) (KubeObjectsRequests, error) {
+	return nativeRequests{}, nil
+}
+
+func (nativeRequestsManager) ProtectRequestsDelete(context.Context, client.Writer, string, map[string]string,
+) error {
+	return nil
+}
+
+func (nativeRequestsManager) RecoverRequestsDelete(context.Context, client.Writer, string, map[string]string,
+) error {
+	return nil
+}
+
+func nativeKubeObjectsCaptureKeyPrefix(s3KeyPrefix, captureName string) string {
+	return s3KeyPrefix + KubeObjectsNativeCapturesPath + captureName + "/"
+}
+
+func (m nativeRequestsManager) ProtectRequestCreate(ctx context.Context, writer client.Writer,
+	reader client.Reader, log logr.Logger, objectStorer ObjectStorer, s3StoreProfile ramendrv1alpha1.S3StoreProfile,
+	s3KeyPrefix string, sourceNamespaceName string, objectsSpec ramendrv1alpha1.KubeObjectsSpec,
+	requestNamespaceName string, captureName string, labels map[string]string,
+) (KubeObjectsProtectRequest, error) {
+	startTime := metav1.Now()
+	keyPrefix := nativeKubeObjectsCaptureKeyPrefix(s3KeyPrefix, captureName)
+
+	selector, err := nativeKubeObjectsSelector(objectsSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := m.resourcesGet(objectsSpec, s3StoreProfile.Encryption.KeySecretRef.Name != "", log)
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+
+	for _, resource := range resources {
+		objects, err := nativeKubeObjectsList(ctx, reader, resource, sourceNamespaceName, selector)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range objects {
+			object := &objects[i]
+
+			// objects with a controller are recreated by it
+			if metav1.GetControllerOf(object) != nil || object.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			key := keyPrefix + resource.groupResource().String() + "/" + object.GetName()
+			if err := objectStorer.UploadObject(key, object); err != nil {
+				return nil, fmt.Errorf("kube object %s upload failed, %w", key, err)
+			}
+
+			count++
+		}
+	}
+
+	log.Info("Kube objects captured", "capture", captureName, "resources", len(resources), "objects", count)
+
+	return nativeRequest{startTime: startTime, endTime: metav1.Now()}, nil
+}
+
+func (nativeRequestsManager) RecoverRequestCreate(ctx context.Context, writer client.Writer,
+	reader client.Reader, log logr.Logger, objectStorer ObjectStorer, s3StoreProfile ramendrv1alpha1.S3StoreProfile,
+	s3KeyPrefix string, sourceNamespaceName string, targetNamespaceName string,
+	objectsSpec ramendrv1alpha1.KubeObjectsSpec, requestNamespaceName string, captureName string,
+	recoverName string, labels map[string]string,
+) (KubeObjectsRecoverRequest, error) {
+	startTime := metav1.Now()
+	keyPrefix := nativeKubeObjectsCaptureKeyPrefix(s3KeyPrefix, captureName)
+
+	selector, err := nativeKubeObjectsSelector(objectsSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := objectStorer.ListKeys(keyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("kube objects of capture %s list failed, %w", captureName, err)
+	}
+
+	createdCount, existingCount := 0, 0
+
+	for _, key := range keys {
+		groupResource := schema.ParseGroupResource(strings.SplitN(strings.TrimPrefix(key, keyPrefix), "/", 2)[0])
+
+		object := &unstructured.Unstructured{}
+		if err := objectStorer.DownloadObject(key, object); err != nil {
+			return nil, fmt.Errorf("kube object %s download failed, %w", key, err)
+		}
+
+		if !nativeKubeObjectSelected(objectsSpec, selector, groupResource, object) {
+			continue
+		}
+
+		nativeKubeObjectCreatePrepare(object, targetNamespaceName)
+
+		if err := writer.Create(ctx, object); err != nil {
+			if !k8serrors.IsAlreadyExists(err) {
+				return nil, fmt.Errorf("kube object %s %s create failed, %w", groupResource, object.GetName(), err)
+			}
+
+			existingCount++
+
+			continue
+		}
+
+		createdCount++
+	}
+
+	log.Info("Kube objects recovered", "capture", captureName, "recover", recoverName,
+		"created", createdCount, "existing", existingCount)
+
+	return nativeRequest{startTime: startTime, endTime: metav1.Now()}, nil
+}
+
+type nativeKubeObjectsResource struct {
+	groupVersion schema.GroupVersion
+	apiResource  metav1.APIResource
+}
+
+func (r nativeKubeObjectsResource) groupResource() schema.GroupResource {
+	return r.groupVersion.WithResource(r.apiResource.Name).GroupResource()
+}
+
+// resourcesGet returns the preferred version of each resource selected by the
+// given spec that can be listed and created, and that Ramen may capture.
+// Secrets are skipped unless the object store encrypts them.
+func (m nativeRequestsManager) resourcesGet(objectsSpec ramendrv1alpha1.KubeObjectsSpec, encrypted bool,
+	log logr.Logger,
+) ([]nativeKubeObjectsResource, error) {
+	resourceLists, err := m.discovery.ServerPreferredResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, fmt.Errorf("kube objects resources discovery failed, %w", err)
+		}
+
+		// an unavailable aggregated API should not prevent the capture of others
+		log.Info("Kube objects resources discovery incomplete", "error", err.Error())
+	}
+
+	resources := []nativeKubeObjectsResource{}
+	includeClusterResources := objectsSpec.IncludeClusterResources != nil && *objectsSpec.IncludeClusterResources
+
+	for _, resourceList := range discovery.FilteredBy(
+		discovery.SupportsAllVerbs{Verbs: []string{"create", "get", "list"}}, resourceLists,
+	) {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, apiResource := range resourceList.APIResources {
+			resource := nativeKubeObjectsResource{groupVersion: groupVersion, apiResource: apiResource}
+
+			if strings.Contains(apiResource.Name, "/") ||
+				(!apiResource.Namespaced && !includeClusterResources) ||
+				!nativeKubeObjectsResourceSelected(objectsSpec, resource.groupResource(), apiResource.Kind) {
+				continue
+			}
+
+			if resource.groupResource().String() == nativeKubeObjectsSecretsResource && !encrypted {
+				log.Info("Kube objects secrets not captured, as the object store does not encrypt them")
+
+				continue
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+func nativeKubeObjectsList(ctx context.Context, reader client.Reader, resource nativeKubeObjectsResource,
+	namespaceName string, selector k8slabels.Selector,
+) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(resource.groupVersion.WithKind(resource.apiResource.Kind + "List"))
+
+	options := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}}
+	if resource.apiResource.Namespaced {
+		options = append(options, client.InNamespace(namespaceName))
+	}
+
+	if err := reader.List(ctx, list, options...); err != nil {
+		return nil, fmt.Errorf("kube objects %s list failed, %w", resource.groupResource(), err)
+	}
+
+	return list.Items, nil
+}
+
+func nativeKubeObjectsSelector(objectsSpec ramendrv1alpha1.KubeObjectsSpec) (k8slabels.Selector, error) {
+	if objectsSpec.LabelSelector == nil {
+		return k8slabels.Everything(), nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(objectsSpec.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("kube objects label selector invalid, %w", err)
+	}
+
+	return selector, nil
+}
+
+// nativeKubeObjectSelected returns whether the given object is selected for
+// recovery by the given spec and its label selector
+func nativeKubeObjectSelected(objectsSpec ramendrv1alpha1.KubeObjectsSpec, selector k8slabels.Selector,
+	groupResource schema.GroupResource, object *unstructured.Unstructured,
+) bool {
+	if object.GetNamespace() == "" &&
+		(objectsSpec.IncludeClusterResources == nil || !*objectsSpec.IncludeClusterResources) {
+		return false
+	}
+
+	return nativeKubeObjectsResourceSelected(objectsSpec, groupResource, object.GetKind()) &&
+		selector.Matches(k8slabels.Set(object.GetLabels()))
+}
+
+func nativeKubeObjectsResourceSelected(objectsSpec ramendrv1alpha1.KubeObjectsSpec,
+	groupResource schema.GroupResource, kind string,
+) bool {
+	if !nativeKubeObjectsResourceAllowed(groupResource) ||
+		nativeKubeObjectsResourceNamed(nativeKubeObjectsExcludedResources, groupResource, kind) ||
+		nativeKubeObjectsResourceNamed(objectsSpec.ExcludedResources, groupResource, kind) {
+		return false
+	}
+
+	return len(objectsSpec.IncludedResources) == 0 ||
+		nativeKubeObjectsResourceNamed(objectsSpec.IncludedResources, groupResource, kind)
+}
+
+// nativeKubeObjectsResourceAllowed returns whether the given resource is one
+// of those that Ramen may capture and recover
+func nativeKubeObjectsResourceAllowed(groupResource schema.GroupResource) bool {
+	for _, name := range nativeKubeObjectsResources {
+		if name == groupResource.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nativeKubeObjectsResourceNamed returns whether any of the given names is "*"
+// or names the given resource by its resource name or kind, case insensitively,
+// optionally qualified by its group, as in "deployments.apps" or "Deployment"
+func nativeKubeObjectsResourceNamed(names []string, groupResource schema.GroupResource, kind string) bool {
+	for _, name := range names {
+		if name == "*" {
+			return true
+		}
+
+		resourceName := name
+
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			if name[i+1:] != groupResource.Group {
+				continue
+			}
+
+			resourceName = name[:i]
+		}
+
+		if strings.EqualFold(resourceName, groupResource.Resource) || strings.EqualFold(resourceName, kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nativeKubeObjectCreatePrepare clears the fields of the given captured object
+// that are set by the API server of the cluster it was captured from, and moves
+// it to the given namespace if it is namespaced
+func nativeKubeObjectCreatePrepare(object *unstructured.Unstructured, namespaceName string) {
+	if object.GetNamespace() != "" {
+		object.SetNamespace(namespaceName)
+	}
+
+	object.SetResourceVersion("")
+	object.SetUID("")
+	object.SetSelfLink("")
+	object.SetGeneration(0)
+	object.SetCreationTimestamp(metav1.Time{})
+	object.SetManagedFields(nil)
+	// owners, if any, are recreated with different UIDs
+	object.SetOwnerReferences(nil)
+	unstructured.RemoveNestedField(object.Object, "status")
+
+	if object.GetAPIVersion() == "v1" && object.GetKind() == "Service" {
+		// cluster IPs are allocated by each cluster
+		if clusterIP, _, _ := unstructured.NestedString(object.Object, "spec", "clusterIP"); clusterIP != "None" {
+			unstructured.RemoveNestedField(object.Object, "spec", "clusterIP")
+			unstructured.RemoveNestedField(object.Object, "spec", "clusterIPs")
+		}
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Native kube objects engine", func() {
+	const (
+		sourceNamespaceName = "native-kube-objects-source"
+		targetNamespaceName = "native-kube-objects-target"
+		keyPrefix           = sourceNamespaceName + "/vrg/kube-objects/0/"
+		captureName         = "capture"
+	)
+
+	var (
+		rootDir     string
+		fsProfile   ramen.S3StoreProfile
+		objectStore controllers.ObjectStorer
+		kubeObjects controllers.KubeObjectsRequestsManager
+	)
+
+	objectExists := func(object client.Object, namespaceName, name string) bool {
+		err := apiReader.Get(context.TODO(), types.NamespacedName{Namespace: namespaceName, Name: name}, object)
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+
+		Expect(err).NotTo(HaveOccurred())
+
+		return true
+	}
+
+	namespacesCreate := func() {
+		for _, namespaceName := range []string{sourceNamespaceName, targetNamespaceName} {
+			err := k8sClient.Create(context.TODO(),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName}})
+			if !k8serrors.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+	}
+
+	objectsCapture := func(objectsSpec ramen.KubeObjectsSpec) {
+		request, err := kubeObjects.ProtectRequestCreate(context.TODO(), k8sClient, apiReader, testLogger,
+			objectStore, fsProfile, keyPrefix, sourceNamespaceName, objectsSpec, "", captureName, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(request.EndTime().Time).NotTo(BeTemporally("<", request.StartTime().Time))
+	}
+
+	objectsRecover := func(objectsSpec ramen.KubeObjectsSpec) {
+		_, err := kubeObjects.RecoverRequestCreate(context.TODO(), k8sClient, apiReader, testLogger,
+			objectStore, fsProfile, keyPrefix, sourceNamespaceName, targetNamespaceName, objectsSpec,
+			"", captureName, "recover", nil)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		rootDir, err = os.MkdirTemp("", "ramen-kube-objects-")
+		Expect(err).NotTo(HaveOccurred())
+
+		fsProfile = ramen.S3StoreProfile{
+			S3ProfileName:        "fs-kube-objects-s3profile",
+			Type:                 ramen.FileSystemObjectStoreType,
+			S3Bucket:             bucketNameSucc,
+			S3CompatibleEndpoint: "file://" + rootDir,
+		}
+		objectStore = s3ProfileObjectStoreGet(fsProfile)
+		kubeObjects = controllers.KubeObjectsNativeRequestsManagerNew(discovery.NewDiscoveryClientForConfigOrDie(cfg))
+	})
+
+	AfterEach(func() {
+		s3ProfileObjectStoresRemove()
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	It("captures the selected objects and recovers them in another namespace", func() {
+		namespacesCreate()
+
+		labels := map[string]string{"app": "native"}
+		for _, object := range []client.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: sourceNamespaceName, Name: "labeled", Labels: labels},
+				Data:       map[string]string{"key": "value"},
+			},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sourceNamespaceName, Name: "unlabeled"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: sourceNamespaceName, Name: "secret"}},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: sourceNamespaceName, Name: "service"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+			},
+		} {
+			Expect(k8sClient.Create(context.TODO(), object)).To(Succeed())
+		}
+
+		objectsCapture(ramen.KubeObjectsSpec{IncludedResources: []string{"ConfigMap", "secrets"}})
+
+		objectsRecover(ramen.KubeObjectsSpec{LabelSelector: &metav1.LabelSelector{MatchLabels: labels}})
+		configMap := &corev1.ConfigMap{}
+		Expect(objectExists(configMap, targetNamespaceName, "labeled")).To(BeTrue())
+		Expect(configMap.Data).To(Equal(map[string]string{"key": "value"}))
+		Expect(objectExists(&corev1.ConfigMap{}, targetNamespaceName, "unlabeled")).To(BeFalse())
+		Expect(objectExists(&corev1.Secret{}, targetNamespaceName, "secret")).To(BeFalse())
+
+		// secrets are not captured to an object store that does not encrypt them
+		objectsRecover(ramen.KubeObjectsSpec{ExcludedResources: []string{"configmaps"}})
+		Expect(objectExists(&corev1.Secret{}, targetNamespaceName, "secret")).To(BeFalse())
+		Expect(objectExists(&corev1.ConfigMap{}, targetNamespaceName, "unlabeled")).To(BeFalse())
+		Expect(objectExists(&corev1.Service{}, targetNamespaceName, "service")).To(BeFalse())
+
+		// objects that exist already are skipped
+		objectsRecover(ramen.KubeObjectsSpec{})
+		Expect(objectExists(&corev1.ConfigMap{}, targetNamespaceName, "unlabeled")).To(BeTrue())
+	})
+
+	It("captures secrets to an object store that encrypts them", func() {
+		namespacesCreate()
+
+		keySecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: configMap.Namespace, Name: "native-encryption-keys"},
+			Data:       map[string][]byte{"key1": bytes.Repeat([]byte{1}, 32)},
+		}
+		Expect(k8sClient.Create(context.TODO(), keySecret)).To(Succeed())
+		defer func() { Expect(k8sClient.Delete(context.TODO(), keySecret)).To(Succeed()) }()
+
+		fsProfile.Encryption = ramen.ObjectEncryption{
+			KeySecretRef: corev1.SecretReference{Name: keySecret.Name, Namespace: keySecret.Namespace},
+			KeyID:        "key1",
+		}
+		objectStore = s3ProfileObjectStoreGet(fsProfile)
+
+		Expect(k8sClient.Create(context.TODO(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: sourceNamespaceName, Name: "encrypted-secret"},
+		})).To(Succeed())
+
+		objectsCapture(ramen.KubeObjectsSpec{IncludedResources: []string{"secrets"}})
+		objectsRecover(ramen.KubeObjectsSpec{})
+		Expect(objectExists(&corev1.Secret{}, targetNamespaceName, "encrypted-secret")).To(BeTrue())
+	})
+})
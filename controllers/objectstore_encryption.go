@@ -0,0 +1,236 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// encryptedObjectMagic prefixes the data of encrypted objects, to tell them
+// apart from the gzipped data of objects uploaded without encryption
+const encryptedObjectMagic = "ramen-aes-gcm-v1\n"
+
+// encryptionKeyIDMetadataName is the name of the metadata, of objects in s3
+// stores, with the ID of the key that encrypted their data key
+const encryptionKeyIDMetadataName = "Ramen-Encryption-Key-Id"
+
+// dataKeyLength is the length of the AES-256 key generated for each object
+const dataKeyLength = 32
+
+// encryptedObject is the envelope, following encryptedObjectMagic, of the data
+// of an encrypted object. The data is encrypted with a random data key, which
+// is encrypted with the key named KeyID. Each ciphertext is prefixed with its
+// nonce.
+type encryptedObject struct {
+	KeyID            string `json:"keyID"`
+	EncryptedDataKey []byte `json:"encryptedDataKey"`
+	EncryptedData    []byte `json:"encryptedData"`
+}
+
+// objectEncrypter encrypts objects with the key of keyID and decrypts them
+// with any of the keys in keys. It rejects unencrypted objects unless
+// allowUnencrypted.
+type objectEncrypter struct {
+	keyID            string
+	keys             map[string][]byte
+	allowUnencrypted bool
+}
+
+// newObjectEncrypter returns an encrypter with the keys of the encryption key
+// secret of the given s3 profile, or nil if the profile has no such secret
+func newObjectEncrypter(ctx context.Context, r client.Reader, s3StoreProfile ramen.S3StoreProfile,
+) (*objectEncrypter, error) {
+	encryption := s3StoreProfile.Encryption
+	if encryption.KeySecretRef.Name == "" {
+		return nil, nil
+	}
+
+	keys, err := getEncryptionKeys(ctx, r, encryption.KeySecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := keys[encryption.KeyID]; !ok {
+		return nil, fmt.Errorf("key %q not found in encryption key secret %v",
+			encryption.KeyID, encryption.KeySecretRef)
+	}
+
+	for keyID, key := range keys {
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, fmt.Errorf("invalid key %q in encryption key secret %v, %w",
+				keyID, encryption.KeySecretRef, err)
+		}
+	}
+
+	return &objectEncrypter{
+		keyID:            encryption.KeyID,
+		keys:             keys,
+		allowUnencrypted: encryption.AllowUnencryptedObjects,
+	}, nil
+}
+
+func getEncryptionKeys(ctx context.Context, r client.Reader, secretRef corev1.SecretReference,
+) (map[string][]byte, error) {
+	secret := corev1.Secret{}
+	namespacedName := types.NamespacedName{Namespace: secretRef.Namespace, Name: secretRef.Name}
+
+	if namespacedName.Namespace == "" {
+		namespacedName.Namespace = NamespaceName()
+	}
+
+	if err := r.Get(ctx, namespacedName, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get encryption key secret %v, %w", secretRef, err)
+	}
+
+	return secret.Data, nil
+}
+
+// encrypt returns the envelope of the given data encrypted with a new data key
+func (e *objectEncrypter) encrypt(data []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeyLength)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key, %w", err)
+	}
+
+	encryptedDataKey, err := aesGCMSeal(e.keys[e.keyID], dataKey, []byte(e.keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data key with key %q, %w", e.keyID, err)
+	}
+
+	encryptedData, err := aesGCMSeal(dataKey, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data, %w", err)
+	}
+
+	envelope, err := json.Marshal(encryptedObject{
+		KeyID:            e.keyID,
+		EncryptedDataKey: encryptedDataKey,
+		EncryptedData:    encryptedData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(encryptedObjectMagic), envelope...), nil
+}
+
+// decrypt returns the data of the given envelope, decrypted with the key
+// whose ID is in the envelope
+func (e *objectEncrypter) decrypt(data []byte) ([]byte, error) {
+	envelope := encryptedObject{}
+	if err := json.Unmarshal(data[len(encryptedObjectMagic):], &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode encryption envelope, %w", err)
+	}
+
+	keyEncryptionKey, ok := e.keys[envelope.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption key %q not found", envelope.KeyID)
+	}
+
+	dataKey, err := aesGCMOpen(keyEncryptionKey, envelope.EncryptedDataKey, []byte(envelope.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key with key %q, %w", envelope.KeyID, err)
+	}
+
+	plaintext, err := aesGCMOpen(dataKey, envelope.EncryptedData, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data, %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func aesGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func aesGCMSeal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := aesGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func aesGCMOpen(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := aesGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+// encryptObjectData returns the given object data, encrypted if the given
+// encrypter is not nil
+func encryptObjectData(e *objectEncrypter, data []byte) ([]byte, error) {
+	if e == nil {
+		return data, nil
+	}
+
+	return e.encrypt(data)
+}
+
+// decryptObjectData returns the given object data, decrypted if it is
+// encrypted. Unencrypted objects are returned as is if no encrypter is given,
+// or if it allows them, as while migrating objects uploaded before encryption
+// was enabled.
+func decryptObjectData(e *objectEncrypter, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(encryptedObjectMagic)) {
+		if e != nil && !e.allowUnencrypted {
+			return nil, fmt.Errorf("object is not encrypted, but encryption is configured and " +
+				"unencrypted objects are not allowed")
+		}
+
+		return data, nil
+	}
+
+	if e == nil {
+		return nil, fmt.Errorf("object is encrypted, but no encryption key secret is configured")
+	}
+
+	return e.decrypt(data)
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	errorswrapper "github.com/pkg/errors"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// fileSystemObjectStore stores each object as a file, named after its key, in
+// a bucket directory under a root directory. Keys are slash separated paths
+// relative to the bucket directory. Each file holds a line with the json
+// encoded metadata of the object, followed by its data.
+type fileSystemObjectStore struct {
+	bucketDir string
+	bucket    string
+	callerTag string
+	name      string
+	encrypter *objectEncrypter
+	verifier  objectVerifier
+}
+
+// newFileSystemObjectStore returns an object store in the directory of the
+// file:// URL of the given s3 profile's endpoint
+func newFileSystemObjectStore(s3StoreProfile ramen.S3StoreProfile, callerTag string,
+	encrypter *objectEncrypter, verifier objectVerifier,
+) (ObjectStorer, error) {
+	rootDir, err := fileSystemObjectStoreRootDir(s3StoreProfile.S3CompatibleEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint for profile %s for caller %s, %w",
+			s3StoreProfile.S3ProfileName, callerTag, err)
+	}
+
+	return &fileSystemObjectStore{
+		bucketDir: filepath.Join(rootDir, s3StoreProfile.S3Bucket),
+		bucket:    s3StoreProfile.S3Bucket,
+		callerTag: callerTag,
+		name:      s3StoreProfile.S3ProfileName,
+		encrypter: encrypter,
+		verifier:  verifier,
+	}, nil
+}
+
+func fileSystemObjectStoreRootDir(endpoint string) (string, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	if endpointURL.Scheme != "file" || !filepath.IsAbs(endpointURL.Path) {
+		return "", fmt.Errorf("endpoint %s is not a file URL with an absolute path", endpoint)
+	}
+
+	return filepath.Clean(endpointURL.Path), nil
+}
+
+// keyPath returns the path of the file of the object with the given key, and
+// fails for keys that would resolve outside of the bucket directory
+func (s *fileSystemObjectStore) keyPath(key string) (string, error) {
+	cleanKey := path.Clean("/" + key)
+	if key == "" || strings.HasSuffix(key, "/") || cleanKey == "/" {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+
+	return filepath.Join(s.bucketDir, filepath.FromSlash(cleanKey)), nil
+}
+
+// UploadObject writes the encoded object to a temporary file first and renames
+// it to its key's file, so that a partially written object is never read
+func (s *fileSystemObjectStore) UploadObject(key string, object interface{}) error {
+	filePath, err := s.keyPath(key)
+	if err != nil {
+		return fmt.Errorf("failed to upload data of %s:%s, %w", s.bucket, key, err)
+	}
+
+	encodedObject, metadata, err := encodeObject(s.encrypter, s.verifier, s.bucket, key, object)
+	if err != nil {
+		return err
+	}
+
+	encodedMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to json encode metadata of %s:%s, %w", s.bucket, key, err)
+	}
+
+	const dirMode, fileMode = 0o750, 0o640
+
+	if err := os.MkdirAll(filepath.Dir(filePath), dirMode); err != nil {
+		return fmt.Errorf("failed to create directory for %s:%s, %w", s.bucket, key, err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), ".upload-")
+	if err != nil {
+		return fmt.Errorf("failed to create file for %s:%s, %w", s.bucket, key, err)
+	}
+
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(append(append(encodedMetadata, '\n'), encodedObject.Bytes()...)); err != nil {
+		tempFile.Close()
+
+		return fmt.Errorf("failed to write data of %s:%s, %w", s.bucket, key, err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to close file of %s:%s, %w", s.bucket, key, err)
+	}
+
+	if err := os.Chmod(tempFile.Name(), fileMode); err != nil {
+		return fmt.Errorf("failed to set mode of file of %s:%s, %w", s.bucket, key, err)
+	}
+
+	if err := os.Rename(tempFile.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to upload data of %s:%s, %w", s.bucket, key, err)
+	}
+
+	return nil
+}
+
+func (s *fileSystemObjectStore) DownloadObject(key string, objectPointer interface{}) error {
+	filePath, err := s.keyPath(key)
+	if err != nil {
+		return fmt.Errorf("failed to download data of %s:%s, %w", s.bucket, key, err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to download data of %s:%s, %w", s.bucket, key, err)
+	}
+
+	metadataEnd := bytes.IndexByte(data, '\n')
+	if metadataEnd < 0 {
+		return ObjectCorruptedError{s.bucket, key, "metadata not found"}
+	}
+
+	encodedMetadata, data := data[:metadataEnd], data[metadataEnd+1:]
+
+	metadata := map[string]string{}
+	if err := json.Unmarshal(encodedMetadata, &metadata); err != nil {
+		return ObjectCorruptedError{s.bucket, key, fmt.Sprintf("failed to json decode metadata, %v", err)}
+	}
+
+	return decodeObject(s, s.encrypter, s.verifier, s.bucket, key, data, metadata, objectPointer)
+}
+
+// ListKeys lists the keys of the files, other than temporary upload files, in
+// the bucket directory that have the given key prefix. An absent bucket
+// directory has no keys.
+func (s *fileSystemObjectStore) ListKeys(keyPrefix string) ([]string, error) {
+	keys := []string{}
+
+	// Walk only the deepest directory that contains all keys with the prefix
+	walkDir := s.bucketDir
+	if prefixDir := path.Dir(keyPrefix + "x"); prefixDir != "." {
+		walkDir = filepath.Join(s.bucketDir, filepath.FromSlash(path.Clean("/"+prefixDir)))
+	}
+
+	err := filepath.WalkDir(walkDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(s.bucketDir, filePath)
+		if err != nil {
+			return err
+		}
+
+		if key := filepath.ToSlash(relativePath); strings.HasPrefix(key, keyPrefix) {
+			keys = append(keys, key)
+		}
+
+		return nil
+	})
+	if err != nil && !errorswrapper.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list objects in bucket %s:%s, %w", s.bucket, keyPrefix, err)
+	}
+
+	return keys, nil
+}
+
+// ListKeyPrefixes lists the directories, in the directory of the given key
+// prefix, whose names have the rest of the key prefix and that hold a file
+// other than a temporary upload file, since a directory whose files were
+// deleted has no keys
+func (s *fileSystemObjectStore) ListKeyPrefixes(keyPrefix, startAfter string, maxCount int) ([]string, error) {
+	prefixDir, namePrefix := path.Split(keyPrefix)
+
+	dirPath := s.bucketDir
+	if prefixDir != "" {
+		dirPath = filepath.Join(s.bucketDir, filepath.FromSlash(path.Clean("/"+prefixDir)))
+	}
+
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		if errorswrapper.Is(err, fs.ErrNotExist) {
+			return []string{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to list object prefixes in bucket %s:%s, %w", s.bucket, keyPrefix, err)
+	}
+
+	prefixes := []string{}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), namePrefix) {
+			continue
+		}
+
+		prefix := prefixDir + entry.Name() + "/"
+		if prefix <= startAfter {
+			continue
+		}
+
+		hasFile, err := fileSystemDirHasFile(filepath.Join(dirPath, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list object prefixes in bucket %s:%s, %w", s.bucket, keyPrefix, err)
+		}
+
+		if hasFile {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	// a name sorts before its extensions, but a prefix may sort after them
+	sort.Strings(prefixes)
+
+	if maxCount > 0 && len(prefixes) > maxCount {
+		prefixes = prefixes[:maxCount]
+	}
+
+	return prefixes, nil
+}
+
+var errFileSystemFileFound = errorswrapper.New("file found")
+
+func fileSystemDirHasFile(dirPath string) (bool, error) {
+	err := filepath.WalkDir(dirPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		return errFileSystemFileFound
+	})
+	if errorswrapper.Is(err, errFileSystemFileFound) {
+		return true, nil
+	}
+
+	return false, err
+}
+
+func (s *fileSystemObjectStore) DeleteObjects(keyPrefix string) error {
+	keys, err := s.ListKeys(keyPrefix)
+	if err != nil {
+		return fmt.Errorf("unable to ListKeys in DeleteObjects "+
+			"from directory %s keyPrefix %s, %w", s.bucketDir, keyPrefix, err)
+	}
+
+	for _, key := range keys {
+		filePath, err := s.keyPath(key)
+		if err != nil {
+			return fmt.Errorf("unable to DeleteObjects from directory %s key %s, %w", s.bucketDir, key, err)
+		}
+
+		if err := os.Remove(filePath); err != nil && !errorswrapper.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("unable to DeleteObjects from directory %s key %s, %w", s.bucketDir, key, err)
+		}
+	}
+
+	return nil
+}
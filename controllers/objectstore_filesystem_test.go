@@ -0,0 +1,366 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// objectFileMetadataEdit applies the given edit to the metadata of the object
+// file of the given path
+func objectFileMetadataEdit(filePath string, edit func(metadata map[string]string)) {
+	data, err := os.ReadFile(filePath)
+	Expect(err).NotTo(HaveOccurred())
+
+	metadataEnd := bytes.IndexByte(data, '\n')
+	Expect(metadataEnd).To(BeNumerically(">=", 0))
+
+	metadata := map[string]string{}
+	Expect(json.Unmarshal(data[:metadataEnd], &metadata)).To(Succeed())
+	edit(metadata)
+
+	encodedMetadata, err := json.Marshal(metadata)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(os.WriteFile(filePath, append(encodedMetadata, data[metadataEnd:]...), 0o600)).To(Succeed())
+}
+
+// s3ProfileObjectStoreGet adds the given profile to the ramen config, with
+// the native kube objects engine, which supports every object store type, and
+// returns its object store
+func s3ProfileObjectStoreGet(s3Profile ramen.S3StoreProfile) controllers.ObjectStorer {
+	ramenConfig.KubeObjectProtection.Engine = ramen.NativeKubeObjectsEngine
+	s3ProfilesStore(append(s3Profiles[0:len(s3Profiles):len(s3Profiles)], s3Profile))
+
+	objectStore, _, err := controllers.S3ObjectStoreGetter().ObjectStore(
+		context.TODO(), apiReader, s3Profile.S3ProfileName, "objectstore-test", testLogger)
+	Expect(err).NotTo(HaveOccurred())
+
+	return objectStore
+}
+
+// s3ProfileObjectStoresRemove restores the s3 profiles and kube objects
+// engine of the ramen config
+func s3ProfileObjectStoresRemove() {
+	ramenConfig.KubeObjectProtection.Engine = ""
+	s3ProfilesStore(s3Profiles[0:])
+}
+
+var _ = Describe("FileSystemObjectStore", func() {
+	const keyPrefix = "namespace/vrg/"
+
+	var (
+		rootDir     string
+		fsProfile   ramen.S3StoreProfile
+		objectStore controllers.ObjectStorer
+	)
+
+	objectStoreGet := func() controllers.ObjectStorer {
+		return s3ProfileObjectStoreGet(fsProfile)
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		rootDir, err = os.MkdirTemp("", "ramen-objectstore-")
+		Expect(err).NotTo(HaveOccurred())
+
+		fsProfile = ramen.S3StoreProfile{
+			S3ProfileName:        "fs-s3profile",
+			Type:                 ramen.FileSystemObjectStoreType,
+			S3Bucket:             bucketNameSucc,
+			S3CompatibleEndpoint: "file://" + rootDir,
+		}
+		objectStore = objectStoreGet()
+	})
+
+	AfterEach(func() {
+		s3ProfileObjectStoresRemove()
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	It("uploads, lists, downloads and deletes PVs", func() {
+		pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv0"}}
+		Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+
+		pvs := []corev1.PersistentVolume{}
+		Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).To(Succeed())
+		Expect(pvs).To(HaveLen(1))
+		Expect(pvs[0].Name).To(Equal(pv.Name))
+
+		Expect(objectStore.DeleteObjects(keyPrefix)).To(Succeed())
+		Expect(objectStore.ListKeys(keyPrefix)).To(BeEmpty())
+	})
+
+	It("detects corrupted and truncated objects", func() {
+		pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv0"}}
+		Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+
+		keys, err := objectStore.ListKeys(keyPrefix)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+		filePath := filepath.Join(rootDir, bucketNameSucc, filepath.FromSlash(keys[0]))
+		data, err := os.ReadFile(filePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, corruptedData := range [][]byte{
+			append(data[:len(data)-1:len(data)-1], data[len(data)-1]^0xff),
+			data[:len(data)-4],
+		} {
+			Expect(os.WriteFile(filePath, corruptedData, 0o600)).To(Succeed())
+
+			pvs := []corev1.PersistentVolume{}
+			err = controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)
+			Expect(errors.As(err, &controllers.ObjectCorruptedError{})).To(BeTrue(), err)
+		}
+	})
+
+	It("accepts and re-signs objects stored without a digest or metadata unless unverified objects are rejected",
+		func() {
+			pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv0"}}
+			Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+
+			keys, err := objectStore.ListKeys(keyPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(HaveLen(1))
+			filePath := filepath.Join(rootDir, bucketNameSucc, filepath.FromSlash(keys[0]))
+			unsign := func(metadata map[string]string) {
+				for name := range metadata {
+					delete(metadata, name)
+				}
+			}
+			objectFileMetadataEdit(filePath, unsign)
+
+			fsProfile.RejectUnverifiedObjects = true
+			objectStore = objectStoreGet()
+			pvs := []corev1.PersistentVolume{}
+			err = controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)
+			Expect(errors.As(err, &controllers.ObjectCorruptedError{})).To(BeTrue(), err)
+
+			fsProfile.RejectUnverifiedObjects = false
+			objectStore = objectStoreGet()
+			Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).To(Succeed())
+			Expect(pvs).To(HaveLen(1))
+
+			fsProfile.RejectUnverifiedObjects = true
+			objectStore = objectStoreGet()
+			pvs = []corev1.PersistentVolume{}
+			Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).To(Succeed())
+			Expect(pvs).To(HaveLen(1))
+		})
+
+	It("rejects objects of another type or API group, but not of another API version", func() {
+		pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv0"}}
+		Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+
+		keys, err := objectStore.ListKeys(keyPrefix)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+		filePath := filepath.Join(rootDir, bucketNameSucc, filepath.FromSlash(keys[0]))
+
+		err = objectStore.DownloadObject(keys[0], &corev1.PersistentVolumeClaim{})
+		Expect(errors.As(err, &controllers.ObjectCorruptedError{})).To(BeTrue(), err)
+
+		objectFileMetadataEdit(filePath,
+			func(metadata map[string]string) { metadata["Ramen-Api-Version"] = "ramendr.openshift.io/v1beta1" })
+		Expect(objectStore.DownloadObject(keys[0], &corev1.PersistentVolume{})).To(Succeed())
+
+		objectFileMetadataEdit(filePath,
+			func(metadata map[string]string) { metadata["Ramen-Api-Version"] = "example.com/v1alpha1" })
+		err = objectStore.DownloadObject(keys[0], &corev1.PersistentVolume{})
+		Expect(errors.As(err, &controllers.ObjectCorruptedError{})).To(BeTrue(), err)
+	})
+
+	It("is rejected while kube objects are protected with velero", func() {
+		ramenConfig.KubeObjectProtection.Engine = ramen.VeleroKubeObjectsEngine
+		configMapUpdate()
+
+		_, _, err := controllers.S3ObjectStoreGetter().ObjectStore(
+			context.TODO(), apiReader, fsProfile.S3ProfileName, "fs-test", testLogger)
+		Expect(err).To(HaveOccurred())
+
+		ramenConfig.KubeObjectProtection.Disabled = true
+		configMapUpdate()
+
+		_, _, err = controllers.S3ObjectStoreGetter().ObjectStore(
+			context.TODO(), apiReader, fsProfile.S3ProfileName, "fs-test", testLogger)
+		ramenConfig.KubeObjectProtection.Disabled = false
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("lists no keys in an absent bucket", func() {
+		Expect(objectStore.ListKeys(keyPrefix)).To(BeEmpty())
+		Expect(objectStore.ListKeyPrefixes("", "", 0)).To(BeEmpty())
+	})
+
+	It("lists key prefixes in order, after a prefix and up to a count", func() {
+		for _, key := range []string{"a/b/0", "a/b-c/0", "a/d/0", "a/e/0", "f/g/0"} {
+			Expect(objectStore.UploadObject(key, "data")).To(Succeed())
+		}
+		Expect(objectStore.DeleteObjects("a/e/")).To(Succeed())
+
+		Expect(objectStore.ListKeyPrefixes("", "", 0)).To(Equal([]string{"a/", "f/"}))
+		Expect(objectStore.ListKeyPrefixes("a/", "", 0)).To(Equal([]string{"a/b-c/", "a/b/", "a/d/"}))
+		Expect(objectStore.ListKeyPrefixes("a/", "a/b-c/", 0)).To(Equal([]string{"a/b/", "a/d/"}))
+		Expect(objectStore.ListKeyPrefixes("a/", "", 2)).To(Equal([]string{"a/b-c/", "a/b/"}))
+		Expect(objectStore.ListKeyPrefixes("a/b", "", 0)).To(Equal([]string{"a/b-c/", "a/b/"}))
+	})
+
+	It("rejects keys that do not name a file", func() {
+		for _, key := range []string{"", "/", keyPrefix, ".."} {
+			Expect(objectStore.UploadObject(key, "data")).NotTo(Succeed(), key)
+		}
+	})
+
+	Context("with a secret", func() {
+		var secret *corev1.Secret
+
+		BeforeEach(func() {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: configMap.Namespace, Name: "fs-secret"},
+				StringData: map[string]string{
+					"AWS_SECRET_ACCESS_KEY":      "secret0",
+					"RAMEN_OBJECT_INTEGRITY_KEY": "integrity0",
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), secret)).To(Succeed())
+
+			fsProfile.S3SecretRef = corev1.SecretReference{Name: secret.Name, Namespace: secret.Namespace}
+			objectStore = objectStoreGet()
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(context.TODO(), secret)).To(Succeed())
+		})
+
+		It("rejects objects not authenticated with an integrity key from the secret", func() {
+			pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv0"}}
+			Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+
+			secretUpdate := func(data map[string]string) {
+				secret.StringData = data
+				Expect(k8sClient.Update(context.TODO(), secret)).To(Succeed())
+				objectStore = objectStoreGet()
+			}
+
+			By("rotating the secret access key")
+			secretUpdate(map[string]string{"AWS_SECRET_ACCESS_KEY": "secret1"})
+			pvs := []corev1.PersistentVolume{}
+			Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).To(Succeed())
+			Expect(pvs).To(HaveLen(1))
+
+			By("rotating the integrity key")
+			secretUpdate(map[string]string{"RAMEN_OBJECT_INTEGRITY_KEY": "integrity1"})
+			err := controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)
+			Expect(errors.As(err, &controllers.ObjectCorruptedError{})).To(BeTrue(), err)
+
+			By("keeping the previous integrity key to verify with")
+			secretUpdate(map[string]string{"RAMEN_OBJECT_INTEGRITY_KEY_PREVIOUS": "integrity0"})
+			pvs = []corev1.PersistentVolume{}
+			Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).To(Succeed())
+			Expect(pvs).To(HaveLen(1))
+		})
+	})
+
+	Context("with encryption", func() {
+		var keySecret *corev1.Secret
+
+		BeforeEach(func() {
+			keySecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: configMap.Namespace, Name: "fs-encryption-keys"},
+				Data:       map[string][]byte{"key1": bytes.Repeat([]byte{1}, 32)},
+			}
+			Expect(k8sClient.Create(context.TODO(), keySecret)).To(Succeed())
+
+			fsProfile.Encryption = ramen.ObjectEncryption{
+				KeySecretRef: corev1.SecretReference{Name: keySecret.Name, Namespace: keySecret.Namespace},
+				KeyID:        "key1",
+			}
+			objectStore = objectStoreGet()
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(context.TODO(), keySecret)).To(Succeed())
+		})
+
+		It("stores objects encrypted and decrypts them after a key rotation", func() {
+			pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-secret-handle"}}
+			Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+
+			keys, err := objectStore.ListKeys(keyPrefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(HaveLen(1))
+			data, err := os.ReadFile(filepath.Join(rootDir, bucketNameSucc, filepath.FromSlash(keys[0])))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("ramen-aes-gcm-v1"))
+
+			keySecret.Data["key2"] = bytes.Repeat([]byte{2}, 32)
+			Expect(k8sClient.Update(context.TODO(), keySecret)).To(Succeed())
+			fsProfile.Encryption.KeyID = "key2"
+			objectStore = objectStoreGet()
+
+			pvs := []corev1.PersistentVolume{}
+			Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).To(Succeed())
+			Expect(pvs).To(HaveLen(1))
+			Expect(pvs[0].Name).To(Equal(pv.Name))
+
+			delete(keySecret.Data, "key1")
+			Expect(k8sClient.Update(context.TODO(), keySecret)).To(Succeed())
+			objectStore = objectStoreGet()
+			Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).NotTo(Succeed())
+		})
+
+		It("rejects unencrypted objects unless allowed", func() {
+			encryption := fsProfile.Encryption
+			fsProfile.Encryption = ramen.ObjectEncryption{}
+			objectStore = objectStoreGet()
+
+			pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-unencrypted"}}
+			Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+
+			fsProfile.Encryption = encryption
+			objectStore = objectStoreGet()
+			pvs := []corev1.PersistentVolume{}
+			Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).NotTo(Succeed())
+
+			fsProfile.Encryption.AllowUnencryptedObjects = true
+			objectStore = objectStoreGet()
+			Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).To(Succeed())
+			Expect(pvs).To(HaveLen(1))
+		})
+
+		It("fails to get the store if its key is not in the secret", func() {
+			fsProfile.Encryption.KeyID = "absent"
+			s3ProfilesStore(append(s3Profiles[0:len(s3Profiles):len(s3Profiles)], fsProfile))
+
+			_, _, err := controllers.S3ObjectStoreGetter().ObjectStore(
+				context.TODO(), apiReader, fsProfile.S3ProfileName, "fs-test", testLogger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
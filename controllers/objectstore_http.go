@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// httpObjectStore stores objects in a generic HTTP object store, as described
+// for ramen.HTTPObjectStoreType
+type httpObjectStore struct {
+	client          *http.Client
+	bucketURL       string
+	bucket          string
+	accessID        string
+	secretAccessKey string
+	callerTag       string
+	name            string
+	encrypter       *objectEncrypter
+	verifier        objectVerifier
+}
+
+// newHTTPObjectStore returns an object store at the http(s):// URL of the
+// given s3 profile's endpoint, with the credentials of its secret, if any
+func newHTTPObjectStore(ctx context.Context, r client.Reader, s3StoreProfile ramen.S3StoreProfile,
+	callerTag string, encrypter *objectEncrypter, verifier objectVerifier,
+) (ObjectStorer, error) {
+	endpointURL, err := url.Parse(s3StoreProfile.S3CompatibleEndpoint)
+	if err != nil || (endpointURL.Scheme != "http" && endpointURL.Scheme != "https") {
+		return nil, fmt.Errorf("endpoint %s of profile %s for caller %s is not an http(s) URL",
+			s3StoreProfile.S3CompatibleEndpoint, s3StoreProfile.S3ProfileName, callerTag)
+	}
+
+	s := &httpObjectStore{
+		client:    &http.Client{Timeout: s3Timeout},
+		bucketURL: strings.TrimSuffix(endpointURL.String(), "/") + "/" + url.PathEscape(s3StoreProfile.S3Bucket) + "/",
+		bucket:    s3StoreProfile.S3Bucket,
+		callerTag: callerTag,
+		name:      s3StoreProfile.S3ProfileName,
+		encrypter: encrypter,
+		verifier:  verifier,
+	}
+
+	if s3StoreProfile.S3SecretRef.Name != "" {
+		accessID, secretAccessKey, err := GetS3Secret(ctx, r, s3StoreProfile.S3SecretRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %v for caller %s, %w",
+				s3StoreProfile.S3SecretRef, callerTag, err)
+		}
+
+		s.accessID = string(accessID)
+		s.secretAccessKey = string(secretAccessKey)
+	}
+
+	return s, nil
+}
+
+func (s *httpObjectStore) keyURL(key string) string {
+	segments := strings.Split(key, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+
+	return s.bucketURL + strings.Join(segments, "/")
+}
+
+// do sends a request with the given method, headers and body to the given URL
+// and returns the response body and headers, or an error if the response
+// status is not 2xx
+func (s *httpObjectStore) do(method, requestURL string, header map[string]string, body io.Reader,
+) ([]byte, http.Header, error) {
+	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(s3Timeout))
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for name, value := range header {
+		request.Header.Set(name, value)
+	}
+
+	if s.accessID != "" {
+		request.SetBasicAuth(s.accessID, s.secretAccessKey)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return nil, nil, fmt.Errorf("%s %s: %s", method, requestURL, response.Status)
+	}
+
+	return responseBody, response.Header, nil
+}
+
+func (s *httpObjectStore) UploadObject(key string, object interface{}) error {
+	encodedObject, metadata, err := encodeObject(s.encrypter, s.verifier, s.bucket, key, object)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := s.do(http.MethodPut, s.keyURL(key), metadata, encodedObject); err != nil {
+		return fmt.Errorf("failed to upload data of %s:%s, %w", s.bucket, key, err)
+	}
+
+	return nil
+}
+
+func (s *httpObjectStore) DownloadObject(key string, objectPointer interface{}) error {
+	data, header, err := s.do(http.MethodGet, s.keyURL(key), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to download data of %s:%s, %w", s.bucket, key, err)
+	}
+
+	metadata := map[string]string{}
+
+	for _, name := range []string{
+		objectDigestMetadataName, objectHMACMetadataName, objectAPIVersionMetadataName, objectTypeMetadataName,
+		encryptionKeyIDMetadataName,
+	} {
+		if value := header.Get(name); value != "" {
+			metadata[name] = value
+		}
+	}
+
+	return decodeObject(s, s.encrypter, s.verifier, s.bucket, key, data, metadata, objectPointer)
+}
+
+func (s *httpObjectStore) ListKeys(keyPrefix string) ([]string, error) {
+	data, _, err := s.do(http.MethodGet, s.bucketURL+"?prefix="+url.QueryEscape(keyPrefix), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects in bucket %s:%s, %w", s.bucket, keyPrefix, err)
+	}
+
+	keys := []string{}
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode list of objects in bucket %s:%s, %w", s.bucket, keyPrefix, err)
+	}
+
+	return keys, nil
+}
+
+func (s *httpObjectStore) ListKeyPrefixes(keyPrefix, startAfter string, maxCount int) ([]string, error) {
+	keys, err := s.ListKeys(keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyPrefixesFromKeys(keys, keyPrefix, startAfter, maxCount), nil
+}
+
+func (s *httpObjectStore) DeleteObjects(keyPrefix string) error {
+	keys, err := s.ListKeys(keyPrefix)
+	if err != nil {
+		return fmt.Errorf("unable to ListKeys in DeleteObjects "+
+			"from endpoint %s keyPrefix %s, %w", s.bucketURL, keyPrefix, err)
+	}
+
+	for _, key := range keys {
+		if _, _, err := s.do(http.MethodDelete, s.keyURL(key), nil, nil); err != nil {
+			return fmt.Errorf("unable to DeleteObjects "+
+				"from endpoint %s key %s, %w", s.bucketURL, key, err)
+		}
+	}
+
+	return nil
+}
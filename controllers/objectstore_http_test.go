@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// httpObject is an object stored by httpObjectStoreServer, with its metadata
+type httpObject struct {
+	data     []byte
+	metadata http.Header
+}
+
+// httpObjectStoreServer serves a bucket of a generic HTTP object store, as
+// described for ramen.HTTPObjectStoreType, from memory
+type httpObjectStoreServer struct {
+	bucket   string
+	user     string
+	password string
+	mutex    sync.Mutex
+	objects  map[string]httpObject
+	// status, if not 0, is returned for every request instead of serving it
+	status int
+}
+
+func (s *httpObjectStoreServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+
+		return
+	}
+
+	if user, password, _ := r.BasicAuth(); user != s.user || password != s.password {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	bucketPath := "/" + s.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, bucketPath) {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, bucketPath)
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		s.list(w, r.URL.Query().Get("prefix"))
+	case r.Method == http.MethodGet:
+		s.get(w, key)
+	case r.Method == http.MethodPut:
+		s.put(w, r, key)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *httpObjectStoreServer) list(w http.ResponseWriter, prefix string) {
+	keys := []string{}
+
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	Expect(json.NewEncoder(w).Encode(keys)).To(Succeed())
+}
+
+func (s *httpObjectStoreServer) get(w http.ResponseWriter, key string) {
+	object, ok := s.objects[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	for name, values := range object.metadata {
+		w.Header()[name] = values
+	}
+
+	_, err := w.Write(object.data)
+	Expect(err).NotTo(HaveOccurred())
+}
+
+func (s *httpObjectStoreServer) put(w http.ResponseWriter, r *http.Request, key string) {
+	data, err := io.ReadAll(r.Body)
+	Expect(err).NotTo(HaveOccurred())
+
+	metadata := http.Header{}
+
+	for name, values := range r.Header {
+		if strings.HasPrefix(name, "Ramen-") {
+			metadata[name] = values
+		}
+	}
+
+	s.objects[key] = httpObject{data: data, metadata: metadata}
+}
+
+var _ = Describe("HTTPObjectStore", func() {
+	const keyPrefix = "namespace/vrg/"
+
+	var (
+		server      *httpObjectStoreServer
+		httpServer  *httptest.Server
+		httpProfile ramen.S3StoreProfile
+		objectStore controllers.ObjectStorer
+	)
+
+	BeforeEach(func() {
+		server = &httpObjectStoreServer{
+			bucket:  bucketNameSucc,
+			user:    awsAccessKeyIDSucc,
+			objects: map[string]httpObject{},
+		}
+		httpServer = httptest.NewServer(server)
+
+		httpProfile = ramen.S3StoreProfile{
+			S3ProfileName:        "http-s3profile",
+			Type:                 ramen.HTTPObjectStoreType,
+			S3Bucket:             bucketNameSucc,
+			S3CompatibleEndpoint: httpServer.URL,
+			S3SecretRef:          corev1.SecretReference{Name: s3Secrets[0].Name, Namespace: s3Secrets[0].Namespace},
+		}
+		objectStore = s3ProfileObjectStoreGet(httpProfile)
+	})
+
+	AfterEach(func() {
+		s3ProfileObjectStoresRemove()
+		httpServer.Close()
+	})
+
+	It("uploads, lists, downloads and deletes PVs", func() {
+		pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv0"}}
+		Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+		Expect(server.objects).To(HaveLen(1))
+
+		pvs := []corev1.PersistentVolume{}
+		Expect(controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)).To(Succeed())
+		Expect(pvs).To(HaveLen(1))
+		Expect(pvs[0].Name).To(Equal(pv.Name))
+
+		Expect(objectStore.DeleteObjects(keyPrefix)).To(Succeed())
+		Expect(objectStore.ListKeys(keyPrefix)).To(BeEmpty())
+		Expect(server.objects).To(BeEmpty())
+	})
+
+	It("lists keys and key prefixes with a prefix", func() {
+		for _, key := range []string{"a/b/0", "a/b/1", "a/d/0", "f/g/0"} {
+			Expect(objectStore.UploadObject(key, "data")).To(Succeed())
+		}
+
+		Expect(objectStore.ListKeys("a/b/")).To(Equal([]string{"a/b/0", "a/b/1"}))
+		Expect(objectStore.ListKeyPrefixes("", "", 0)).To(Equal([]string{"a/", "f/"}))
+		Expect(objectStore.ListKeyPrefixes("a/", "a/b/", 0)).To(Equal([]string{"a/d/"}))
+	})
+
+	It("detects objects corrupted in the store", func() {
+		pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv0"}}
+		Expect(controllers.UploadPV(objectStore, keyPrefix, pv.Name, pv)).To(Succeed())
+
+		for key, object := range server.objects {
+			object.data[len(object.data)-1] ^= 0xff
+			server.objects[key] = object
+		}
+
+		pvs := []corev1.PersistentVolume{}
+		err := controllers.DownloadTypedObjects(objectStore, keyPrefix, &pvs)
+		Expect(errors.As(err, &controllers.ObjectCorruptedError{})).To(BeTrue(), err)
+	})
+
+	It("fails to download an absent object", func() {
+		Expect(objectStore.DownloadObject(keyPrefix+"absent", &corev1.PersistentVolume{})).NotTo(Succeed())
+	})
+
+	It("fails requests the store rejects", func() {
+		server.status = http.StatusInternalServerError
+
+		Expect(objectStore.UploadObject(keyPrefix+"a", "data")).NotTo(Succeed())
+		Expect(objectStore.DownloadObject(keyPrefix+"a", new(string))).NotTo(Succeed())
+		_, err := objectStore.ListKeys(keyPrefix)
+		Expect(err).To(HaveOccurred())
+		Expect(objectStore.DeleteObjects(keyPrefix)).NotTo(Succeed())
+	})
+
+	It("fails requests with credentials the store does not accept", func() {
+		server.user = "other"
+
+		Expect(objectStore.UploadObject(keyPrefix+"a", "data")).NotTo(Succeed())
+		Expect(server.objects).To(BeEmpty())
+	})
+
+	It("fails to list keys if the store does not return a JSON array", func() {
+		httpServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("not json"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		_, err := objectStore.ListKeys(keyPrefix)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects a profile whose endpoint is not an http(s) URL", func() {
+		httpProfile.S3CompatibleEndpoint = "ftp://" + strings.TrimPrefix(httpServer.URL, "http://")
+		s3ProfilesStore(append(s3Profiles[0:len(s3Profiles):len(s3Profiles)], httpProfile))
+
+		_, _, err := controllers.S3ObjectStoreGetter().ObjectStore(
+			context.TODO(), apiReader, httpProfile.S3ProfileName, "objectstore-test", testLogger)
+		Expect(err).To(HaveOccurred())
+	})
+})
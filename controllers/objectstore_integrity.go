@@ -0,0 +1,253 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// objectHMACMetadataName is the name of the metadata with the hex encoded
+	// HMAC-SHA256 of the key, metadata and stored data of an object
+	objectHMACMetadataName = "Ramen-Hmac-Sha256"
+
+	// objectIntegrityKeySecretKey is the key, in the secret of an s3 profile,
+	// of the key of the HMAC of its objects. Objects are verified with their
+	// digest only if it is absent.
+	objectIntegrityKeySecretKey = "RAMEN_OBJECT_INTEGRITY_KEY"
+
+	// objectIntegrityKeySecretKeyPrefix prefixes the keys, in the secret of an
+	// s3 profile, of earlier keys of the HMAC of its objects, such as
+	// RAMEN_OBJECT_INTEGRITY_KEY_PREVIOUS, which verify objects but do not sign
+	// them, so that the key can be rotated
+	objectIntegrityKeySecretKeyPrefix = objectIntegrityKeySecretKey + "_"
+
+	// objectIntegrityKeyLabel distinguishes the HMAC key derived from a secret
+	// from other uses of that secret
+	objectIntegrityKeyLabel = "ramen-object-integrity-v1"
+)
+
+// objectVerifier authenticates the objects of a store with an HMAC keyed from
+// the integrity keys of the secret of its s3 profile or, if it has none,
+// verifies their digest. Objects stored before they were signed, as by earlier
+// versions of Ramen, are accepted and re-signed, unless rejectUnverified.
+type objectVerifier struct {
+	// key signs objects, if not nil
+	key []byte
+	// keys verify objects, starting with key
+	keys             [][]byte
+	rejectUnverified bool
+}
+
+// newObjectVerifier returns a verifier with the integrity keys of the secret
+// of the given s3 profile, if any
+func newObjectVerifier(ctx context.Context, r client.Reader, s3StoreProfile ramen.S3StoreProfile,
+) (objectVerifier, error) {
+	verifier := objectVerifier{rejectUnverified: s3StoreProfile.RejectUnverifiedObjects}
+
+	secretRef := s3StoreProfile.S3SecretRef
+	if secretRef.Name == "" {
+		return verifier, nil
+	}
+
+	secret := corev1.Secret{}
+	namespacedName := types.NamespacedName{Namespace: secretRef.Namespace, Name: secretRef.Name}
+
+	if namespacedName.Namespace == "" {
+		namespacedName.Namespace = NamespaceName()
+	}
+
+	if err := r.Get(ctx, namespacedName, &secret); err != nil {
+		return verifier, fmt.Errorf("failed to get secret %v, %w", secretRef, err)
+	}
+
+	if keyMaterial := secret.Data[objectIntegrityKeySecretKey]; len(keyMaterial) != 0 {
+		verifier.key = objectIntegrityKeyDerive(keyMaterial)
+		verifier.keys = append(verifier.keys, verifier.key)
+	}
+
+	names := make([]string, 0, len(secret.Data))
+
+	for name := range secret.Data {
+		if strings.HasPrefix(name, objectIntegrityKeySecretKeyPrefix) && len(secret.Data[name]) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		verifier.keys = append(verifier.keys, objectIntegrityKeyDerive(secret.Data[name]))
+	}
+
+	return verifier, nil
+}
+
+func objectIntegrityKeyDerive(keyMaterial []byte) []byte {
+	mac := hmac.New(sha256.New, keyMaterial)
+	mac.Write([]byte(objectIntegrityKeyLabel))
+
+	return mac.Sum(nil)
+}
+
+// sign adds to the given metadata, of the given data stored with the given
+// key, its HMAC if the verifier has a key
+func (v objectVerifier) sign(key string, data []byte, metadata map[string]string) {
+	if v.key != nil {
+		metadata[objectHMACMetadataName] = hex.EncodeToString(objectMAC(v.key, key, data, metadata))
+	}
+}
+
+// objectMAC returns the HMAC, with the given integrity key, of the given key,
+// of the metadata that describes the given data and of the data itself, so
+// that neither can be swapped with those of another object
+func objectMAC(integrityKey []byte, key string, data []byte, metadata map[string]string) []byte {
+	mac := hmac.New(sha256.New, integrityKey)
+
+	for _, field := range [][]byte{
+		[]byte(key),
+		[]byte(metadata[objectAPIVersionMetadataName]),
+		[]byte(metadata[objectTypeMetadataName]),
+		[]byte(metadata[encryptionKeyIDMetadataName]),
+		data,
+	} {
+		length := make([]byte, 8)
+		binary.BigEndian.PutUint64(length, uint64(len(field)))
+		mac.Write(length)
+		mac.Write(field)
+	}
+
+	return mac.Sum(nil)
+}
+
+// macVerified returns whether the given HMAC of the given data, stored with
+// the given key along with the given metadata, was keyed from any of the
+// verifier's keys
+func (v objectVerifier) macVerified(expectedMAC, key string, data []byte, metadata map[string]string) bool {
+	for _, integrityKey := range v.keys {
+		if hmac.Equal([]byte(expectedMAC), []byte(hex.EncodeToString(objectMAC(integrityKey, key, data, metadata)))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verify returns an ObjectCorruptedError if the given data, stored with the
+// given key in the given bucket, does not match the HMAC or digest in the given
+// metadata, or if the metadata does not describe an object of the Ramen API
+// group and of the type of the given object pointer. Objects stored without an
+// HMAC, while the verifier has a key to sign them with, or without a digest or
+// metadata, are stored by earlier versions of Ramen. They are rejected if
+// unverified objects are, and are to be re-signed otherwise, as returned.
+func (v objectVerifier) verify(bucket, key string, data []byte, metadata map[string]string,
+	objectPointer interface{},
+) (bool, error) {
+	expectedMAC, hasMAC := metadata[objectHMACMetadataName]
+	_, hasDigest := metadata[objectDigestMetadataName]
+	resign := false
+
+	switch {
+	case hasMAC && len(v.keys) != 0:
+		if !v.macVerified(expectedMAC, key, data, metadata) {
+			return false, ObjectCorruptedError{bucket, key, fmt.Sprintf("HMAC-SHA256 of %d bytes differs from "+
+				"stored %s, or was not keyed from an integrity key of this profile's secret", len(data), expectedMAC)}
+		}
+	case hasDigest:
+		if err := verifyObjectDigest(bucket, key, data, metadata); err != nil {
+			return false, err
+		}
+
+		resign = v.key != nil
+	default:
+		resign = true
+	}
+
+	if _, ok := metadata[objectAPIVersionMetadataName]; !ok {
+		resign = true
+	} else if err := verifyObjectMetadata(bucket, key, metadata, objectPointer); err != nil {
+		return false, err
+	}
+
+	if resign && v.rejectUnverified {
+		return false, ObjectCorruptedError{bucket, key, "stored without an HMAC-SHA256, sha256 digest or metadata, " +
+			"and unverified objects are rejected"}
+	}
+
+	return resign, nil
+}
+
+// verifyObjectDigest returns an ObjectCorruptedError if the given data, stored
+// with the given key in the given bucket, does not match the digest in the
+// given metadata
+func verifyObjectDigest(bucket, key string, data []byte, metadata map[string]string) error {
+	expectedDigest := metadata[objectDigestMetadataName]
+
+	digest := sha256.Sum256(data)
+	if actualDigest := hex.EncodeToString(digest[:]); actualDigest != expectedDigest {
+		return ObjectCorruptedError{bucket, key, fmt.Sprintf("sha256 digest %s of %d bytes differs from stored %s",
+			actualDigest, len(data), expectedDigest)}
+	}
+
+	return nil
+}
+
+// verifyObjectMetadata returns an ObjectCorruptedError unless the given
+// metadata, of an object stored with the given key in the given bucket, has an
+// API version of the Ramen API group and the type of the given object pointer.
+// Objects stored with other versions of the group are decoded into the types of
+// the current one, ignoring fields it does not know.
+func verifyObjectMetadata(bucket, key string, metadata map[string]string, objectPointer interface{}) error {
+	apiVersion := metadata[objectAPIVersionMetadataName]
+	if groupVersion, err := schema.ParseGroupVersion(apiVersion); err != nil ||
+		groupVersion.Group != ramen.GroupVersion.Group {
+		return ObjectCorruptedError{bucket, key, fmt.Sprintf("stored with API version %q, not of API group %q",
+			apiVersion, ramen.GroupVersion.Group)}
+	}
+
+	objectType := strings.TrimLeft(metadata[objectTypeMetadataName], "*")
+	if expectedType := objectTypeName(reflect.TypeOf(objectPointer)); objectType != expectedType {
+		return ObjectCorruptedError{bucket, key, fmt.Sprintf("stored with type %q instead of %q",
+			objectType, expectedType)}
+	}
+
+	return nil
+}
+
+// objectTypeName returns the name of the given type, or of the type it points
+// to, so that objects uploaded by value or by pointer are of the same type
+func objectTypeName(objectType reflect.Type) string {
+	for objectType.Kind() == reflect.Ptr {
+		objectType = objectType.Elem()
+	}
+
+	return objectType.String()
+}
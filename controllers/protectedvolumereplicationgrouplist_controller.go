@@ -19,8 +19,11 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -80,8 +83,8 @@ func (r *ProtectedVolumeReplicationGroupListReconciler) Reconcile(ctx context.Co
 		return ctrl.Result{}, client.IgnoreNotFound(fmt.Errorf("get: %w", err))
 	}
 
-	if s.instance.Status != nil {
-		return ctrl.Result{}, nil
+	if requeueAfter, refresh := s.refreshDue(); !refresh {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	// get target profile from spec
@@ -95,9 +98,9 @@ func (r *ProtectedVolumeReplicationGroupListReconciler) Reconcile(ctx context.Co
 	}
 
 	// get namespace+VRG prefixes as list from S3. Format: unique namespaceName/vrgName pairs
-	prefixNamespaceVRG, err := s.getNamespacesAndVrgPrefixesFromS3(s3profileName)
+	prefixNamespaceVRG, continueValue, err := s.vrgPrefixesList(objectStore)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("error during getNamespacesAndVrgPrefixesFromS3: %w", err)
+		return ctrl.Result{}, fmt.Errorf("error during vrgPrefixesList: %w", err)
 	}
 
 	// get VRG contents from S3
@@ -107,60 +110,172 @@ func (r *ProtectedVolumeReplicationGroupListReconciler) Reconcile(ctx context.Co
 	}
 
 	// store results in Status field
-	err = s.updateStatus(vrgs)
+	err = s.updateStatus(vrgs, continueValue)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error during updateStatus: %w", err)
 	}
 
 	log.Info("protectedvolumereplicationgrouplist updated successfully")
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: s.refreshInterval()}, nil
 }
 
-func (s *ProtectedVolumeReplicationGroupListInstance) getNamespacesAndVrgPrefixesFromS3(s3profileName string,
-) ([]string, error) {
-	const GetAllContents = ""
+func (s *ProtectedVolumeReplicationGroupListInstance) refreshInterval() time.Duration {
+	if s.instance.Spec.RefreshInterval == nil || s.instance.Spec.RefreshInterval.Duration < 0 {
+		return 0
+	}
+
+	return s.instance.Spec.RefreshInterval.Duration
+}
 
-	prefixNamespaceVRG, err := s.ParseResultListFromReplicaStore(s3profileName, GetAllContents, ParseDoubleSlash)
+// refreshDue returns whether the store should be queried now and, if not, how
+// long until it should be. It is queried if the status was never set or was
+// cleared, if the spec changed since it was set, or if it is older than the
+// refresh interval
+func (s *ProtectedVolumeReplicationGroupListInstance) refreshDue() (time.Duration, bool) {
+	status := s.instance.Status
+	if status == nil || status.ObservedGeneration != s.instance.Generation {
+		return 0, true
+	}
+
+	refreshInterval := s.refreshInterval()
+	if refreshInterval == 0 {
+		return 0, false
+	}
+
+	if requeueAfter := time.Until(status.SampleTime.Add(refreshInterval)); requeueAfter > 0 {
+		return requeueAfter, false
+	}
+
+	return 0, true
+}
+
+// vrgPrefixesList lists, in order, the namespace/vrgName prefixes of the VRGs
+// in the store of the spec's namespaces, if any, with a name in the spec's VRG
+// names, if any, that follow the spec's continue value, up to the spec's limit.
+// If the limit is reached before the last prefix, it also returns the last
+// prefix returned as the continue value. It lists the namespace prefixes of
+// the store, and then the VRG prefixes of each namespace, rather than its keys.
+func (s *ProtectedVolumeReplicationGroupListInstance) vrgPrefixesList(objectStore ObjectStorer,
+) ([]string, string, error) {
+	spec := &s.instance.Spec
+
+	namespacePrefixes, err := s.namespacePrefixesList(objectStore)
 	if err != nil {
-		return prefixNamespaceVRG, fmt.Errorf("error during ParseResultListFromReplicaStore: %w", err)
+		return nil, "", err
+	}
+
+	// prefixes are compared with their trailing slash, in the store's order
+	continuePrefix, continueNamespacePrefix := "", ""
+	if spec.Continue != "" {
+		continuePrefix = spec.Continue + "/"
+		continueNamespacePrefix = continuePrefix[:strings.Index(continuePrefix, "/")+1]
+	}
+
+	prefixes := []string{}
+
+	for _, namespacePrefix := range namespacePrefixes {
+		if namespacePrefix < continueNamespacePrefix {
+			continue
+		}
+
+		startAfter := ""
+		if namespacePrefix == continueNamespacePrefix {
+			startAfter = continuePrefix
+		}
+
+		// one more than the limit tells whether more remain
+		maxCount := 0
+		if spec.Limit > 0 && len(spec.VRGNames) == 0 {
+			maxCount = spec.Limit + 1 - len(prefixes)
+		}
+
+		vrgPrefixes, err := objectStore.ListKeyPrefixes(namespacePrefix, startAfter, maxCount)
+		if err != nil {
+			return nil, "", fmt.Errorf("list VRG prefixes of %s: %w", namespacePrefix, err)
+		}
+
+		for _, vrgPrefix := range vrgPrefixes {
+			prefix := strings.TrimSuffix(vrgPrefix, "/")
+
+			if len(spec.VRGNames) > 0 && !containsString(spec.VRGNames, prefix[len(namespacePrefix):]) {
+				continue
+			}
+
+			if spec.Limit > 0 && len(prefixes) == spec.Limit {
+				return prefixes, prefixes[len(prefixes)-1], nil
+			}
+
+			s.log.Info(fmt.Sprintf("prefixNamespaceVRG[%d]=%s", len(prefixes), prefix))
+			prefixes = append(prefixes, prefix)
+		}
 	}
 
-	for index, val := range prefixNamespaceVRG {
-		s.log.Info(fmt.Sprintf("prefixNamespaceVRG[%d]=%s", index, val))
+	return prefixes, "", nil
+}
+
+// namespacePrefixesList returns, in order, the prefixes of the spec's
+// namespaces, if any, or else of the namespaces in the store
+func (s *ProtectedVolumeReplicationGroupListInstance) namespacePrefixesList(objectStore ObjectStorer,
+) ([]string, error) {
+	if len(s.instance.Spec.NamespaceNames) == 0 {
+		namespacePrefixes, err := objectStore.ListKeyPrefixes("", "", 0)
+		if err != nil {
+			return nil, fmt.Errorf("list namespace prefixes: %w", err)
+		}
+
+		return namespacePrefixes, nil
 	}
 
-	return prefixNamespaceVRG, nil
+	namespacePrefixes := make([]string, 0, len(s.instance.Spec.NamespaceNames))
+
+	for _, namespaceName := range s.instance.Spec.NamespaceNames {
+		if namespacePrefix := namespaceName + "/"; !containsString(namespacePrefixes, namespacePrefix) {
+			namespacePrefixes = append(namespacePrefixes, namespacePrefix)
+		}
+	}
+
+	sort.Strings(namespacePrefixes)
+
+	return namespacePrefixes, nil
 }
 
 func (s *ProtectedVolumeReplicationGroupListInstance) getVrgContentsFromS3(prefixNamespaceVRG []string,
 	objectStore ObjectStorer) ([]ramendrv1alpha1.VolumeReplicationGroup, error) {
 	vrgsAll := make([]ramendrv1alpha1.VolumeReplicationGroup, 0)
 
-	const NoPrefixToRemove = ""
-	namespaceNamesList := getUniqueStringsFromList(prefixNamespaceVRG, ParseSingleSlash, NoPrefixToRemove)
+	selector := labels.Everything()
+
+	if s.instance.Spec.LabelSelector != nil {
+		var err error
+
+		selector, err = metav1.LabelSelectorAsSelector(s.instance.Spec.LabelSelector)
+		if err != nil {
+			return vrgsAll, fmt.Errorf("label selector %v: %w", s.instance.Spec.LabelSelector, err)
+		}
+	}
+
+	for _, namespaceAndVRG := range prefixNamespaceVRG {
+		// download VRGs
+		prefixInS3 := namespaceAndVRG + "/"
 
-	s.log.Info("namespaceNames:")
+		vrgs, err := DownloadVRGs(objectStore, prefixInS3)
+		if err != nil {
+			return vrgsAll, fmt.Errorf("error during DownloadVRGs on '%s': %w", prefixInS3, err)
+		}
 
-	for _, namespace := range namespaceNamesList {
-		namespaceAndVRG := getUniqueStringsFromList(prefixNamespaceVRG, ParseRemoveSlashes, namespace)
-		for _, vrgName := range namespaceAndVRG {
-			// download VRGs
-			prefixInS3 := fmt.Sprintf("%s/%s/", namespace, vrgName)
+		// add all VRGs found whose labels match to list
+		for i := range vrgs {
+			vrg := &vrgs[i]
+			s.log.Info(fmt.Sprintf("downloaded VRG with name '%s' in namespace '%s'", vrg.Name, vrg.Namespace))
 
-			vrgs, err := DownloadVRGs(objectStore, prefixInS3)
-			if err != nil {
-				return vrgsAll, fmt.Errorf("error during DownloadVRGs on '%s': %w", prefixInS3, err)
+			if !selector.Matches(labels.Set(vrg.Labels)) {
+				continue
 			}
 
-			// add all VRGs found to list
-			for i := range vrgs {
-				vrg := &vrgs[i]
-				s.log.Info(fmt.Sprintf("downloaded VRG with name '%s' in namespace '%s'", vrg.Name, vrg.Namespace))
-				VrgTidyForList(vrg)
+			VrgTidyForList(vrg)
 
-				vrgsAll = append(vrgsAll, *vrg)
-			}
+			vrgsAll = append(vrgsAll, *vrg)
 		}
 	}
 
@@ -172,12 +287,14 @@ func VrgTidyForList(vrg *ramendrv1alpha1.VolumeReplicationGroup) {
 }
 
 func (s *ProtectedVolumeReplicationGroupListInstance) updateStatus(
-	vrgs []ramendrv1alpha1.VolumeReplicationGroup,
+	vrgs []ramendrv1alpha1.VolumeReplicationGroup, continueValue string,
 ) error {
 	// store all data in Status
 	s.instance.Status = &ramendrv1alpha1.ProtectedVolumeReplicationGroupListStatus{
-		SampleTime: metav1.Now(),
-		Items:      vrgs,
+		SampleTime:         metav1.Now(),
+		Items:              vrgs,
+		ObservedGeneration: s.instance.Generation,
+		Continue:           continueValue,
 	}
 
 	// final Status update to object
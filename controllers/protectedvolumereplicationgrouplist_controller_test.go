@@ -294,3 +294,111 @@ var _ = Describe("ProtectedVolumeReplicationGroupListController", func() {
 		})
 	})
 })
+
+var _ = Describe("ProtectedVolumeReplicationGroupListController filters", func() {
+	const (
+		namespaceName0  = "protectedvrglist-filter-0"
+		namespaceName1  = "protectedvrglist-filter-1"
+		s3ProfileNumber = 1
+	)
+	objectStorer := &objectStorers[s3ProfileNumber]
+	vrg := func(namespaceName, objectName, color string) ramen.VolumeReplicationGroup {
+		return ramen.VolumeReplicationGroup{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: ramen.GroupVersion.String(),
+				Kind:       "VolumeReplicationGroup",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespaceName,
+				Name:      objectName,
+				Labels:    map[string]string{"color": color},
+			},
+		}
+	}
+	vrgs := []ramen.VolumeReplicationGroup{
+		vrg(namespaceName0, "vrg0", "blue"),
+		vrg(namespaceName0, "vrg1", "green"),
+		vrg(namespaceName1, "vrg0", "blue"),
+	}
+	var protectedVrgList *ramen.ProtectedVolumeReplicationGroupList
+	protectedVrgListCreateAndStatusWait := func(spec ramen.ProtectedVolumeReplicationGroupListSpec) {
+		spec.S3ProfileName = s3Profiles[s3ProfileNumber].S3ProfileName
+		protectedVrgList = &ramen.ProtectedVolumeReplicationGroupList{
+			ObjectMeta: metav1.ObjectMeta{Name: "protectedvrglist-filter"},
+			Spec:       spec,
+		}
+		Expect(k8sClient.Create(context.TODO(), protectedVrgList)).To(Succeed())
+		protectedVrgListSampleTimeRecentWait(protectedVrgList)
+	}
+	protectedVrgListNames := func() []string {
+		names := make([]string, len(protectedVrgList.Status.Items))
+		for i := range protectedVrgList.Status.Items {
+			item := &protectedVrgList.Status.Items[i]
+			names[i] = item.Namespace + "/" + item.Name
+		}
+
+		return names
+	}
+	BeforeEach(func() {
+		for i := range vrgs {
+			Expect(controllers.VrgObjectProtect(*objectStorer, vrgs[i])).To(Succeed())
+		}
+	})
+	AfterEach(func() {
+		protectedVrgListDeleteAndNotFoundWait(protectedVrgList)
+		for i := range vrgs {
+			Expect(controllers.VrgObjectUnprotect(*objectStorer, vrgs[i])).To(Succeed())
+		}
+	})
+	It("should report only the VRGs of the given namespaces, names and labels", func() {
+		protectedVrgListCreateAndStatusWait(ramen.ProtectedVolumeReplicationGroupListSpec{
+			NamespaceNames: []string{namespaceName0, namespaceName1},
+			VRGNames:       []string{"vrg0"},
+			LabelSelector:  &metav1.LabelSelector{MatchLabels: map[string]string{"color": "blue"}},
+		})
+		Expect(protectedVrgListNames()).To(ConsistOf(namespaceName0+"/vrg0", namespaceName1+"/vrg0"))
+
+		protectedVrgList.Spec.LabelSelector.MatchLabels["color"] = "green"
+		Expect(k8sClient.Update(context.TODO(), protectedVrgList)).To(Succeed())
+		Eventually(func() int64 {
+			Expect(protectedVrgListGet(protectedVrgList)).To(Succeed())
+
+			return protectedVrgList.Status.ObservedGeneration
+		}, timeout, interval).Should(Equal(protectedVrgList.Generation))
+		Expect(protectedVrgListNames()).To(BeEmpty())
+	})
+	It("should report the VRGs a page at a time", func() {
+		protectedVrgListCreateAndStatusWait(ramen.ProtectedVolumeReplicationGroupListSpec{
+			NamespaceNames: []string{namespaceName1, namespaceName0},
+			Limit:          2,
+		})
+		Expect(protectedVrgListNames()).To(Equal([]string{namespaceName0 + "/vrg0", namespaceName0 + "/vrg1"}))
+		Expect(protectedVrgList.Status.Continue).To(Equal(namespaceName0 + "/vrg1"))
+
+		protectedVrgList.Spec.Continue = protectedVrgList.Status.Continue
+		Expect(k8sClient.Update(context.TODO(), protectedVrgList)).To(Succeed())
+		Eventually(func() int64 {
+			Expect(protectedVrgListGet(protectedVrgList)).To(Succeed())
+
+			return protectedVrgList.Status.ObservedGeneration
+		}, timeout, interval).Should(Equal(protectedVrgList.Generation))
+		Expect(protectedVrgListNames()).To(Equal([]string{namespaceName1 + "/vrg0"}))
+		Expect(protectedVrgList.Status.Continue).To(BeEmpty())
+	})
+	It("should report VRGs stored since the last query once the refresh interval elapses", func() {
+		protectedVrgListCreateAndStatusWait(ramen.ProtectedVolumeReplicationGroupListSpec{
+			RefreshInterval: &metav1.Duration{Duration: time.Second},
+			NamespaceNames:  []string{namespaceName1},
+		})
+		Expect(protectedVrgListNames()).To(Equal([]string{namespaceName1 + "/vrg0"}))
+
+		vrgAdded := vrg(namespaceName1, "vrg1", "blue")
+		Expect(controllers.VrgObjectProtect(*objectStorer, vrgAdded)).To(Succeed())
+		Eventually(func() []string {
+			Expect(protectedVrgListGet(protectedVrgList)).To(Succeed())
+
+			return protectedVrgListNames()
+		}, timeout, interval).Should(ConsistOf(namespaceName1+"/vrg0", namespaceName1+"/vrg1"))
+		Expect(controllers.VrgObjectUnprotect(*objectStorer, vrgAdded)).To(Succeed())
+	})
+})
@@ -22,10 +22,12 @@ import (
 	"io/ioutil"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/go-logr/logr"
 	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -52,6 +54,7 @@ const (
 	drClusterOperatorCatalogSourceNameDefault         = "ramen-catalog"
 	drClusterOperatorClusterServiceVersionNameDefault = drClusterOperatorPackageNameDefault + ".v0.0.1"
 	VeleroNamespaceNameDefault                        = "velero"
+	dataLaggingSchedulingIntervalMultipleDefault      = 2
 )
 
 // FIXME
@@ -131,11 +134,39 @@ func GetRamenConfigS3StoreProfile(ctx context.Context, apiReader client.Reader,
 
 	s3StoreProfile = *s3StoreProfilePointer
 
-	err = s3StoreProfileFormatCheck(&s3StoreProfile)
+	if err = s3StoreProfileFormatCheck(&s3StoreProfile); err != nil {
+		return
+	}
+
+	err = kubeObjectsStoreTypeCheck(ramenConfig, &s3StoreProfile)
 
 	return
 }
 
+// kubeObjectsStoreTypeCheck returns an error if kube objects are protected
+// with an engine that cannot capture to and recover from the store of the
+// given profile, as its kube objects would not be recovered at failover
+func kubeObjectsStoreTypeCheck(ramenConfig *ramendrv1alpha1.RamenConfig,
+	s3StoreProfile *ramendrv1alpha1.S3StoreProfile,
+) error {
+	if ramenConfig.KubeObjectProtection.Disabled {
+		return nil
+	}
+
+	engine := kubeObjectsEngineOrDefault(ramenConfig)
+	if engine != ramendrv1alpha1.VeleroKubeObjectsEngine {
+		return nil
+	}
+
+	if storeType := objectStoreType(*s3StoreProfile); !(veleroRequestsManager{}).ObjectStoreTypeSupported(storeType) {
+		return fmt.Errorf("kube objects engine %s does not support the %s object store of s3 profile %s, "+
+			"set kubeObjectProtection.engine to %s or disable kube object protection",
+			engine, storeType, s3StoreProfile.S3ProfileName, ramendrv1alpha1.NativeKubeObjectsEngine)
+	}
+
+	return nil
+}
+
 func RamenConfigS3StoreProfilePointerGet(ramenConfig *ramendrv1alpha1.RamenConfig, profileName string,
 ) *ramendrv1alpha1.S3StoreProfile {
 	for i := range ramenConfig.S3StoreProfiles {
@@ -157,7 +188,7 @@ func s3StoreProfileFormatCheck(s3StoreProfile *ramendrv1alpha1.S3StoreProfile) (
 		return err
 	}
 
-	_, err = url.ParseRequestURI(s3Endpoint)
+	endpointURL, err := url.ParseRequestURI(s3Endpoint)
 	if err != nil {
 		err = fmt.Errorf("invalid s3 endpoint <%s> in "+
 			"profile %s, reason: %w", s3Endpoint, s3StoreProfile.S3ProfileName, err)
@@ -165,6 +196,21 @@ func s3StoreProfileFormatCheck(s3StoreProfile *ramendrv1alpha1.S3StoreProfile) (
 		return err
 	}
 
+	switch objectStoreType(*s3StoreProfile) {
+	case ramendrv1alpha1.S3ObjectStoreType, ramendrv1alpha1.HTTPObjectStoreType:
+		if endpointURL.Scheme != "http" && endpointURL.Scheme != "https" {
+			return fmt.Errorf("s3 endpoint <%s> in profile %s is not an http(s) URL",
+				s3Endpoint, s3StoreProfile.S3ProfileName)
+		}
+	case ramendrv1alpha1.FileSystemObjectStoreType:
+		if _, err := fileSystemObjectStoreRootDir(s3Endpoint); err != nil {
+			return fmt.Errorf("invalid s3 endpoint in profile %s, reason: %w", s3StoreProfile.S3ProfileName, err)
+		}
+	default:
+		return fmt.Errorf("unknown object store type %q in profile %s",
+			s3StoreProfile.Type, s3StoreProfile.S3ProfileName)
+	}
+
 	s3Bucket := s3StoreProfile.S3Bucket
 	if s3Bucket == "" {
 		err = fmt.Errorf("s3 bucket has not been configured in s3 profile %s",
@@ -173,6 +219,11 @@ func s3StoreProfileFormatCheck(s3StoreProfile *ramendrv1alpha1.S3StoreProfile) (
 		return err
 	}
 
+	if encryption := s3StoreProfile.Encryption; encryption.KeySecretRef.Name != "" && encryption.KeyID == "" {
+		return fmt.Errorf("encryption key id has not been configured in s3 profile %s",
+			s3StoreProfile.S3ProfileName)
+	}
+
 	return nil
 }
 
@@ -291,3 +342,30 @@ func drClusterOperatorClusterServiceVersionNameOrDefault(ramenConfig *ramendrv1a
 
 	return ramenConfig.DrClusterOperator.ClusterServiceVersionName
 }
+
+func kubeObjectsEngineOrDefault(ramenConfig *ramendrv1alpha1.RamenConfig) ramendrv1alpha1.KubeObjectsEngine {
+	if ramenConfig.KubeObjectProtection.Engine == "" {
+		return ramendrv1alpha1.VeleroKubeObjectsEngine
+	}
+
+	return ramenConfig.KubeObjectProtection.Engine
+}
+
+func dataLaggingSchedulingIntervalMultipleOrDefault(ramenConfig *ramendrv1alpha1.RamenConfig) int {
+	if ramenConfig.DataLagging.SchedulingIntervalMultiple <= 0 {
+		return dataLaggingSchedulingIntervalMultipleDefault
+	}
+
+	return ramenConfig.DataLagging.SchedulingIntervalMultiple
+}
+
+// dataLaggingThreshold returns the age beyond which replicated data, that is
+// synced every schedulingInterval, is lagging
+func dataLaggingThreshold(schedulingInterval string, ramenConfig *ramendrv1alpha1.RamenConfig) (time.Duration, error) {
+	interval, err := rmnutil.SchedulingIntervalDuration(schedulingInterval)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(dataLaggingSchedulingIntervalMultipleOrDefault(ramenConfig)) * interval, nil
+}
@@ -17,10 +17,15 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -34,6 +39,7 @@ import (
 	ramen "github.com/ramendr/ramen/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -101,9 +107,46 @@ type ObjectStorer interface {
 	UploadObject(key string, object interface{}) error
 	DownloadObject(key string, objectPointer interface{}) error
 	ListKeys(keyPrefix string) (keys []string, err error)
+	// ListKeyPrefixes lists, in order, the distinct prefixes of the keys with
+	// the given key prefix, up to and including the first "/" that follows
+	// it, that are after startAfter, up to maxCount of them unless it is 0
+	ListKeyPrefixes(keyPrefix, startAfter string, maxCount int) (prefixes []string, err error)
 	DeleteObjects(keyPrefix string) error
 }
 
+// keyPrefixesFromKeys returns, in order, the distinct prefixes of the given
+// keys with the given key prefix, up to and including the first "/" that
+// follows it, that are after startAfter, up to maxCount of them unless it is 0
+func keyPrefixesFromKeys(keys []string, keyPrefix, startAfter string, maxCount int) []string {
+	prefixes := []string{}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, keyPrefix) {
+			continue
+		}
+
+		slashIndex := strings.Index(key[len(keyPrefix):], "/")
+		if slashIndex < 0 {
+			continue
+		}
+
+		prefix := key[:len(keyPrefix)+slashIndex+1]
+		if prefix <= startAfter || (len(prefixes) > 0 && prefixes[len(prefixes)-1] == prefix) {
+			continue
+		}
+
+		if maxCount > 0 && len(prefixes) == maxCount {
+			break
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes
+}
+
 // S3ObjectStoreGetter returns a concrete type that implements
 // the ObjectStoreGetter interface, allowing the concrete type
 // to be not exported.
@@ -115,11 +158,10 @@ func S3ObjectStoreGetter() ObjectStoreGetter {
 // the ObjectStoreGetter interface.
 type s3ObjectStoreGetter struct{}
 
-// ObjectStore returns an S3 object store that satisfies the ObjectStorer
-// interface,  with a downloader and an uploader client connections, by either
-// creating a new connection or returning a previously established connection
-// for the given s3 profile.  Returns an error if s3 profile does not exists,
-// secret is not configured, or if client session creation fails.
+// ObjectStore returns an object store, of the type configured in the given s3
+// profile, that satisfies the ObjectStorer interface.  Returns an error if s3
+// profile does not exists, its type is unknown, or if the object store of
+// that type cannot be accessed.
 func (s3ObjectStoreGetter) ObjectStore(ctx context.Context,
 	r client.Reader, s3ProfileName string,
 	callerTag string, log logr.Logger,
@@ -130,9 +172,54 @@ func (s3ObjectStoreGetter) ObjectStore(ctx context.Context,
 			s3ProfileName, callerTag, err)
 	}
 
+	encrypter, err := newObjectEncrypter(ctx, r, s3StoreProfile)
+	if err != nil {
+		return nil, s3StoreProfile, fmt.Errorf("failed to get encryption keys of profile %s for caller %s, %w",
+			s3ProfileName, callerTag, err)
+	}
+
+	verifier, err := newObjectVerifier(ctx, r, s3StoreProfile)
+	if err != nil {
+		return nil, s3StoreProfile, fmt.Errorf("failed to get integrity key of profile %s for caller %s, %w",
+			s3ProfileName, callerTag, err)
+	}
+
+	var objectStorer ObjectStorer
+
+	switch objectStoreType(s3StoreProfile) {
+	case ramen.S3ObjectStoreType:
+		objectStorer, err = newS3ObjectStore(ctx, r, s3StoreProfile, callerTag, encrypter, verifier)
+	case ramen.FileSystemObjectStoreType:
+		objectStorer, err = newFileSystemObjectStore(s3StoreProfile, callerTag, encrypter, verifier)
+	case ramen.HTTPObjectStoreType:
+		objectStorer, err = newHTTPObjectStore(ctx, r, s3StoreProfile, callerTag, encrypter, verifier)
+	default:
+		err = fmt.Errorf("unknown object store type %q in profile %s for caller %s",
+			s3StoreProfile.Type, s3ProfileName, callerTag)
+	}
+
+	return objectStorer, s3StoreProfile, err
+}
+
+// objectStoreType returns the type of object store of the given s3 profile,
+// which defaults to S3
+func objectStoreType(s3StoreProfile ramen.S3StoreProfile) ramen.ObjectStoreType {
+	if s3StoreProfile.Type == "" {
+		return ramen.S3ObjectStoreType
+	}
+
+	return s3StoreProfile.Type
+}
+
+// newS3ObjectStore returns an S3 object store, with a client and an uploader
+// client connections, for the given s3 profile.  Returns an error if
+// secret is not configured, or if client session creation fails.
+func newS3ObjectStore(ctx context.Context, r client.Reader, s3StoreProfile ramen.S3StoreProfile,
+	callerTag string, encrypter *objectEncrypter, verifier objectVerifier,
+) (ObjectStorer, error) {
 	accessID, secretAccessKey, err := GetS3Secret(ctx, r, s3StoreProfile.S3SecretRef)
 	if err != nil {
-		return nil, s3StoreProfile, fmt.Errorf("failed to get secret %v for caller %s, %w",
+		return nil, fmt.Errorf("failed to get secret %v for caller %s, %w",
 			s3StoreProfile.S3SecretRef, callerTag, err)
 	}
 
@@ -149,32 +236,32 @@ func (s3ObjectStoreGetter) ObjectStore(ctx context.Context,
 		S3ForcePathStyle: aws.Bool(true),
 	})
 	if err != nil {
-		return nil, s3StoreProfile, fmt.Errorf("failed to create new session for %s for caller %s, %w",
+		return nil, fmt.Errorf("failed to create new session for %s for caller %s, %w",
 			s3Endpoint, callerTag, err)
 	}
 
 	// Create a client session
 	s3Client := s3.New(s3Session)
 
-	// Also create S3 uploader and S3 downloader which can be safely used
-	// concurrently across goroutines, whereas, the s3 client session
-	// does not support concurrent writers.
+	// Also create S3 uploader which can be safely used concurrently across
+	// goroutines, whereas, the s3 client session does not support concurrent
+	// writers.
 	s3Uploader := s3manager.NewUploaderWithClient(s3Client)
-	s3Downloader := s3manager.NewDownloaderWithClient(s3Client)
 	s3BatchDeleter := s3manager.NewBatchDeleteWithClient(s3Client)
 	s3Conn := &s3ObjectStore{
 		session:      s3Session,
 		client:       s3Client,
 		uploader:     s3Uploader,
-		downloader:   s3Downloader,
 		batchDeleter: s3BatchDeleter,
 		s3Endpoint:   s3Endpoint,
 		s3Bucket:     s3StoreProfile.S3Bucket,
 		callerTag:    callerTag,
-		name:         s3ProfileName,
+		name:         s3StoreProfile.S3ProfileName,
+		encrypter:    encrypter,
+		verifier:     verifier,
 	}
 
-	return s3Conn, s3StoreProfile, nil
+	return s3Conn, nil
 }
 
 func GetS3Secret(ctx context.Context, r client.Reader,
@@ -204,12 +291,13 @@ type s3ObjectStore struct {
 	session      *session.Session
 	client       *s3.S3
 	uploader     *s3manager.Uploader
-	downloader   *s3manager.Downloader
 	batchDeleter *s3manager.BatchDelete
 	s3Endpoint   string
 	s3Bucket     string
 	callerTag    string
 	name         string
+	encrypter    *objectEncrypter
+	verifier     objectVerifier
 }
 
 // CreateBucket creates the given bucket; does not return an error if the bucket
@@ -366,6 +454,122 @@ func DeleteTypedObjects(s ObjectStorer, keyPrefix, keySuffix string, object inte
 	return s.DeleteObjects(typedKey(keyPrefix, keySuffix, reflect.TypeOf(object)))
 }
 
+// Names of the metadata stored with each object
+const (
+	// SHA-256 digest of the stored data of the object, hex encoded
+	objectDigestMetadataName = "Ramen-Sha256"
+
+	// API version of Ramen that stored the object
+	objectAPIVersionMetadataName = "Ramen-Api-Version"
+
+	// Go type of the stored object
+	objectTypeMetadataName = "Ramen-Object-Type"
+)
+
+// ObjectCorruptedError is returned on download of an object whose data does
+// not match the HMAC or digest stored with it, that was stored without either,
+// or that otherwise cannot be decoded
+type ObjectCorruptedError struct {
+	bucket, key, reason string
+}
+
+func (e ObjectCorruptedError) Error() string {
+	return fmt.Sprintf("corrupted data of %s:%s, %s", e.bucket, e.key, e.reason)
+}
+
+// encodeObject json encodes and gzips the given object, which is stored with
+// the given key in the given bucket, encrypts it if the given encrypter is not
+// nil and signs it with the given verifier. All object store types store
+// objects in this format, along with the returned metadata.
+func encodeObject(encrypter *objectEncrypter, verifier objectVerifier, bucket, key string, object interface{},
+) (*bytes.Buffer, map[string]string, error) {
+	encodedObject := &bytes.Buffer{}
+
+	gzWriter := gzip.NewWriter(encodedObject)
+	if err := json.NewEncoder(gzWriter).Encode(object); err != nil {
+		return nil, nil, fmt.Errorf("failed to json encode %s:%s, %w",
+			bucket, key, err)
+	}
+
+	if err := gzWriter.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to close gzip writer of %s:%s, %w",
+			bucket, key, err)
+	}
+
+	data, err := encryptObjectData(encrypter, encodedObject.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encrypt %s:%s, %w", bucket, key, err)
+	}
+
+	digest := sha256.Sum256(data)
+	metadata := map[string]string{
+		objectDigestMetadataName:     hex.EncodeToString(digest[:]),
+		objectAPIVersionMetadataName: ramen.GroupVersion.String(),
+		objectTypeMetadataName:       objectTypeName(reflect.TypeOf(object)),
+	}
+
+	if encrypter != nil {
+		metadata[encryptionKeyIDMetadataName] = encrypter.keyID
+	}
+
+	verifier.sign(key, data, metadata)
+
+	return bytes.NewBuffer(data), metadata, nil
+}
+
+// decodeObject verifies, decrypts, if encrypted, unzips and json decodes the
+// given data, stored with the given key in the given bucket of the given store
+// along with the given metadata, into the given object pointer. An object
+// stored before objects were signed is uploaded again, so that it is signed.
+func decodeObject(store ObjectStorer, encrypter *objectEncrypter, verifier objectVerifier,
+	bucket, key string, data []byte, metadata map[string]string, objectPointer interface{},
+) error {
+	resign, err := verifier.verify(bucket, key, data, metadata, objectPointer)
+	if err != nil {
+		return err
+	}
+
+	if err := decodeObjectData(encrypter, bucket, key, data, objectPointer); err != nil {
+		return err
+	}
+
+	if resign {
+		// The object is downloaded regardless, as its store may not be
+		// writable, as by a peer cluster
+		log := ctrl.Log.WithName("ObjectStore").WithValues("bucket", bucket, "key", key)
+		if err := store.UploadObject(key, objectPointer); err != nil {
+			log.Info("Object stored before objects were signed not re-signed", "error", err.Error())
+		} else {
+			log.Info("Object stored before objects were signed re-signed")
+		}
+	}
+
+	return nil
+}
+
+func decodeObjectData(encrypter *objectEncrypter, bucket, key string, data []byte, objectPointer interface{},
+) error {
+	data, err := decryptObjectData(encrypter, data)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s:%s, %w", bucket, key, err)
+	}
+
+	gzReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return ObjectCorruptedError{bucket, key, fmt.Sprintf("failed to unzip data, %v", err)}
+	}
+
+	if err := json.NewDecoder(gzReader).Decode(objectPointer); err != nil {
+		return ObjectCorruptedError{bucket, key, fmt.Sprintf("failed to json decode data, %v", err)}
+	}
+
+	if err := gzReader.Close(); err != nil {
+		return ObjectCorruptedError{bucket, key, fmt.Sprintf("failed to close gzip reader, %v", err)}
+	}
+
+	return nil
+}
+
 // UploadObject uploads the given object to the bucket with the given key.
 // - OK to call UploadObject() concurrently from multiple goroutines safely.
 // - Upload may fail due to many reasons: RequestError (connection error),
@@ -376,27 +580,21 @@ func DeleteTypedObjects(s ObjectStorer, keyPrefix, keySuffix string, object inte
 //   DownloadObject() method
 func (s *s3ObjectStore) UploadObject(key string,
 	uploadContent interface{}) error {
-	encodedUploadContent := &bytes.Buffer{}
 	bucket := s.s3Bucket
 
-	gzWriter := gzip.NewWriter(encodedUploadContent)
-	if err := json.NewEncoder(gzWriter).Encode(uploadContent); err != nil {
-		return fmt.Errorf("failed to json encode %s:%s, %w",
-			bucket, key, err)
-	}
-
-	if err := gzWriter.Close(); err != nil {
-		return fmt.Errorf("failed to close gzip writer of %s:%s, %w",
-			bucket, key, err)
+	encodedUploadContent, metadata, err := encodeObject(s.encrypter, s.verifier, bucket, key, uploadContent)
+	if err != nil {
+		return err
 	}
 
 	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(s3Timeout))
 	defer cancel()
 
 	if _, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
-		Bucket: &bucket,
-		Key:    &key,
-		Body:   encodedUploadContent,
+		Bucket:   &bucket,
+		Key:      &key,
+		Body:     encodedUploadContent,
+		Metadata: aws.StringMap(metadata),
 	}); err != nil {
 		return fmt.Errorf("failed to upload data of %s:%s, %w",
 			bucket, key, err)
@@ -513,9 +711,66 @@ func (s *s3ObjectStore) ListKeys(keyPrefix string) (
 	return keys, nil
 }
 
+// ListKeyPrefixes lists, in order, the distinct prefixes of the keys with the
+// given keyPrefix in the bucket, up to and including the first "/" that follows
+// it, that are after startAfter, up to maxCount of them unless it is 0. The
+// store rolls the keys with each prefix up into it, so that they are not each
+// listed, and the listing is continued a page at a time with the continuation
+// token of the previous page.
+func (s *s3ObjectStore) ListKeyPrefixes(keyPrefix, startAfter string, maxCount int) (
+	prefixes []string, err error) {
+	var nextContinuationToken *string
+
+	bucket := s.s3Bucket
+	delimiter := "/"
+	prefixes = []string{}
+
+	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(s3Timeout))
+	defer cancel()
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:    &bucket,
+		Prefix:    &keyPrefix,
+		Delimiter: &delimiter,
+	}
+	if startAfter != "" {
+		input.StartAfter = &startAfter
+	}
+
+	for {
+		input.ContinuationToken = nextContinuationToken
+
+		result, err := s.client.ListObjectsV2WithContext(ctx, input)
+		if err != nil {
+			return nil,
+				fmt.Errorf("failed to list object prefixes in bucket %s:%s, %w",
+					bucket, keyPrefix, err)
+		}
+
+		for _, commonPrefix := range result.CommonPrefixes {
+			// keys with the startAfter prefix are rolled up into it
+			if *commonPrefix.Prefix <= startAfter {
+				continue
+			}
+
+			prefixes = append(prefixes, *commonPrefix.Prefix)
+
+			if maxCount > 0 && len(prefixes) == maxCount {
+				return prefixes, nil
+			}
+		}
+
+		if !*result.IsTruncated {
+			return prefixes, nil
+		}
+
+		nextContinuationToken = result.NextContinuationToken
+	}
+}
+
 // DownloadObject downloads an object from the bucket with the given key,
-// unzips, decodes the json blob and stores the downloaded object in the
-// downloadContent parameter.  The caller is expected to use the correct type of
+// verifies its digest, unzips, decodes the json blob and stores the downloaded
+// object in the downloadContent parameter.  The caller is expected to use the correct type of
 // downloadContent parameter.
 // - OK to call DownloadObject() concurrently from multiple goroutines safely.
 // - Assumes that the object in S3 store are json blobs that have been then
@@ -527,39 +782,38 @@ func (s *s3ObjectStore) ListKeys(keyPrefix string) (
 // - Download may fail due to many reasons: RequestError (connection error),
 //   NoSuchBucket, NoSuchKey, invalid gzip header, json unmarshall error,
 //   InvalidParameter (e.g., empty key), etc.
+// - Data that does not match its HMAC or digest, or cannot be unzipped or
+//   decoded, fails with an ObjectCorruptedError
 func (s *s3ObjectStore) DownloadObject(key string,
 	downloadContent interface{}) error {
 	bucket := s.s3Bucket
-	writerAt := &aws.WriteAtBuffer{}
 
 	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(s3Timeout))
 	defer cancel()
 
-	if _, err := s.downloader.DownloadWithContext(ctx, writerAt, &s3.GetObjectInput{
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
 		Bucket: &bucket,
 		Key:    &key,
-	}); err != nil {
+	})
+	if err != nil {
 		return fmt.Errorf("failed to download data of %s:%s, %w",
 			bucket, key, err)
 	}
 
-	gzReader, err := gzip.NewReader(bytes.NewReader(writerAt.Bytes()))
-	if err != nil && !errorswrapper.Is(err, io.EOF) {
-		return fmt.Errorf("failed to unzip data of %s:%s, %w",
-			bucket, key, err)
-	}
+	defer output.Body.Close()
 
-	if err := json.NewDecoder(gzReader).Decode(downloadContent); err != nil {
-		return fmt.Errorf("failed to decode json decoder of %s:%s, %w",
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read data of %s:%s, %w",
 			bucket, key, err)
 	}
 
-	if err := gzReader.Close(); err != nil {
-		return fmt.Errorf("failed to close gzip reader of %s:%s, %w",
-			bucket, key, err)
+	metadata := make(map[string]string, len(output.Metadata))
+	for name, value := range output.Metadata {
+		metadata[http.CanonicalHeaderKey(name)] = aws.StringValue(value)
 	}
 
-	return nil
+	return decodeObject(s, s.encrypter, s.verifier, bucket, key, data, metadata, downloadContent)
 }
 
 // DeleteObjects() deletes from the bucket any objects that have the given
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -127,6 +128,39 @@ func (f fakeObjectStorer) ListKeys(keyPrefix string) ([]string, error) {
 	return keys, nil
 }
 
+func (f fakeObjectStorer) ListKeyPrefixes(keyPrefix, startAfter string, maxCount int) ([]string, error) {
+	keys, err := f.ListKeys(keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixSet := map[string]struct{}{}
+
+	for _, key := range keys {
+		slashIndex := strings.Index(key[len(keyPrefix):], "/")
+		if slashIndex < 0 {
+			continue
+		}
+
+		if prefix := key[:len(keyPrefix)+slashIndex+1]; prefix > startAfter {
+			prefixSet[prefix] = struct{}{}
+		}
+	}
+
+	prefixes := make([]string, 0, len(prefixSet))
+	for prefix := range prefixSet {
+		prefixes = append(prefixes, prefix)
+	}
+
+	sort.Strings(prefixes)
+
+	if maxCount > 0 && len(prefixes) > maxCount {
+		prefixes = prefixes[:maxCount]
+	}
+
+	return prefixes, nil
+}
+
 func (f fakeObjectStorer) DeleteObjects(keyPrefix string) error {
 	for key := range f.objects {
 		if strings.HasPrefix(key, keyPrefix) {
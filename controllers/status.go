@@ -53,6 +53,18 @@ const (
 	// type is removed from VRG status.
 	VRGTotalConditions = 4
 
+	// PV data is lagging.  This condition is True while the last group sync
+	// of a primary VRG is older than the configured multiple of its scheduling
+	// interval.  It is not set until the data lags for the first time, and is
+	// not counted in VRGTotalConditions.
+	VRGConditionTypeDataLagging = "DataLagging"
+
+	// Kube objects capture hooks succeeded.  This condition reports whether
+	// the hooks of the latest kube objects capture succeeded.  It is not set
+	// until a VRG with capture hooks starts a capture, and is not counted in
+	// VRGTotalConditions.
+	VRGConditionTypeCaptureHooksSucceeded = "CaptureHooksSucceeded"
+
 	// VolSync related conditions. These conditions are only applicable
 	// at individual PVCs and not generic VRG conditions.
 	VRGConditionTypeVolSyncRepSourceSetup      = "ReplicationSourceSetup"
@@ -70,6 +82,7 @@ const (
 	VRGConditionReasonDataProtected              = "DataProtected"
 	VRGConditionReasonProgressing                = "Progressing"
 	VRGConditionReasonClusterDataRestored        = "Restored"
+	VRGConditionReasonClusterDataCorrupted       = "Corrupted"
 	VRGConditionReasonError                      = "Error"
 	VRGConditionReasonErrorUnknown               = "UnknownError"
 	VRGConditionReasonUploading                  = "Uploading"
@@ -80,6 +93,13 @@ const (
 	VRGConditionReasonVolSyncPVsRestored         = "Restored"
 	VRGConditionReasonVolSyncFinalSyncInProgress = "Syncing"
 	VRGConditionReasonVolSyncFinalSyncComplete   = "Synced"
+	VRGConditionReasonLagging                    = "Lagging"
+	VRGConditionReasonInSync                     = "InSync"
+	VRGConditionReasonQuiescing                  = "Quiescing"
+	VRGConditionReasonQuiesced                   = "Quiesced"
+	VRGConditionReasonResumed                    = "Resumed"
+	VRGConditionReasonPreCaptureHookFailed       = "PreCaptureHookFailed"
+	VRGConditionReasonPostCaptureHookFailed      = "PostCaptureHookFailed"
 )
 
 // Just when VRG has been picked up for reconciliation when nothing has been
@@ -260,6 +280,17 @@ func setVRGClusterDataErrorCondition(conditions *[]metav1.Condition, observedGen
 	})
 }
 
+// sets conditions when PV cluster data failed to restore as it is corrupted
+func setVRGClusterDataCorruptedCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               VRGConditionTypeClusterDataReady,
+		Reason:             VRGConditionReasonClusterDataCorrupted,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionFalse,
+		Message:            message,
+	})
+}
+
 // sets conditions when PV cluster data is protected
 func setVRGClusterDataProtectedCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
 	setStatusCondition(conditions, metav1.Condition{
@@ -391,3 +422,59 @@ func setVRGConditionTypeVolSyncPVRestoreError(conditions *[]metav1.Condition, ob
 		Message:            message,
 	})
 }
+
+func setVRGDataLaggingCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               VRGConditionTypeDataLagging,
+		Reason:             VRGConditionReasonLagging,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+		Message:            message,
+	})
+}
+
+func setVRGDataInSyncCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               VRGConditionTypeDataLagging,
+		Reason:             VRGConditionReasonInSync,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionFalse,
+		Message:            message,
+	})
+}
+
+func setVRGCaptureHooksSucceededCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	reason, message string,
+) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               VRGConditionTypeCaptureHooksSucceeded,
+		Reason:             reason,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+		Message:            message,
+	})
+}
+
+func setVRGCaptureHooksProgressingCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	reason, message string,
+) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               VRGConditionTypeCaptureHooksSucceeded,
+		Reason:             reason,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionUnknown,
+		Message:            message,
+	})
+}
+
+func setVRGCaptureHooksFailedCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	reason, message string,
+) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               VRGConditionTypeCaptureHooksSucceeded,
+		Reason:             reason,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionFalse,
+		Message:            message,
+	})
+}
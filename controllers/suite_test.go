@@ -313,6 +313,7 @@ var _ = BeforeSuite(func() {
 		APIReader:         k8sManager.GetAPIReader(),
 		Scheme:            k8sManager.GetScheme(),
 		ObjectStoreGetter: fakeObjectStoreGetter{},
+		MCVGetter:         FakeMCVGetter{},
 	}).SetupWithManager(k8sManager)).To(Succeed())
 
 	err = (&ramencontrollers.VolumeReplicationGroupReconciler{
@@ -332,12 +333,13 @@ var _ = BeforeSuite(func() {
 	}).SetupWithManager(k8sManager)).To(Succeed())
 
 	drpcReconciler := (&ramencontrollers.DRPlacementControlReconciler{
-		Client:    k8sManager.GetClient(),
-		APIReader: k8sManager.GetAPIReader(),
-		Log:       ctrl.Log.WithName("controllers").WithName("DRPlacementControl"),
-		MCVGetter: FakeMCVGetter{},
-		Scheme:    k8sManager.GetScheme(),
-		Callback:  FakeProgressCallback,
+		Client:         k8sManager.GetClient(),
+		APIReader:      k8sManager.GetAPIReader(),
+		Log:            ctrl.Log.WithName("controllers").WithName("DRPlacementControl"),
+		MCVGetter:      FakeMCVGetter{},
+		ObjStoreGetter: fakeObjectStoreGetter{},
+		Scheme:         k8sManager.GetScheme(),
+		Callback:       FakeProgressCallback,
 	})
 	err = drpcReconciler.SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -96,3 +98,33 @@ func DRPolicyS3Profiles(drpolicy *rmn.DRPolicy, drclusters []rmn.DRCluster) sets
 
 	return mustHaveS3Profiles
 }
+
+// SchedulingIntervalDuration converts a scheduling interval of the form
+// <num><m,h,d> to its duration
+func SchedulingIntervalDuration(schedulingInterval string) (time.Duration, error) {
+	const minLength = 2
+
+	if len(schedulingInterval) < minLength {
+		return 0, fmt.Errorf("scheduling interval %q is invalid", schedulingInterval)
+	}
+
+	num, err := strconv.Atoi(schedulingInterval[:len(schedulingInterval)-1])
+	if err != nil || num <= 0 {
+		return 0, fmt.Errorf("scheduling interval %q is invalid", schedulingInterval)
+	}
+
+	var unit time.Duration
+
+	switch schedulingInterval[len(schedulingInterval)-1:] {
+	case "m":
+		unit = time.Minute
+	case "h":
+		unit = time.Hour
+	case "d":
+		unit = 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("scheduling interval %q is invalid, unit must be one of m, h or d", schedulingInterval)
+	}
+
+	return time.Duration(num) * unit, nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("DRPolicy_Util", func() {
+	Context("SchedulingIntervalDuration", func() {
+		It("converts minutes, hours and days to a duration", func() {
+			for schedulingInterval, expected := range map[string]time.Duration{
+				"5m":  5 * time.Minute,
+				"12h": 12 * time.Hour,
+				"2d":  48 * time.Hour,
+			} {
+				duration, err := util.SchedulingIntervalDuration(schedulingInterval)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(duration).To(Equal(expected), schedulingInterval)
+			}
+		})
+		It("rejects invalid scheduling intervals", func() {
+			for _, schedulingInterval := range []string{"", "m", "0h", "5s", "xh"} {
+				_, err := util.SchedulingIntervalDuration(schedulingInterval)
+				Expect(err).To(HaveOccurred(), schedulingInterval)
+			}
+		})
+	})
+})
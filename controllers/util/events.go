@@ -102,6 +102,47 @@ const (
 	// EventReasonSwitchFailed is generated when DRPC fails to switch the cluster
 	// where the app is placed
 	EventReasonSwitchFailed = "DRPCClusterSwitchFailed"
+
+	// EventReasonDrillStarted is generated when DRPC starts a failover drill
+	EventReasonDrillStarted = "DRPCDrillStarted"
+
+	// EventReasonDrillSuccess is generated when the application data is
+	// restored to the drill namespace on the drill cluster
+	EventReasonDrillSuccess = "DRPCDrillSuccess"
+
+	// EventReasonDrillFailed is generated when DRPC fails to start or complete
+	// a failover drill
+	EventReasonDrillFailed = "DRPCDrillFailed"
+
+	// EventReasonDrillCleanedUp is generated when DRPC deletes the resources
+	// of a failover drill from the drill cluster
+	EventReasonDrillCleanedUp = "DRPCDrillCleanedUp"
+
+	// EventReasonDataLagging is generated when the application data on the
+	// peer cluster becomes older than the allowed multiple of the scheduling
+	// interval
+	EventReasonDataLagging = "DRPCDataLagging"
+
+	// EventReasonDataInSync is generated when lagging application data on
+	// the peer cluster is synced again within the allowed lag
+	EventReasonDataInSync = "DRPCDataInSync"
+
+	// EventReasonAutoFailoverStarted is generated when the hub fails over an
+	// application whose current cluster is unavailable
+	EventReasonAutoFailoverStarted = "DRPCAutoFailoverStarted"
+
+	// EventReasonAutoFailoverDeferred is generated when the automatic failover
+	// of an application waits for the rate limit, the concurrent failovers cap
+	// or a surviving cluster
+	EventReasonAutoFailoverDeferred = "DRPCAutoFailoverDeferred"
+
+	// EventReasonAutoFailoverFencing is generated when the hub fences an
+	// unavailable cluster before failing over its metro applications
+	EventReasonAutoFailoverFencing = "DRClusterAutoFailoverFencing"
+
+	// EventReasonFailbackUnfencing is generated when a failback unfences the
+	// cluster it fails back to
+	EventReasonFailbackUnfencing = "DRPCFailbackUnfencing"
 )
 
 // EventReporter is custom events reporter type which allows user to limit the events
@@ -54,6 +54,11 @@ type ManagedClusterViewGetter interface {
 	DeleteNamespaceManagedClusterView(resourceName, resourceNamespace, clusterName, resourceType string) error
 
 	DeleteNFManagedClusterView(resourceName, resourceNamespace, clusterName, resourceType string) error
+
+	GetConfigMapFromManagedCluster(resourceName, resourceNamespace, managedCluster string,
+		annotations map[string]string) (*corev1.ConfigMap, error)
+
+	DeleteConfigMapManagedClusterView(resourceName, resourceNamespace, clusterName, resourceType string) error
 }
 
 type ManagedClusterViewGetterImpl struct {
@@ -147,6 +152,33 @@ func (m ManagedClusterViewGetterImpl) GetNamespaceFromManagedCluster(
 	return namespace, err
 }
 
+func (m ManagedClusterViewGetterImpl) GetConfigMapFromManagedCluster(resourceName, resourceNamespace,
+	managedCluster string, annotations map[string]string) (*corev1.ConfigMap, error) {
+	logger := ctrl.Log.WithName("MCV").WithValues("resouceName", resourceName)
+
+	// get ConfigMap through ManagedClusterView
+	mcvMeta := metav1.ObjectMeta{
+		Name:      BuildManagedClusterViewName(resourceName, resourceNamespace, MWTypeCM),
+		Namespace: managedCluster,
+	}
+
+	if annotations != nil {
+		mcvMeta.Annotations = annotations
+	}
+
+	mcvViewscope := viewv1beta1.ViewScope{
+		Resource:  "ConfigMap",
+		Name:      resourceName,
+		Namespace: resourceNamespace,
+	}
+
+	configMap := &corev1.ConfigMap{}
+
+	err := m.getManagedClusterResource(mcvMeta, mcvViewscope, configMap, logger)
+
+	return configMap, err
+}
+
 /*
 Description: queries a managed cluster for a resource type, and populates a variable with the results.
 Requires:
@@ -255,6 +287,14 @@ func (m ManagedClusterViewGetterImpl) DeleteNFManagedClusterView(
 	return m.DeleteManagedClusterView(clusterName, mcvNameNF, logger)
 }
 
+func (m ManagedClusterViewGetterImpl) DeleteConfigMapManagedClusterView(
+	resourceName, resourceNamespace, clusterName, resourceType string) error {
+	logger := ctrl.Log.WithName("MCV").WithValues("resouceName", resourceName)
+	mcvNameCM := BuildManagedClusterViewName(resourceName, resourceNamespace, MWTypeCM)
+
+	return m.DeleteManagedClusterView(clusterName, mcvNameCM, logger)
+}
+
 func (m ManagedClusterViewGetterImpl) DeleteManagedClusterView(clusterName, mcvName string, logger logr.Logger) error {
 	logger.Info("Delete ManagedClusterView from", "namespace", clusterName, "name", mcvName)
 
@@ -53,9 +53,12 @@ const (
 	ManifestWorkNameFormat string = "%s-%s-%s-mw"
 
 	// ManifestWork Types
-	MWTypeVRG string = "vrg"
-	MWTypeNS  string = "ns"
-	MWTypeNF  string = "nf"
+	MWTypeVRG       string = "vrg"
+	MWTypeNS        string = "ns"
+	MWTypeNF        string = "nf"
+	MWTypeDrill     string = "drill"
+	MWTypeCM        string = "cm"
+	MWTypeNodeFence string = "nodefence"
 )
 
 type MWUtil struct {
@@ -123,7 +126,7 @@ func (mwu *MWUtil) CreateOrUpdateVRGManifestWork(
 	mwu.Log.Info(fmt.Sprintf("Create or Update manifestwork %s:%s:%s:%+v",
 		name, namespace, homeCluster, vrg))
 
-	manifestWork, err := mwu.generateVRGManifestWork(name, namespace, homeCluster, vrg, annotations)
+	manifestWork, err := mwu.generateVRGManifestWork(name, namespace, homeCluster, MWTypeVRG, vrg, annotations)
 	if err != nil {
 		return err
 	}
@@ -131,7 +134,24 @@ func (mwu *MWUtil) CreateOrUpdateVRGManifestWork(
 	return mwu.createOrUpdateManifestWork(manifestWork, homeCluster)
 }
 
-func (mwu *MWUtil) generateVRGManifestWork(name, namespace, homeCluster string,
+// Drill VRG MW creation. The drill VRG lives in a namespace other than the DRPC
+// namespace, hence its MW is named using the DRPC name and namespace and a type
+// distinct from the VRG MW type.
+func (mwu *MWUtil) CreateOrUpdateDrillVRGManifestWork(
+	name, namespace, drillCluster string,
+	vrg rmn.VolumeReplicationGroup, annotations map[string]string) error {
+	mwu.Log.Info(fmt.Sprintf("Create or Update drill manifestwork %s:%s:%s:%+v",
+		name, namespace, drillCluster, vrg))
+
+	manifestWork, err := mwu.generateVRGManifestWork(name, namespace, drillCluster, MWTypeDrill, vrg, annotations)
+	if err != nil {
+		return err
+	}
+
+	return mwu.createOrUpdateManifestWork(manifestWork, drillCluster)
+}
+
+func (mwu *MWUtil) generateVRGManifestWork(name, namespace, homeCluster, mwType string,
 	vrg rmn.VolumeReplicationGroup, annotations map[string]string) (*ocmworkv1.ManifestWork, error) {
 	vrgClientManifest, err := mwu.generateVRGManifest(vrg)
 	if err != nil {
@@ -143,7 +163,7 @@ func (mwu *MWUtil) generateVRGManifestWork(name, namespace, homeCluster string,
 	manifests := []ocmworkv1.Manifest{*vrgClientManifest}
 
 	return mwu.newManifestWork(
-		fmt.Sprintf(ManifestWorkNameFormat, name, namespace, MWTypeVRG),
+		fmt.Sprintf(ManifestWorkNameFormat, name, namespace, mwType),
 		homeCluster,
 		map[string]string{"app": "VRG"},
 		manifests, annotations), nil
@@ -195,6 +215,29 @@ func (mwu *MWUtil) generateNFManifest(nf csiaddonsv1alpha1.NetworkFence) (*ocmwo
 	return mwu.GenerateManifest(nf)
 }
 
+// Node fence request MW creation
+func (mwu *MWUtil) CreateOrUpdateNodeFenceManifestWork(
+	name, namespace, homeCluster string,
+	configMap corev1.ConfigMap, annotations map[string]string) error {
+	mwu.Log.Info(fmt.Sprintf("Create or Update manifestwork %s:%s:%s:%+v",
+		name, namespace, homeCluster, configMap.Data))
+
+	manifest, err := mwu.GenerateManifest(configMap)
+	if err != nil {
+		mwu.Log.Error(err, "failed to generate node fence ConfigMap manifest")
+
+		return err
+	}
+
+	manifestWork := mwu.newManifestWork(
+		fmt.Sprintf(ManifestWorkNameFormat, name, namespace, MWTypeNodeFence),
+		homeCluster,
+		map[string]string{"app": "NodeFence"},
+		[]ocmworkv1.Manifest{*manifest}, annotations)
+
+	return mwu.createOrUpdateManifestWork(manifestWork, homeCluster)
+}
+
 func (mwu *MWUtil) CreateOrUpdateNamespaceManifest(
 	name string, namespaceName string, managedClusterNamespace string,
 	annotations map[string]string) error {
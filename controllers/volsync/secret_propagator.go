@@ -18,6 +18,10 @@ import (
 	plrulev1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
 )
 
+// policyTriggerUpdateAnnotation, once changed, has the policy propagator
+// resolve the hub templates of a policy again
+const policyTriggerUpdateAnnotation = "policy.open-cluster-management.io/trigger-update"
+
 func GetVolSyncSSHSecretNameFromVRGName(vrgName string) string {
 	return fmt.Sprintf("%s-vs-secret", vrgName)
 }
@@ -166,6 +170,16 @@ func (sp *secretPropagator) reconcileSecretPropagationPolicy() error {
 			return fmt.Errorf("%w", err)
 		}
 
+		// Have the hub templates resolve to the keys of the secret anew once
+		// they are rotated, so that all clusters are updated in one step
+		if rotatedAt, ok := sp.SourceSecret.GetAnnotations()[SecretRotatedAtAnnotation]; ok {
+			if policy.Annotations == nil {
+				policy.Annotations = map[string]string{}
+			}
+
+			policy.Annotations[policyTriggerUpdateAnnotation] = rotatedAt
+		}
+
 		policy.Spec = policyv1.PolicySpec{
 			Disabled: false,
 			PolicyTemplates: []*policyv1.PolicyTemplate{
@@ -197,16 +211,24 @@ func (sp *secretPropagator) getEmbeddedConfigPolicy() (*cfgpolicyv1.Configuratio
 			sp.SourceSecret.GetNamespace(), sp.SourceSecret.GetName(), key)
 	}
 
+	secretMetadata := map[string]interface{}{
+		"name":      sp.DestSecretName,
+		"namespace": sp.DestSecretNamespace,
+	}
+
+	// Have the clusters know which keys they have, so that replication is
+	// paused until both have the same ones
+	if rotatedAt, ok := sp.SourceSecret.GetAnnotations()[SecretRotatedAtAnnotation]; ok {
+		secretMetadata["annotations"] = map[string]interface{}{SecretRotatedAtAnnotation: rotatedAt}
+	}
+
 	// Build Secret as map[string]interface{} as we need to encode data as string for this replacement to work
 	secretObjDefinition := map[string]interface{}{
 		"apiVersion": "v1",
 		"kind":       "Secret",
-		"metadata": map[string]interface{}{
-			"name":      sp.DestSecretName,
-			"namespace": sp.DestSecretNamespace,
-		},
-		"type": "Opaque",
-		"data": secretData,
+		"metadata":   secretMetadata,
+		"type":       "Opaque",
+		"data":       secretData,
 	}
 
 	secretObjDefinitionRaw, err := json.Marshal(secretObjDefinition)
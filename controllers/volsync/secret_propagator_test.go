@@ -215,6 +215,43 @@ var _ = Describe("Secret propagator", func() {
 					})
 				})
 
+				Context("When the secret keys are rotated", func() {
+					It("Should have the policy propagate the secret anew, with when its keys were generated", func() {
+						const rotatedAt = "2022-06-01T12:00:00Z"
+
+						testSecret.Annotations = map[string]string{volsync.SecretRotatedAtAnnotation: rotatedAt}
+						Expect(k8sClient.Update(ctx, testSecret)).To(Succeed())
+
+						updatedPolicy := &policyv1.Policy{}
+						Eventually(func() string {
+							err := volsync.PropagateSecretToClusters(ctx, k8sClient, testSecret, owner,
+								destClusters, destSecName, destSecNamespace, logger)
+							if err != nil {
+								return ""
+							}
+
+							if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(createdPolicy),
+								updatedPolicy); err != nil {
+								return ""
+							}
+
+							return updatedPolicy.GetAnnotations()["policy.open-cluster-management.io/trigger-update"]
+						}, maxWait, interval).Should(Equal(rotatedAt))
+
+						embeddedObj, _, err := genericCodec.Decode(
+							updatedPolicy.Spec.PolicyTemplates[0].ObjectDefinition.Raw, nil, nil)
+						Expect(err).NotTo(HaveOccurred())
+						embeddedConfigPolicy, ok := embeddedObj.(*cfgpolicyv1.ConfigurationPolicy)
+						Expect(ok).To(BeTrue())
+
+						embeddedSecret := metav1.PartialObjectMetadata{}
+						Expect(json.Unmarshal(embeddedConfigPolicy.Spec.ObjectTemplates[0].ObjectDefinition.Raw,
+							&embeddedSecret)).To(Succeed())
+						Expect(embeddedSecret.GetAnnotations()).To(
+							HaveKeyWithValue(volsync.SecretRotatedAtAnnotation, rotatedAt))
+					})
+				})
+
 				Context("When cleanup is run and policy/rule/binding exist", func() {
 					// Policy/placementrule/placementbinding were all created at this point
 					It("Should cleanup the policy/rule/binding", func() {
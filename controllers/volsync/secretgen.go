@@ -7,6 +7,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"time"
 
 	"golang.org/x/crypto/ssh"
 
@@ -21,11 +22,16 @@ import (
 
 const keyBitSize = 4096
 
+// SecretRotatedAtAnnotation records, in RFC 3339, when the ssh keys of a
+// volsync replication secret were last generated
+const SecretRotatedAtAnnotation = "ramendr.openshift.io/volsync-secret-rotated-at"
+
 // Creates a new volsync replication secret on the cluster (should be called on the hub cluster).  If the secret
-// already exists, nop
+// already exists, regenerates its keys if rotationPeriod is non-zero and has elapsed since they were generated,
+// and each of clustersRotatedAt, when the keys of the secret on the clusters were generated, is theirs, or else nop
 func ReconcileVolSyncReplicationSecret(ctx context.Context, k8sClient client.Client, ownerObject metav1.Object,
-	secretName, secretNamespace string, log logr.Logger) (*corev1.Secret, error,
-) {
+	secretName, secretNamespace string, rotationPeriod time.Duration, clustersRotatedAt []string, log logr.Logger,
+) (*corev1.Secret, error) {
 	existingSecret := &corev1.Secret{}
 	// See if it exists already
 	err := k8sClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: secretNamespace}, existingSecret)
@@ -35,8 +41,23 @@ func ReconcileVolSyncReplicationSecret(ctx context.Context, k8sClient client.Cli
 		return nil, fmt.Errorf("failed to get secret (%w)", err)
 	}
 
-	if err == nil { // Found the secret, going to assume it doesn't need modification
-		return existingSecret, nil
+	if err == nil {
+		if !secretRotationDue(existingSecret, rotationPeriod, time.Now()) {
+			return existingSecret, nil
+		}
+
+		// Rotating the keys again before the clusters have the last ones would
+		// pause replication until they do
+		for _, clusterRotatedAt := range clustersRotatedAt {
+			if clusterRotatedAt != existingSecret.GetAnnotations()[SecretRotatedAtAnnotation] {
+				log.Info("Secret keys not rotated until all clusters have the last ones",
+					"secretName", secretName, "clustersRotatedAt", clustersRotatedAt)
+
+				return existingSecret, nil
+			}
+		}
+
+		return rotateVolSyncReplicationSecret(ctx, k8sClient, existingSecret, log)
 	}
 
 	secret, err := generateNewVolSyncReplicationSecret(secretName, secretNamespace, log)
@@ -62,8 +83,61 @@ func ReconcileVolSyncReplicationSecret(ctx context.Context, k8sClient client.Cli
 	return secret, nil
 }
 
+// secretRotationDue returns whether the keys of a volsync replication secret
+// are due for rotation: if rotationPeriod is non-zero and has elapsed since
+// they were generated, or if when is unrecorded, as for secrets created
+// before their keys were rotated
+func secretRotationDue(secret *corev1.Secret, rotationPeriod time.Duration, now time.Time) bool {
+	if rotationPeriod <= 0 {
+		return false
+	}
+
+	rotatedAt, err := time.Parse(time.RFC3339, secret.GetAnnotations()[SecretRotatedAtAnnotation])
+	if err != nil {
+		return true
+	}
+
+	return !now.Before(rotatedAt.Add(rotationPeriod))
+}
+
+func rotateVolSyncReplicationSecret(ctx context.Context, k8sClient client.Client, secret *corev1.Secret,
+	log logr.Logger,
+) (*corev1.Secret, error) {
+	rotatedSecret, err := generateNewVolSyncReplicationSecret(secret.GetName(), secret.GetNamespace(), log)
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Data = rotatedSecret.Data
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+
+	secret.Annotations[SecretRotatedAtAnnotation] = rotatedSecret.Annotations[SecretRotatedAtAnnotation]
+
+	log.Info("Rotating volsync rsync secret", "secretName", secret.GetName())
+
+	if err := k8sClient.Update(ctx, secret); err != nil {
+		log.Error(err, "Error rotating secret", "secretName", secret.GetName())
+
+		return nil, fmt.Errorf("error rotating secret for volsync (%w)", err)
+	}
+
+	return secret, nil
+}
+
+// generateNewVolSyncReplicationSecret generates a secret with a key pair for
+// the source to authenticate with, and another for the destination
 func generateNewVolSyncReplicationSecret(secretName, secretNamespace string, log logr.Logger) (*corev1.Secret, error) {
-	priv, pub, err := generateKeyPair(log)
+	sourcePriv, sourcePub, err := generateKeyPair(log)
+	if err != nil {
+		log.Error(err, "Unable to generate new secret for VolSync replication")
+
+		return nil, err
+	}
+
+	destinationPriv, destinationPub, err := generateKeyPair(log)
 	if err != nil {
 		log.Error(err, "Unable to generate new secret for VolSync replication")
 
@@ -72,14 +146,15 @@ func generateNewVolSyncReplicationSecret(secretName, secretNamespace string, log
 
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      secretName,
-			Namespace: secretNamespace,
+			Name:        secretName,
+			Namespace:   secretNamespace,
+			Annotations: map[string]string{SecretRotatedAtAnnotation: time.Now().UTC().Format(time.RFC3339)},
 		},
 		Data: map[string][]byte{
-			"source":          priv,
-			"source.pub":      pub,
-			"destination":     priv,
-			"destination.pub": pub,
+			"source":          sourcePriv,
+			"source.pub":      sourcePub,
+			"destination":     destinationPriv,
+			"destination.pub": destinationPub,
 		},
 	}
 
@@ -3,6 +3,7 @@ package volsync_test
 import (
 	"crypto/x509"
 	"encoding/pem"
+	"time"
 
 	"golang.org/x/crypto/ssh"
 
@@ -52,10 +53,17 @@ var _ = Describe("Secretgen", func() {
 
 	Describe("Reconcile volsync rsync secret", func() {
 		testSecretName := "test-secret-abc"
+		var rotationPeriod time.Duration
+		var clustersRotatedAt []string
+
+		BeforeEach(func() {
+			rotationPeriod = 0
+			clustersRotatedAt = nil
+		})
 
 		JustBeforeEach(func() {
 			testSecret, err := volsync.ReconcileVolSyncReplicationSecret(ctx, k8sClient, owner,
-				testSecretName, testNamespace.GetName(), logger)
+				testSecretName, testNamespace.GetName(), rotationPeriod, clustersRotatedAt, logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(testSecret.GetName()).To(Equal(testSecretName))
@@ -88,6 +96,11 @@ var _ = Describe("Secretgen", func() {
 				destPubBytes, ok := newSecret.Data["destination.pub"]
 				Expect(ok).To(BeTrue())
 				validateKeyPair(destBytes, destPubBytes)
+
+				// Source and destination should each have their own keys
+				Expect(destBytes).NotTo(Equal(sourceBytes))
+
+				Expect(newSecret.GetAnnotations()).To(HaveKey(volsync.SecretRotatedAtAnnotation))
 			})
 		})
 
@@ -122,6 +135,56 @@ var _ = Describe("Secretgen", func() {
 
 				Expect(secret.Data).To(Equal(existingSecret.Data))
 			})
+
+			Context("When the secret keys are due for rotation", func() {
+				BeforeEach(func() {
+					rotationPeriod = time.Hour
+					clustersRotatedAt = []string{"", ""}
+				})
+
+				It("Should rotate the secret keys", func() {
+					secret := &corev1.Secret{}
+					Expect(k8sClient.Get(ctx,
+						types.NamespacedName{Name: testSecretName, Namespace: testNamespace.GetName()},
+						secret)).To(Succeed())
+
+					Expect(secret.Data).NotTo(HaveKey("a"))
+					validateKeyPair(secret.Data["source"], secret.Data["source.pub"])
+					validateKeyPair(secret.Data["destination"], secret.Data["destination.pub"])
+					Expect(secret.GetAnnotations()).To(HaveKey(volsync.SecretRotatedAtAnnotation))
+				})
+
+				Context("When a cluster does not have the secret keys yet", func() {
+					BeforeEach(func() {
+						clustersRotatedAt = []string{"", "2022-06-01T12:00:00Z"}
+					})
+
+					It("Should leave the existing secret unchanged", func() {
+						secret := &corev1.Secret{}
+						Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(existingSecret), secret)).To(Succeed())
+						Expect(secret.Data).To(Equal(existingSecret.Data))
+					})
+				})
+			})
+
+			Context("When the secret keys were rotated within the rotation period", func() {
+				rotatedAt := time.Now().UTC().Format(time.RFC3339)
+
+				BeforeEach(func() {
+					rotationPeriod = time.Hour
+					clustersRotatedAt = []string{rotatedAt, rotatedAt}
+
+					existingSecret.Annotations = map[string]string{volsync.SecretRotatedAtAnnotation: rotatedAt}
+					Expect(k8sClient.Update(ctx, existingSecret)).To(Succeed())
+				})
+
+				It("Should leave the existing secret unchanged", func() {
+					secret := &corev1.Secret{}
+					Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(existingSecret), secret)).To(Succeed())
+					Expect(secret.Data).To(Equal(existingSecret.Data))
+					Expect(secret.GetAnnotations()).To(HaveKeyWithValue(volsync.SecretRotatedAtAnnotation, rotatedAt))
+				})
+			})
 		})
 	})
 })
@@ -77,10 +77,12 @@ type VSHandler struct {
 	schedulingInterval          string
 	volumeSnapshotClassSelector metav1.LabelSelector // volume snapshot classes to be filtered label selector
 	volumeSnapshotClassList     *snapv1.VolumeSnapshotClassList
+	secretRotatedAt             string // when the keys of the ssh secret that both clusters have were generated
 }
 
 func NewVSHandler(ctx context.Context, client client.Client, log logr.Logger, owner metav1.Object,
-	schedulingInterval string, volumeSnapshotClassSelector metav1.LabelSelector) *VSHandler {
+	schedulingInterval string, volumeSnapshotClassSelector metav1.LabelSelector, secretRotatedAt string,
+) *VSHandler {
 	return &VSHandler{
 		ctx:                         ctx,
 		client:                      client,
@@ -89,6 +91,7 @@ func NewVSHandler(ctx context.Context, client client.Client, log logr.Logger, ow
 		schedulingInterval:          schedulingInterval,
 		volumeSnapshotClassSelector: volumeSnapshotClassSelector,
 		volumeSnapshotClassList:     nil, // Do not initialize until we need it
+		secretRotatedAt:             secretRotatedAt,
 	}
 }
 
@@ -141,8 +144,8 @@ func (v *VSHandler) ReconcileRD(
 	// Pre-allocated shared secret - DRPC will generate and propagate this secret from hub to clusters
 	sshKeysSecretName := GetVolSyncSSHSecretNameFromVRGName(v.owner.GetName())
 	// Need to confirm this secret exists on the cluster before proceeding, otherwise volsync will generate it
-	secretExists, err := v.validateSecretAndAddVRGOwnerRef(sshKeysSecretName)
-	if err != nil || !secretExists {
+	secret, err := v.validateSecretAndAddVRGOwnerRef(sshKeysSecretName)
+	if err != nil || secret == nil {
 		return nil, err
 	}
 
@@ -265,11 +268,19 @@ func (v *VSHandler) ReconcileRS(rsSpec ramendrv1alpha1.VolSyncReplicationSourceS
 	sshKeysSecretName := GetVolSyncSSHSecretNameFromVRGName(v.owner.GetName())
 
 	// Need to confirm this secret exists on the cluster before proceeding, otherwise volsync will generate it
-	secretExists, err := v.validateSecretAndAddVRGOwnerRef(sshKeysSecretName)
-	if err != nil || !secretExists {
+	secret, err := v.validateSecretAndAddVRGOwnerRef(sshKeysSecretName)
+	if err != nil || secret == nil {
 		return false, nil, err
 	}
 
+	// Until the destination has the keys of this secret as well, syncs would fail to authenticate
+	paused := secret.GetAnnotations()[SecretRotatedAtAnnotation] != v.secretRotatedAt
+	if paused {
+		l.Info("Pausing ReplicationSource until both clusters have the ssh keys",
+			"secretRotatedAt", secret.GetAnnotations()[SecretRotatedAtAnnotation],
+			"bothClustersRotatedAt", v.secretRotatedAt)
+	}
+
 	// Check if a ReplicationDestination is still here (Can happen if transitioning from secondary to primary)
 	// Before creating a new RS for this PVC, make sure any ReplicationDestination for this PVC is cleaned up first
 	// This avoids a scenario where we create an RS that immediately connects back to an RD that still exists locally
@@ -284,7 +295,7 @@ func (v *VSHandler) ReconcileRS(rsSpec ramendrv1alpha1.VolSyncReplicationSourceS
 		return false, nil, err
 	}
 
-	replicationSource, err := v.createOrUpdateRS(rsSpec, sshKeysSecretName, runFinalSync)
+	replicationSource, err := v.createOrUpdateRS(rsSpec, sshKeysSecretName, runFinalSync, paused)
 	if err != nil {
 		return false, nil, err
 	}
@@ -381,7 +392,7 @@ func (v *VSHandler) cleanupAfterRSFinalSync(rsSpec ramendrv1alpha1.VolSyncReplic
 
 // nolint: funlen
 func (v *VSHandler) createOrUpdateRS(rsSpec ramendrv1alpha1.VolSyncReplicationSourceSpec,
-	sshKeysSecretName string, runFinalSync bool) (*volsyncv1alpha1.ReplicationSource, error,
+	sshKeysSecretName string, runFinalSync, paused bool) (*volsyncv1alpha1.ReplicationSource, error,
 ) {
 	l := v.log.WithValues("rsSpec", rsSpec, "runFinalSync", runFinalSync)
 
@@ -411,6 +422,7 @@ func (v *VSHandler) createOrUpdateRS(rsSpec ramendrv1alpha1.VolSyncReplicationSo
 		addVRGOwnerLabel(v.owner, rs)
 
 		rs.Spec.SourcePVC = rsSpec.ProtectedPVC.Name
+		rs.Spec.Paused = paused
 
 		if runFinalSync {
 			l.V(1).Info("ReplicationSource - final sync")
@@ -681,7 +693,29 @@ func (v *VSHandler) validatePVCAndAddVRGOwnerRef(pvcName string) (*corev1.Persis
 	return pvc, nil
 }
 
-func (v *VSHandler) validateSecretAndAddVRGOwnerRef(secretName string) (bool, error) {
+// GetSecretRotatedAt returns when the keys of the ssh secret on the cluster
+// were generated, if recorded, or an empty string
+func (v *VSHandler) GetSecretRotatedAt() (string, error) {
+	secret := &corev1.Secret{}
+
+	err := v.client.Get(v.ctx,
+		types.NamespacedName{
+			Name:      GetVolSyncSSHSecretNameFromVRGName(v.owner.GetName()),
+			Namespace: v.owner.GetNamespace(),
+		}, secret)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return "", fmt.Errorf("error getting secret (%w)", err)
+		}
+
+		return "", nil
+	}
+
+	return secret.GetAnnotations()[SecretRotatedAtAnnotation], nil
+}
+
+// Returns the secret, or nil if not found
+func (v *VSHandler) validateSecretAndAddVRGOwnerRef(secretName string) (*corev1.Secret, error) {
 	secret := &corev1.Secret{}
 
 	err := v.client.Get(v.ctx,
@@ -693,13 +727,13 @@ func (v *VSHandler) validateSecretAndAddVRGOwnerRef(secretName string) (bool, er
 		if !kerrors.IsNotFound(err) {
 			v.log.Error(err, "Failed to get secret", "secretName", secretName)
 
-			return false, fmt.Errorf("error getting secret (%w)", err)
+			return nil, fmt.Errorf("error getting secret (%w)", err)
 		}
 
 		// Secret is not found
 		v.log.Info("Secret not found", "secretName", secretName)
 
-		return false, nil
+		return nil, nil
 	}
 
 	v.log.Info("Secret exists", "secretName", secretName)
@@ -708,12 +742,12 @@ func (v *VSHandler) validateSecretAndAddVRGOwnerRef(secretName string) (bool, er
 	if err := v.addOwnerReferenceAndUpdate(secret, v.owner); err != nil {
 		v.log.Error(err, "Unable to update secret", "secretName", secretName)
 
-		return true, err
+		return secret, err
 	}
 
 	v.log.V(1).Info("VolSync secret validated", "secret name", secretName)
 
-	return true, nil
+	return secret, nil
 }
 
 func (v *VSHandler) getRS(name string) (*volsyncv1alpha1.ReplicationSource, error) {
@@ -80,7 +80,7 @@ var _ = Describe("VolSync Handler - Volume Replication Class tests", func() {
 
 			BeforeEach(func() {
 				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil,
-					schedulingInterval, metav1.LabelSelector{})
+					schedulingInterval, metav1.LabelSelector{}, "")
 			})
 
 			It("GetVolumeSnapshotClasses() should find all volume snapshot classes", func() {
@@ -110,7 +110,7 @@ var _ = Describe("VolSync Handler - Volume Replication Class tests", func() {
 				}
 
 				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil,
-					schedulingInterval, vsClassLabelSelector)
+					schedulingInterval, vsClassLabelSelector, "")
 			})
 
 			It("GetVolumeSnapshotClasses() should find matching volume snapshot classes", func() {
@@ -154,7 +154,7 @@ var _ = Describe("VolSync Handler - Volume Replication Class tests", func() {
 				}
 
 				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil,
-					schedulingInterval, vsClassLabelSelector)
+					schedulingInterval, vsClassLabelSelector, "")
 			})
 
 			It("GetVolumeSnapshotClasses() should find matching volume snapshot classes", func() {
@@ -207,7 +207,7 @@ var _ = Describe("VolSync Handler", func() {
 		Expect(ownerCm.GetName()).NotTo(BeEmpty())
 		owner = ownerCm
 
-		vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, owner, schedulingInterval, metav1.LabelSelector{})
+		vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, owner, schedulingInterval, metav1.LabelSelector{}, "")
 	})
 
 	AfterEach(func() {
@@ -698,6 +698,34 @@ var _ = Describe("VolSync Handler", func() {
 						It("Should create an ReplicationSource if one does not exist", func() {
 							// All checks here performed in the JustBeforeEach(common checks)
 							Expect(returnedRS).NotTo(BeNil())
+							Expect(createdRS.Spec.Paused).To(BeFalse())
+						})
+
+						Context("When the ssh secret keys are not those both clusters have", func() {
+							const rotatedAt = "2022-06-01T12:00:00Z"
+
+							BeforeEach(func() {
+								vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, owner, schedulingInterval,
+									metav1.LabelSelector{}, rotatedAt)
+							})
+
+							It("Should pause the ReplicationSource until they are", func() {
+								Expect(createdRS.Spec.Paused).To(BeTrue())
+
+								Expect(vsHandler.GetSecretRotatedAt()).To(BeEmpty())
+
+								dummySSHSecret.Annotations = map[string]string{
+									volsync.SecretRotatedAtAnnotation: rotatedAt,
+								}
+								Expect(k8sClient.Update(ctx, dummySSHSecret)).To(Succeed())
+								Eventually(vsHandler.GetSecretRotatedAt, maxWait, interval).Should(Equal(rotatedAt))
+
+								Eventually(func() bool {
+									_, rs, err := vsHandler.ReconcileRS(rsSpec, false)
+
+									return err == nil && rs != nil && !rs.Spec.Paused
+								}, maxWait, interval).Should(BeTrue())
+							})
 						})
 
 						Context("When replication source already exists", func() {
@@ -1176,7 +1204,7 @@ var _ = Describe("VolSync Handler", func() {
 			Expect(k8sClient.Create(ctx, otherOwnerCm)).To(Succeed())
 			Expect(otherOwnerCm.GetName()).NotTo(BeEmpty())
 			otherVSHandler := volsync.NewVSHandler(ctx, k8sClient, logger, otherOwnerCm,
-				schedulingInterval, metav1.LabelSelector{})
+				schedulingInterval, metav1.LabelSelector{}, "")
 
 			for i := 0; i < 2; i++ {
 				otherOwnerRdSpec := ramendrv1alpha1.VolSyncReplicationDestinationSpec{
@@ -1366,7 +1394,7 @@ var _ = Describe("VolSync Handler", func() {
 			Expect(k8sClient.Create(ctx, otherOwnerCm)).To(Succeed())
 			Expect(otherOwnerCm.GetName()).NotTo(BeEmpty())
 			otherVSHandler := volsync.NewVSHandler(ctx, k8sClient, logger, otherOwnerCm,
-				schedulingInterval, metav1.LabelSelector{})
+				schedulingInterval, metav1.LabelSelector{}, "")
 
 			for i := 0; i < 2; i++ {
 				otherOwnerRsSpec := ramendrv1alpha1.VolSyncReplicationSourceSpec{
@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -58,6 +59,8 @@ type VolumeReplicationGroupReconciler struct {
 	ObjStoreGetter ObjectStoreGetter
 	Scheme         *runtime.Scheme
 	eventRecorder  *rmnutil.EventReporter
+	kubeObjects    KubeObjectsRequestsManager
+	podExecutor    podCommandExecutor
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -97,10 +100,26 @@ func (r *VolumeReplicationGroupReconciler) SetupWithManager(
 		}).
 		For(&ramendrv1alpha1.VolumeReplicationGroup{}).
 		Watches(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, pvcMapFun, builder.WithPredicates(pvcPredicate)).
-		Owns(&volrep.VolumeReplication{})
+		Owns(&volrep.VolumeReplication{}).
+		Watches(&source.Kind{Type: &volrep.VolumeReplication{}},
+			handler.EnqueueRequestsFromMapFunc(volRepOwnerLabelsMapFunc)).
+		// VolSync secrets, once their keys are rotated, pause replication
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			&handler.EnqueueRequestForOwner{OwnerType: &ramendrv1alpha1.VolumeReplicationGroup{}})
+
+	kubeObjects, err := kubeObjectsRequestsManagerNew(mgr, ramenConfig)
+	if err != nil {
+		return err
+	}
+
+	r.kubeObjects = kubeObjects
+
+	if r.podExecutor, err = podCommandExecutorNew(mgr.GetConfig()); err != nil {
+		return err
+	}
 
 	if !ramenConfig.KubeObjectProtection.Disabled {
-		kubeObjectsRequestsWatch(builder)
+		kubeObjectsRequestsWatch(builder, r.kubeObjects)
 	}
 
 	return builder.Complete(r)
@@ -214,17 +233,13 @@ func filterPVC(mgr manager.Manager, pvc *corev1.PersistentVolumeClaim, log logr.
 
 	var vrgs ramendrv1alpha1.VolumeReplicationGroupList
 
-	listOptions := []client.ListOption{
-		client.InNamespace(pvc.Namespace),
-	}
-
 	// decide if reconcile request needs to be sent to the
 	// corresponding VolumeReplicationGroup CR by:
-	// - whether there is a VolumeReplicationGroup CR in the namespace
-	//   to which the the pvc belongs to.
+	// - whether there is a VolumeReplicationGroup CR that protects the
+	//   namespace to which the the pvc belongs to.
 	// - whether the labels on pvc match the label selectors from
 	//    VolumeReplicationGroup CR.
-	err := mgr.GetClient().List(context.TODO(), &vrgs, listOptions...)
+	err := mgr.GetClient().List(context.TODO(), &vrgs)
 	if err != nil {
 		log.Error(err, "Failed to get list of VolumeReplicationGroup resources")
 
@@ -232,6 +247,10 @@ func filterPVC(mgr manager.Manager, pvc *corev1.PersistentVolumeClaim, log logr.
 	}
 
 	for _, vrg := range vrgs.Items {
+		if !containsString(vrgNamespaceNames(&vrg), pvc.Namespace) {
+			continue
+		}
+
 		vrgLabelSelector := vrg.Spec.PVCSelector
 		selector, err := metav1.LabelSelectorAsSelector(&vrgLabelSelector)
 		// continue if we fail to get the labels for this object hoping
@@ -254,6 +273,33 @@ func filterPVC(mgr manager.Manager, pvc *corev1.PersistentVolumeClaim, log logr.
 	return req
 }
 
+// vrgNamespaceNames returns the names of the namespaces whose PVCs and kube
+// objects the VRG protects, its own first
+func vrgNamespaceNames(vrg *ramendrv1alpha1.VolumeReplicationGroup) []string {
+	namespaceNames := []string{vrg.Namespace}
+
+	for _, namespaceName := range vrg.Spec.ProtectedNamespaces {
+		if !containsString(namespaceNames, namespaceName) {
+			namespaceNames = append(namespaceNames, namespaceName)
+		}
+	}
+
+	return namespaceNames
+}
+
+// volRepOwnerLabelsMapFunc maps a VolumeReplication resource to the VRG that
+// created it in a namespace other than its own, which cannot be its owner
+func volRepOwnerLabelsMapFunc(obj client.Object) []reconcile.Request {
+	ownerNamespaceName, ownerName, ok := ownerNamespaceNameAndName(obj.GetLabels())
+	if !ok || ownerNamespaceName == obj.GetNamespace() {
+		return []reconcile.Request{}
+	}
+
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: ownerNamespaceName, Name: ownerName}},
+	}
+}
+
 // nolint: lll // disabling line length linter
 // +kubebuilder:rbac:groups=ramendr.openshift.io,resources=volumereplicationgroups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ramendr.openshift.io,resources=volumereplicationgroups/status,verbs=get;update;patch
@@ -262,9 +308,9 @@ func filterPVC(mgr manager.Manager, pvc *corev1.PersistentVolumeClaim, log logr.
 // +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplicationclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments,verbs=get;list;watch
-// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;delete;deletecollection
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;update;patch;create
+// +kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;update;patch;create;delete
 // +kubebuilder:rbac:groups=volsync.backube,resources=replicationdestinations,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=volsync.backube,resources=replicationsources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;update;delete
@@ -317,7 +363,8 @@ func (r *VolumeReplicationGroupReconciler) Reconcile(ctx context.Context, req ct
 	}
 
 	v.volSyncHandler = volsync.NewVSHandler(ctx, r.Client, log, v.instance,
-		v.instance.Spec.Async.SchedulingInterval, v.instance.Spec.Async.VolumeSnapshotClassSelector)
+		v.instance.Spec.Async.SchedulingInterval, v.instance.Spec.Async.VolumeSnapshotClassSelector,
+		v.instance.Spec.VolSync.SecretRotatedAt)
 
 	if v.instance.Status.ProtectedPVCs == nil {
 		v.instance.Status.ProtectedPVCs = []ramendrv1alpha1.ProtectedPVC{}
@@ -439,7 +486,13 @@ func (v *VRGInstance) processVRGActions() (ctrl.Result, error) {
 	case !v.instance.GetDeletionTimestamp().IsZero():
 		v.log = v.log.WithValues("Finalize", true)
 
+		if v.isDrill() {
+			return v.processDrillForDeletion()
+		}
+
 		return v.processForDeletion()
+	case v.isDrill():
+		return v.processAsDrill()
 	case v.instance.Spec.ReplicationState == ramendrv1alpha1.Primary:
 		return v.processAsPrimary()
 	default: // Secondary, not primary and not deleted
@@ -543,8 +596,19 @@ func (v *VRGInstance) restorePVs(result *ctrl.Result) error {
 }
 
 func (v *VRGInstance) listPVCsByPVCSelector() (*corev1.PersistentVolumeClaimList, error) {
-	return rmnutil.ListPVCsByPVCSelector(v.ctx, v.reconciler.Client, v.instance.Spec.PVCSelector, v.instance.Namespace,
-		v.instance.Spec.VolSync.Disabled, v.log)
+	pvcList := &corev1.PersistentVolumeClaimList{}
+
+	for _, namespaceName := range vrgNamespaceNames(v.instance) {
+		namespacePVCList, err := rmnutil.ListPVCsByPVCSelector(v.ctx, v.reconciler.Client,
+			v.instance.Spec.PVCSelector, namespaceName, v.instance.Spec.VolSync.Disabled, v.log)
+		if err != nil {
+			return nil, err
+		}
+
+		pvcList.Items = append(pvcList.Items, namespacePVCList.Items...)
+	}
+
+	return pvcList, nil
 }
 
 // updatePVCList fetches and updates the PVC list to process for the current instance of VRG
@@ -596,11 +660,29 @@ func (v *VRGInstance) updatePVCListForAll() error {
 		numCopied := copy(v.volSyncPVCs, pvcList.Items)
 		v.log.Info("No VolumeReplicationClass available. Using all PVCs with VolSync", "pvcCount", numCopied)
 
-		return nil
+		return v.validateVolSyncPVCsNamespace()
 	}
 
 	// Separate PVCs targeted for VolRep from PVCs targeted for VolSync
-	return v.separatePVCsUsingStorageClassProvisioner(pvcList)
+	if err := v.separatePVCsUsingStorageClassProvisioner(pvcList); err != nil {
+		return err
+	}
+
+	return v.validateVolSyncPVCsNamespace()
+}
+
+// validateVolSyncPVCsNamespace fails if a PVC targeted for VolSync is not in
+// the VRG's namespace, as VolSync resources are created in that namespace only
+func (v *VRGInstance) validateVolSyncPVCsNamespace() error {
+	for idx := range v.volSyncPVCs {
+		pvc := &v.volSyncPVCs[idx]
+		if pvc.Namespace != v.instance.Namespace {
+			return fmt.Errorf("PVC %s/%s requires VolSync, which protects PVCs in the VRG namespace %s only",
+				pvc.Namespace, pvc.Name, v.instance.Namespace)
+		}
+	}
+
+	return nil
 }
 
 func (v *VRGInstance) updateReplicationClassList() error {
@@ -627,8 +709,9 @@ func (v *VRGInstance) separatePVCsUsingVRGStatus(pvcList *corev1.PersistentVolum
 	for idx := range pvcList.Items {
 		pvc := &pvcList.Items[idx]
 
-		for _, protectedPVC := range v.instance.Status.ProtectedPVCs {
-			if pvc.Name == protectedPVC.Name {
+		for i := range v.instance.Status.ProtectedPVCs {
+			protectedPVC := &v.instance.Status.ProtectedPVCs[i]
+			if v.protectedPVCMatches(protectedPVC, pvc.Namespace, pvc.Name) {
 				if protectedPVC.ProtectedByVolSync {
 					v.volSyncPVCs = append(v.volSyncPVCs, *pvc)
 				} else {
@@ -765,7 +848,7 @@ func (v *VRGInstance) processAsPrimary() (ctrl.Result, error) {
 		v.log.Info("Restoring PVs failed", "Error", err.Error())
 
 		msg := fmt.Sprintf("Failed to restore PVs (%v)", err.Error())
-		setVRGClusterDataErrorCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+		v.setVRGClusterDataRestoreErrorCondition(err, msg)
 
 		if err = v.updateVRGStatus(false); err != nil {
 			v.log.Error(err, "VRG Status update failed")
@@ -798,11 +881,21 @@ func (v *VRGInstance) processAsPrimary() (ctrl.Result, error) {
 
 func (v *VRGInstance) reconcileAsPrimary() ctrl.Result {
 	result := ctrl.Result{}
-	if len(v.volSyncPVCs) != 0 {
+
+	// a final sync is neither prepared nor run until the application is quiesced
+	finalSyncQuiesced := v.kubeObjectsFinalSyncHooks(&result)
+
+	if len(v.volSyncPVCs) != 0 && finalSyncQuiesced {
 		result.Requeue = v.reconcileVolSyncAsPrimary()
 	}
 
 	v.reconcileVolRepsAsPrimary(&result.Requeue)
+
+	if finalSyncQuiesced {
+		v.updateFinalSyncStatus()
+	}
+
+	v.clusterDataGenerationCapture(&result)
 	v.kubeObjectsProtect(&result)
 
 	return result
@@ -854,6 +947,8 @@ func (v *VRGInstance) processAsSecondary() (ctrl.Result, error) {
 }
 
 func (v *VRGInstance) reconcileAsSecondary() bool {
+	v.kubeObjectsFinalSyncHooksForget()
+
 	if v.reconcileVolSyncAsSecondary() {
 		return true // requeue
 	}
@@ -869,6 +964,8 @@ func (v *VRGInstance) updateVRGStatus(updateConditions bool) error {
 	}
 
 	v.updateStatusState()
+	v.updateLastGroupSyncTime()
+	v.updateDataLaggingCondition()
 
 	v.instance.Status.ObservedGeneration = v.instance.Generation
 
@@ -895,6 +992,76 @@ func (v *VRGInstance) updateVRGStatus(updateConditions bool) error {
 	return nil
 }
 
+// updateLastGroupSyncTime sets the last group sync time of a primary VRG to the
+// oldest last sync time of its protected PVCs, as the data of all of them is
+// replicated up to that time. It is left unset until every PVC is synced once.
+func (v *VRGInstance) updateLastGroupSyncTime() {
+	v.instance.Status.LastGroupSyncTime = nil
+
+	if v.instance.Spec.ReplicationState != ramendrv1alpha1.Primary || len(v.instance.Status.ProtectedPVCs) == 0 {
+		return
+	}
+
+	var lastGroupSyncTime *metav1.Time
+
+	for idx := range v.instance.Status.ProtectedPVCs {
+		lastSyncTime := v.instance.Status.ProtectedPVCs[idx].LastSyncTime
+		if lastSyncTime == nil || lastSyncTime.IsZero() {
+			return
+		}
+
+		if lastGroupSyncTime == nil || lastSyncTime.Before(lastGroupSyncTime) {
+			lastGroupSyncTime = lastSyncTime
+		}
+	}
+
+	v.instance.Status.LastGroupSyncTime = lastGroupSyncTime.DeepCopy()
+}
+
+// updateDataLaggingCondition sets the DataLagging condition of an async primary
+// VRG while its last group sync is older than the configured multiple of its
+// scheduling interval, and resets it once the data is synced again
+func (v *VRGInstance) updateDataLaggingCondition() {
+	if v.instance.Spec.ReplicationState != ramendrv1alpha1.Primary ||
+		v.instance.Spec.Async.Mode != ramendrv1alpha1.AsyncModeEnabled {
+		meta.RemoveStatusCondition(&v.instance.Status.Conditions, VRGConditionTypeDataLagging)
+
+		return
+	}
+
+	lastGroupSyncTime := v.instance.Status.LastGroupSyncTime
+	if lastGroupSyncTime == nil {
+		return
+	}
+
+	_, ramenConfig, err := ConfigMapGet(v.ctx, v.reconciler.APIReader)
+	if err != nil {
+		v.log.Info("Failed to get ramen config, skipping data lagging check", "error", err.Error())
+
+		return
+	}
+
+	threshold, err := dataLaggingThreshold(v.instance.Spec.Async.SchedulingInterval, ramenConfig)
+	if err != nil {
+		v.log.Info("Invalid scheduling interval, skipping data lagging check", "error", err.Error())
+
+		return
+	}
+
+	if time.Since(lastGroupSyncTime.Time) > threshold {
+		setVRGDataLaggingCondition(&v.instance.Status.Conditions, v.instance.Generation,
+			fmt.Sprintf("Data last synced at %s, which is more than %v ago",
+				lastGroupSyncTime.UTC().Format(time.RFC3339), threshold))
+
+		return
+	}
+
+	if findCondition(v.instance.Status.Conditions, VRGConditionTypeDataLagging) != nil {
+		setVRGDataInSyncCondition(&v.instance.Status.Conditions, v.instance.Generation,
+			fmt.Sprintf("Data synced within %v", threshold))
+	}
+}
+
 func (v *VRGInstance) updateStatusState() {
 	dataReadyCondition := findCondition(v.instance.Status.Conditions, VRGConditionTypeDataReady)
 	if dataReadyCondition == nil {
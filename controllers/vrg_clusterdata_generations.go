@@ -0,0 +1,283 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// A generation of PV cluster data is a copy of the PVs, and the VRG, that a
+// primary VRG with cluster data retention enabled uploads to each of its S3
+// stores once all of its PVs are uploaded for its current generation, and
+// again whenever its generation, its PVCs or the specs of their PVs change. Unlike
+// the latest PV cluster data, generations are not overwritten, so that the PV
+// cluster data prior to a bad change can be restored by naming its generation
+// in the VRG spec. Generations are stored under the VRG's S3 key prefix, in
+// "generations/<name>/", where name is the UTC time of the capture.
+
+const (
+	clusterDataGenerationsPath      = "generations/"
+	clusterDataGenerationNameFormat = "20060102T150405Z"
+)
+
+func clusterDataGenerationKeyPrefix(s3KeyPrefix, generationName string) string {
+	return s3KeyPrefix + clusterDataGenerationsPath + generationName + "/"
+}
+
+// clusterDataGenerationCapture uploads a new generation of the PV cluster data
+// to each S3 store, unless one was uploaded for the VRG's current generation
+// and PVs already, and deletes the generations that exceed the retention limits
+func (v *VRGInstance) clusterDataGenerationCapture(result *ctrl.Result) {
+	vrg := v.instance
+	retention := vrg.Spec.ClusterDataRetention
+
+	if retention == nil || len(v.volRepPVCs) == 0 {
+		return
+	}
+
+	pvs := make([]corev1.PersistentVolume, 0, len(v.volRepPVCs))
+
+	for idx := range v.volRepPVCs {
+		pv, err := v.getPVFromPVC(&v.volRepPVCs[idx])
+		if err != nil {
+			v.log.Error(err, "Cluster data generation capture PV get error", "pvc", v.volRepPVCs[idx].Name)
+
+			result.Requeue = true
+
+			return
+		}
+
+		pvs = append(pvs, pv)
+	}
+
+	pvsDigest, err := clusterDataGenerationPVsDigest(v.volRepPVCs, pvs)
+	if err != nil {
+		v.log.Error(err, "Cluster data generation capture PVs digest error")
+
+		return
+	}
+
+	if last := vrg.Status.LastClusterDataGeneration; last != nil &&
+		last.ObservedGeneration == vrg.Generation && last.PVsDigest == pvsDigest {
+		return
+	}
+
+	if !v.volRepPVCsClusterDataProtected() {
+		v.log.Info("Cluster data generation capture pending PV cluster data upload")
+
+		return
+	}
+
+	now := time.Now().UTC()
+	generationName := now.Format(clusterDataGenerationNameFormat)
+	keyPrefix := clusterDataGenerationKeyPrefix(v.s3KeyPrefix(), generationName)
+
+	for _, s3ProfileName := range vrg.Spec.S3Profiles {
+		if s3ProfileName == NoS3StoreAvailable {
+			continue
+		}
+
+		if err := v.clusterDataGenerationUpload(s3ProfileName, keyPrefix, pvs, now, retention); err != nil {
+			v.log.Error(err, "Cluster data generation capture error", "generation", generationName,
+				"profile", s3ProfileName)
+
+			result.Requeue = true
+
+			return
+		}
+	}
+
+	vrg.Status.LastClusterDataGeneration = &ramen.ClusterDataGeneration{
+		Name:               generationName,
+		ObservedGeneration: vrg.Generation,
+		PVsDigest:          pvsDigest,
+	}
+
+	v.log.Info("Cluster data generation captured", "generation", generationName, "pvs", len(pvs))
+}
+
+func (v *VRGInstance) clusterDataGenerationUpload(s3ProfileName, keyPrefix string,
+	pvs []corev1.PersistentVolume, now time.Time, retention *ramen.ClusterDataRetentionSpec,
+) error {
+	objectStore, err := v.getObjectStorer(s3ProfileName)
+	if err != nil {
+		return err
+	}
+
+	for idx := range pvs {
+		if err := UploadPV(objectStore, keyPrefix, pvs[idx].Name, pvs[idx]); err != nil {
+			return err
+		}
+	}
+
+	if err := uploadTypedObject(objectStore, keyPrefix, vrgS3ObjectNameSuffix, *v.instance); err != nil {
+		return err
+	}
+
+	return ClusterDataGenerationsPrune(objectStore, v.s3KeyPrefix(), now, retention)
+}
+
+// clusterDataGenerationPVsDigest returns a digest of the names of the given
+// PVCs and of the names and specs of the given PVs they are bound to, which
+// changes whenever a PVC is added or removed or a PV spec changes
+func clusterDataGenerationPVsDigest(pvcs []corev1.PersistentVolumeClaim, pvs []corev1.PersistentVolume,
+) (string, error) {
+	entries := make([]string, 0, len(pvs))
+
+	for idx := range pvs {
+		pvSpec, err := json.Marshal(pvs[idx].Spec)
+		if err != nil {
+			return "", fmt.Errorf("unable to json encode spec of PV %s, %w", pvs[idx].Name, err)
+		}
+
+		entries = append(entries, strings.Join([]string{
+			pvcs[idx].Namespace + "/" + pvcs[idx].Name, pvs[idx].Name, string(pvSpec),
+		}, "\n"))
+	}
+
+	sort.Strings(entries)
+
+	digest := sha256.Sum256([]byte(strings.Join(entries, "\n\n")))
+
+	return hex.EncodeToString(digest[:]), nil
+}
+
+func (v *VRGInstance) volRepPVCsClusterDataProtected() bool {
+	for idx := range v.volRepPVCs {
+		protectedPVC := v.findProtectedPVC(v.volRepPVCs[idx].Namespace, v.volRepPVCs[idx].Name)
+		if protectedPVC == nil {
+			return false
+		}
+
+		condition := findCondition(protectedPVC.Conditions, VRGConditionTypeClusterDataProtected)
+		if condition == nil || condition.Status != metav1.ConditionTrue ||
+			condition.ObservedGeneration != v.instance.Generation {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ClusterDataGenerations returns the names, from oldest to latest, of the
+// generations of PV cluster data with the given S3 key prefix in the given store
+func ClusterDataGenerations(objectStore ObjectStorer, s3KeyPrefix string) ([]string, error) {
+	generationsKeyPrefix := s3KeyPrefix + clusterDataGenerationsPath
+
+	keys, err := objectStore.ListKeys(generationsKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list cluster data generations with key prefix %s, %w",
+			generationsKeyPrefix, err)
+	}
+
+	generationNameSet := sets.String{}
+
+	for _, key := range keys {
+		generationNameSet.Insert(strings.SplitN(strings.TrimPrefix(key, generationsKeyPrefix), "/", 2)[0])
+	}
+
+	// sets.String.List() returns the names sorted, which is from oldest to latest
+	return generationNameSet.List(), nil
+}
+
+// ClusterDataGenerationsPrune deletes the generations of PV cluster data that
+// exceed the given retention's count or age, except for the latest one
+func ClusterDataGenerationsPrune(objectStore ObjectStorer, s3KeyPrefix string, now time.Time,
+	retention *ramen.ClusterDataRetentionSpec,
+) error {
+	generationNames, err := ClusterDataGenerations(objectStore, s3KeyPrefix)
+	if err != nil {
+		return err
+	}
+
+	for idx, generationName := range generationNames {
+		retainedCount := len(generationNames) - idx
+		if retainedCount == 1 {
+			break
+		}
+
+		if !clusterDataGenerationExpired(generationName, retainedCount, now, retention) {
+			continue
+		}
+
+		if err := objectStore.DeleteObjects(clusterDataGenerationKeyPrefix(s3KeyPrefix, generationName)); err != nil {
+			return fmt.Errorf("unable to delete cluster data generation %s, %w", generationName, err)
+		}
+	}
+
+	return nil
+}
+
+// clusterDataGenerationExpired returns whether the named generation, which is
+// the oldest of the given number of generations, exceeds the given retention
+func clusterDataGenerationExpired(generationName string, count int, now time.Time,
+	retention *ramen.ClusterDataRetentionSpec,
+) bool {
+	if retention.MaxCount > 0 && count > retention.MaxCount {
+		return true
+	}
+
+	if retention.MaxAge == nil {
+		return false
+	}
+
+	captureTime, err := time.Parse(clusterDataGenerationNameFormat, generationName)
+	if err != nil {
+		// Not a generation that was captured by Ramen; leave it alone
+		return false
+	}
+
+	return now.Sub(captureTime) > retention.MaxAge.Duration
+}
+
+// clusterDataRestoreKeyPrefix returns the S3 key prefix of the PV cluster data
+// to restore, which is that of the generation named in the VRG spec, if any
+func (v *VRGInstance) clusterDataRestoreKeyPrefix() string {
+	if generationName := v.instance.Spec.ClusterDataGeneration; generationName != "" {
+		return clusterDataGenerationKeyPrefix(v.restoreS3KeyPrefix(), generationName)
+	}
+
+	return v.restoreS3KeyPrefix()
+}
+
+// downloadPVsToRestore downloads the PVs to restore from the given store, and
+// fails if the generation named in the VRG spec, if any, is absent from it
+func (v *VRGInstance) downloadPVsToRestore(objectStore ObjectStorer) ([]corev1.PersistentVolume, error) {
+	pvList, err := downloadPVs(objectStore, v.clusterDataRestoreKeyPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	if generationName := v.instance.Spec.ClusterDataGeneration; generationName != "" && len(pvList) == 0 {
+		return nil, fmt.Errorf("cluster data generation %s not found", generationName)
+	}
+
+	return pvList, nil
+}
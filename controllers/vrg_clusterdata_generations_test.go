@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("VRG cluster data generations", func() {
+	const s3KeyPrefix = "namespace/vrg/"
+
+	var (
+		rootDir     string
+		objectStore controllers.ObjectStorer
+	)
+
+	generationsUpload := func(generationNames ...string) {
+		pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv0"}}
+
+		for _, generationName := range generationNames {
+			Expect(controllers.UploadPV(objectStore, s3KeyPrefix+"generations/"+generationName+"/", pv.Name, pv)).
+				To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		rootDir, err = os.MkdirTemp("", "ramen-generations-")
+		Expect(err).NotTo(HaveOccurred())
+
+		objectStore = s3ProfileObjectStoreGet(ramen.S3StoreProfile{
+			S3ProfileName:        "fs-generations-s3profile",
+			Type:                 ramen.FileSystemObjectStoreType,
+			S3Bucket:             bucketNameSucc,
+			S3CompatibleEndpoint: "file://" + rootDir,
+		})
+		generationsUpload("20221001T000000Z", "20221002T000000Z", "20221003T000000Z", "20221004T000000Z")
+	})
+
+	AfterEach(func() {
+		s3ProfileObjectStoresRemove()
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	now := time.Date(2022, 10, 4, 12, 0, 0, 0, time.UTC)
+
+	It("lists generations from oldest to latest", func() {
+		Expect(controllers.ClusterDataGenerations(objectStore, s3KeyPrefix)).To(Equal([]string{
+			"20221001T000000Z", "20221002T000000Z", "20221003T000000Z", "20221004T000000Z",
+		}))
+	})
+
+	It("retains the latest generations up to the maximum count", func() {
+		retention := &ramen.ClusterDataRetentionSpec{MaxCount: 2}
+		Expect(controllers.ClusterDataGenerationsPrune(objectStore, s3KeyPrefix, now, retention)).To(Succeed())
+		Expect(controllers.ClusterDataGenerations(objectStore, s3KeyPrefix)).To(Equal([]string{
+			"20221003T000000Z", "20221004T000000Z",
+		}))
+	})
+
+	It("retains generations up to the maximum age", func() {
+		retention := &ramen.ClusterDataRetentionSpec{MaxAge: &metav1.Duration{Duration: 48 * time.Hour}}
+		Expect(controllers.ClusterDataGenerationsPrune(objectStore, s3KeyPrefix, now, retention)).To(Succeed())
+		Expect(controllers.ClusterDataGenerations(objectStore, s3KeyPrefix)).To(Equal([]string{
+			"20221003T000000Z", "20221004T000000Z",
+		}))
+	})
+
+	It("retains the latest generation regardless of its age", func() {
+		retention := &ramen.ClusterDataRetentionSpec{MaxAge: &metav1.Duration{Duration: time.Hour}}
+		Expect(controllers.ClusterDataGenerationsPrune(objectStore, s3KeyPrefix, now, retention)).To(Succeed())
+		Expect(controllers.ClusterDataGenerations(objectStore, s3KeyPrefix)).To(Equal([]string{
+			"20221004T000000Z",
+		}))
+	})
+})
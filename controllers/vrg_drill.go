@@ -0,0 +1,368 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// A drill VRG restores the PV cluster data, and kube objects, of the VRG with
+// the same name in the drill source namespace to its own namespace. Restored
+// PVs are renamed, retained on deletion, and pre-bound to PVCs that the drill
+// VRG creates, so that the drill neither conflicts with PVs restored for the
+// application itself nor deletes replicated volumes once cleaned up. The PVs
+// are read-only, so that the drill never writes to the replicated volumes, and
+// a verifier pod mounts and reads each of them before the drill is ready. A
+// drill VRG does not replicate or protect anything, and its deletion leaves
+// the cluster data in the S3 stores untouched.
+
+const (
+	drillVerifierImageDefault = "registry.access.redhat.com/ubi8/ubi-minimal:latest"
+	drillVerifierMountPath    = "/drill"
+)
+
+func (v *VRGInstance) isDrill() bool {
+	return v.instance.Spec.Drill != nil
+}
+
+// sourceNamespaceName returns the namespace of the VRG whose cluster data is
+// restored by this VRG
+func (v *VRGInstance) sourceNamespaceName() string {
+	if v.isDrill() {
+		return v.instance.Spec.Drill.SourceNamespace
+	}
+
+	return v.instance.Namespace
+}
+
+// restoreS3KeyPrefix returns the S3 key prefix of the cluster data restored by this VRG
+func (v *VRGInstance) restoreS3KeyPrefix() string {
+	return S3KeyPrefix(types.NamespacedName{
+		Namespace: v.sourceNamespaceName(),
+		Name:      v.instance.Name,
+	}.String())
+}
+
+func (v *VRGInstance) drillLabels() map[string]string {
+	return ownerLabels(v.instance.Namespace, v.instance.Name)
+}
+
+func drillPVName(pvName, drillNamespaceName string) string {
+	return pvName + "-" + drillNamespaceName
+}
+
+// redirectPVForDrill points a PV to be restored by a drill VRG to a PVC with
+// the same name in the drill namespace
+func (v *VRGInstance) redirectPVForDrill(pv *corev1.PersistentVolume) {
+	if !v.isDrill() {
+		return
+	}
+
+	pv.Name = drillPVName(pv.Name, v.instance.Namespace)
+	pv.Spec.ClaimRef.Namespace = v.instance.Namespace
+	pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+
+	if pv.Spec.CSI != nil {
+		pv.Spec.CSI.ReadOnly = true
+	}
+
+	if pv.Labels == nil {
+		pv.Labels = map[string]string{}
+	}
+
+	for key, value := range v.drillLabels() {
+		pv.Labels[key] = value
+	}
+}
+
+// restoreDrillPVCs creates a PVC bound to each PV restored by a drill VRG, ahead
+// of any kube objects recovery, which then leaves these PVCs as is
+func (v *VRGInstance) restoreDrillPVCs(pvList []corev1.PersistentVolume) error {
+	if !v.isDrill() {
+		return nil
+	}
+
+	for idx := range pvList {
+		pvc := drillPVC(&pvList[idx], v.drillLabels())
+
+		if err := v.reconciler.Create(v.ctx, pvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create drill PVC %s/%s (%w)", pvc.Namespace, pvc.Name, err)
+		}
+	}
+
+	v.log.Info("Drill PVCs restored", "count", len(pvList))
+
+	return nil
+}
+
+func drillPVC(pv *corev1.PersistentVolume, labels map[string]string) *corev1.PersistentVolumeClaim {
+	storageClassName := pv.Spec.StorageClassName
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pv.Spec.ClaimRef.Name,
+			Namespace: pv.Spec.ClaimRef.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: pv.Spec.AccessModes,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: pv.Spec.Capacity[corev1.ResourceStorage],
+				},
+			},
+			VolumeName:       pv.Name,
+			StorageClassName: &storageClassName,
+			VolumeMode:       pv.Spec.VolumeMode,
+		},
+	}
+}
+
+// processAsDrill reconciles the current instance of VRG as a failover drill
+func (v *VRGInstance) processAsDrill() (ctrl.Result, error) {
+	v.log.Info("Entering processing VolumeReplicationGroup as drill", "source", v.sourceNamespaceName())
+
+	defer v.log.Info("Exiting processing VolumeReplicationGroup")
+
+	if err := v.addFinalizer(vrgFinalizerName); err != nil {
+		v.log.Info("Failed to add finalizer", "finalizer", vrgFinalizerName, "errorValue", err)
+
+		msg := "Failed to add finalizer to VolumeReplicationGroup"
+		setVRGDataErrorCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+
+		if err = v.updateVRGStatus(false); err != nil {
+			v.log.Error(err, "VRG Status update failed")
+		}
+
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	result := ctrl.Result{}
+	if err := v.restorePVs(&result); err != nil {
+		v.log.Info("Restoring PVs for drill failed", "Error", err.Error())
+
+		msg := fmt.Sprintf("Failed to restore PVs for drill (%v)", err.Error())
+		v.setVRGClusterDataRestoreErrorCondition(err, msg)
+
+		if err = v.updateVRGStatus(false); err != nil {
+			v.log.Error(err, "VRG Status update failed")
+
+			result.Requeue = true
+		}
+
+		return result, nil
+	}
+
+	if err := v.updateDrillDataReadyCondition(); err != nil {
+		v.log.Info("Drill PVCs status unknown", "Error", err.Error())
+
+		result.Requeue = true
+	}
+
+	if err := v.updateVRGStatus(false); err != nil {
+		result.Requeue = true
+	}
+
+	return result, nil
+}
+
+// updateDrillDataReadyCondition sets the DataReady condition once all the PVCs
+// restored by the drill are bound to their PVs and the verifier pod read them
+func (v *VRGInstance) updateDrillDataReadyCondition() error {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := v.reconciler.List(v.ctx, pvcList,
+		client.InNamespace(v.instance.Namespace),
+		client.MatchingLabels(v.drillLabels()),
+	); err != nil {
+		return fmt.Errorf("failed to list drill PVCs (%w)", err)
+	}
+
+	for idx := range pvcList.Items {
+		pvc := &pvcList.Items[idx]
+		if pvc.Status.Phase != corev1.ClaimBound {
+			msg := fmt.Sprintf("Drill PVC %s is not yet bound", pvc.Name)
+			setVRGDataProgressingCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+
+			return nil
+		}
+	}
+
+	return v.verifyDrillPVCs(pvcList.Items)
+}
+
+func (v *VRGInstance) drillVerifierPodName() string {
+	return v.instance.Name + "-drill-verifier"
+}
+
+// verifyDrillPVCs runs a pod that mounts the drill PVCs read-only and reads
+// each of them, and sets the DataReady condition from its outcome, so that a
+// drill succeeds only if its volumes are usable
+func (v *VRGInstance) verifyDrillPVCs(pvcs []corev1.PersistentVolumeClaim) error {
+	pod := &corev1.Pod{}
+
+	err := v.reconciler.Get(v.ctx, types.NamespacedName{
+		Namespace: v.instance.Namespace,
+		Name:      v.drillVerifierPodName(),
+	}, pod)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get drill verifier pod (%w)", err)
+		}
+
+		pod = v.drillVerifierPod(pvcs)
+		if err := v.reconciler.Create(v.ctx, pod); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create drill verifier pod (%w)", err)
+		}
+	}
+
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		msg := fmt.Sprintf("%d drill PVCs are bound, mounted and readable", len(pvcs))
+		setVRGAsPrimaryReadyCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+	case corev1.PodFailed:
+		msg := fmt.Sprintf("Drill PVCs are not readable: pod %s failed: %s", pod.Name, pod.Status.Message)
+		setVRGDataErrorCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+	default:
+		msg := fmt.Sprintf("Verifying that the drill PVCs are readable with pod %s", pod.Name)
+		setVRGDataProgressingCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+	}
+
+	return nil
+}
+
+// drillVerifierPod returns a pod that mounts each drill PVC read-only, and
+// lists the root of each file system, or reads the first block of each block
+// device
+func (v *VRGInstance) drillVerifierPod(pvcs []corev1.PersistentVolumeClaim) *corev1.Pod {
+	image := v.instance.Spec.Drill.VerifierImage
+	if image == "" {
+		image = drillVerifierImageDefault
+	}
+
+	container := corev1.Container{
+		Name:  "verifier",
+		Image: image,
+	}
+	volumes := make([]corev1.Volume, 0, len(pvcs))
+	script := "set -e"
+
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		name := fmt.Sprintf("drill-%d", idx)
+		path := drillVerifierMountPath + "/" + pvc.Name
+
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvc.Name,
+					ReadOnly:  true,
+				},
+			},
+		})
+
+		if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == corev1.PersistentVolumeBlock {
+			container.VolumeDevices = append(container.VolumeDevices,
+				corev1.VolumeDevice{Name: name, DevicePath: path})
+			script += fmt.Sprintf("; dd if=%q of=/dev/null bs=4096 count=1", path)
+
+			continue
+		}
+
+		container.VolumeMounts = append(container.VolumeMounts,
+			corev1.VolumeMount{Name: name, MountPath: path, ReadOnly: true})
+		script += fmt.Sprintf("; ls -A %q >/dev/null", path)
+	}
+
+	container.Command = []string{"/bin/sh", "-c", script}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      v.drillVerifierPodName(),
+			Namespace: v.instance.Namespace,
+			Labels:    v.drillLabels(),
+		},
+		Spec: corev1.PodSpec{
+			Containers:    []corev1.Container{container},
+			Volumes:       volumes,
+			RestartPolicy: corev1.RestartPolicyNever,
+		},
+	}
+}
+
+// processDrillForDeletion deletes the PVCs and PVs restored by a drill VRG and
+// removes its finalizer. The replicated volumes are retained.
+func (v *VRGInstance) processDrillForDeletion() (ctrl.Result, error) {
+	v.log.Info("Entering processing drill VolumeReplicationGroup for deletion")
+
+	defer v.log.Info("Exiting processing VolumeReplicationGroup")
+
+	if !containsString(v.instance.ObjectMeta.Finalizers, vrgFinalizerName) {
+		v.log.Info("Finalizer missing from resource", "finalizer", vrgFinalizerName)
+
+		return ctrl.Result{}, nil
+	}
+
+	result := ctrl.Result{}
+	if err := v.kubeObjectsProtectionDelete(&result); err != nil {
+		v.log.Info("Kube objects protection deletion failed", "error", err)
+
+		return result, err
+	}
+
+	labels := client.MatchingLabels(v.drillLabels())
+
+	if err := v.reconciler.DeleteAllOf(v.ctx, &corev1.Pod{},
+		client.InNamespace(v.instance.Namespace), labels); err != nil {
+		v.log.Info("Failed to delete drill verifier pod", "errorValue", err)
+
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := v.reconciler.DeleteAllOf(v.ctx, &corev1.PersistentVolumeClaim{},
+		client.InNamespace(v.instance.Namespace), labels); err != nil {
+		v.log.Info("Failed to delete drill PVCs", "errorValue", err)
+
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := v.reconciler.DeleteAllOf(v.ctx, &corev1.PersistentVolume{}, labels); err != nil {
+		v.log.Info("Failed to delete drill PVs", "errorValue", err)
+
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := v.removeFinalizer(vrgFinalizerName); err != nil {
+		v.log.Info("Failed to remove finalizer", "finalizer", vrgFinalizerName, "errorValue", err)
+
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeNormal,
+		rmnutil.EventReasonDeleteSuccess, "Deletion Success")
+
+	return ctrl.Result{}, nil
+}
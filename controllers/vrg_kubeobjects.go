@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -60,16 +62,57 @@ func kubeObjectsCaptureInterval(kubeObjectProtectionSpec *ramen.KubeObjectProtec
 	return kubeObjectProtectionSpec.CaptureInterval.Duration
 }
 
+func kubeObjectsCaptureRetentionCount(kubeObjectProtectionSpec *ramen.KubeObjectProtectionSpec) int64 {
+	if kubeObjectProtectionSpec.CaptureRetentionCount < ramen.KubeObjectProtectionCaptureRetentionCountDefault {
+		return ramen.KubeObjectProtectionCaptureRetentionCountDefault
+	}
+
+	return int64(kubeObjectProtectionSpec.CaptureRetentionCount)
+}
+
+// kubeObjectsCapturesRetain returns the given count of the latest of the given
+// captures, which are ordered from oldest to latest
+func kubeObjectsCapturesRetain(captures []ramen.KubeObjectsCaptureIdentifier, count int64,
+) []ramen.KubeObjectsCaptureIdentifier {
+	if int64(len(captures)) <= count {
+		return captures
+	}
+
+	return append([]ramen.KubeObjectsCaptureIdentifier{}, captures[int64(len(captures))-count:]...)
+}
+
+// kubeObjectsCaptureNumberNext returns the number of the next capture, which
+// exceeds those of all previous captures, so that a number identifies a
+// single capture
+func kubeObjectsCaptureNumberNext(status *ramen.KubeObjectProtectionStatus) int64 {
+	number := int64(0)
+	if status.CaptureToRecoverFrom != nil {
+		number = status.CaptureToRecoverFrom.Number
+	}
+
+	for _, capture := range status.Captures {
+		if capture.Number > number {
+			number = capture.Number
+		}
+	}
+
+	return number + 1
+}
+
 func timeSincePreviousAndUntilNext(previousTime time.Time, interval time.Duration) (time.Duration, time.Duration) {
 	since := time.Since(previousTime)
 
 	return since, interval - since
 }
 
+func kubeObjectsCapturesPathName(namespaceName, vrgName string) string {
+	return s3PathNamePrefix(namespaceName, vrgName) + "kube-objects/"
+}
+
 func kubeObjectsCapturePathNameAndNamePrefix(namespaceName, vrgName string, captureNumber int64) (string, string) {
 	number := strconv.FormatInt(captureNumber, 10)
 
-	return s3PathNamePrefix(namespaceName, vrgName) + "kube-objects/" + number + "/",
+	return kubeObjectsCapturesPathName(namespaceName, vrgName) + number + "/",
 		// TODO fix: may exceed name capacity
 		namespaceName + "--" + vrgName + "--" + number
 }
@@ -78,6 +121,17 @@ func kubeObjectsCaptureName(prefix, groupName, s3ProfileName string) string {
 	return prefix + "--" + groupName + "--" + s3ProfileName
 }
 
+// kubeObjectsNamespaceGroupName returns the name of a group's capture of one of
+// the VRG's protected namespaces. That of the VRG's own namespace, the first,
+// is the group's name, as it was before other namespaces could be protected.
+func kubeObjectsNamespaceGroupName(groupName string, namespaceNumber int, namespaceName string) string {
+	if namespaceNumber == 0 {
+		return groupName
+	}
+
+	return groupName + "--" + namespaceName
+}
+
 func kubeObjectsRecoverNamePrefix(vrgNamespaceName, vrgName string) string {
 	return vrgNamespaceName + "--" + vrgName
 }
@@ -101,7 +155,11 @@ func (v *VRGInstance) kubeObjectsProtect(result *ctrl.Result) {
 	}
 
 	if v.instance.Spec.KubeObjectProtection != nil {
-		v.kubeObjectsCaptureStartOrResumeOrDelay(result, s3StoreAccessors)
+		if kubeObjectsCaptureStoreAccessible(v.reconciler.kubeObjects, s3StoreAccessors) {
+			v.kubeObjectsCaptureStartOrResumeOrDelay(result, s3StoreAccessors)
+		} else {
+			v.log.Info("Kube objects capture skipped; no supported object store profile")
+		}
 	}
 
 	v.vrgObjectProtect(result, s3StoreAccessors)
@@ -109,10 +167,7 @@ func (v *VRGInstance) kubeObjectsProtect(result *ctrl.Result) {
 
 type s3StoreAccessor struct {
 	ObjectStorer
-	url                       string
-	bucketName                string
-	regionName                string
-	veleroNamespaceSecretName string
+	ramen.S3StoreProfile
 }
 
 func (v *VRGInstance) s3StoreAccessorsGet() []s3StoreAccessor {
@@ -132,18 +187,26 @@ func (v *VRGInstance) s3StoreAccessorsGet() []s3StoreAccessor {
 			return nil
 		}
 
-		s3StoreAccessors[i] = s3StoreAccessor{
-			objectStorer,
-			s3StoreProfile.S3CompatibleEndpoint,
-			s3StoreProfile.S3Bucket,
-			s3StoreProfile.S3Region,
-			s3StoreProfile.VeleroNamespaceSecretName,
-		}
+		s3StoreAccessors[i] = s3StoreAccessor{objectStorer, s3StoreProfile}
 	}
 
 	return s3StoreAccessors
 }
 
+// kubeObjectsCaptureStoreAccessible returns whether any of the stores is of a
+// type that the kube objects engine captures to and recovers from
+func kubeObjectsCaptureStoreAccessible(
+	kubeObjects KubeObjectsRequestsManager, s3StoreAccessors []s3StoreAccessor,
+) bool {
+	for _, s3StoreAccessor := range s3StoreAccessors {
+		if kubeObjects.ObjectStoreTypeSupported(objectStoreType(s3StoreAccessor.S3StoreProfile)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (v *VRGInstance) kubeObjectsCaptureStartOrResumeOrDelay(result *ctrl.Result, s3StoreAccessors []s3StoreAccessor) {
 	veleroNamespaceName := v.veleroNamespaceName()
 	vrg := v.instance
@@ -157,7 +220,8 @@ func (v *VRGInstance) kubeObjectsCaptureStartOrResumeOrDelay(result *ctrl.Result
 		captureToRecoverFrom = &ramen.KubeObjectsCaptureIdentifier{}
 	}
 
-	number := 1 - captureToRecoverFrom.Number
+	retentionCount := kubeObjectsCaptureRetentionCount(vrg.Spec.KubeObjectProtection)
+	number := kubeObjectsCaptureNumberNext(status)
 	pathName, namePrefix := kubeObjectsCapturePathNameAndNamePrefix(vrg.Namespace, vrg.Name, number)
 	labels := ownerLabels(vrg.Namespace, vrg.Name)
 	captureStartOrResume := func() {
@@ -165,7 +229,8 @@ func (v *VRGInstance) kubeObjectsCaptureStartOrResumeOrDelay(result *ctrl.Result
 			veleroNamespaceName, interval, labels)
 	}
 
-	requests, err := KubeObjectsCaptureRequestsGet(v.ctx, v.reconciler.APIReader, veleroNamespaceName, labels)
+	requests, err := v.reconciler.kubeObjects.ProtectRequestsGet(
+		v.ctx, v.reconciler.APIReader, veleroNamespaceName, labels)
 	if err != nil {
 		v.log.Error(err, "Kube objects capture in-progress query error", "number", number)
 
@@ -188,7 +253,22 @@ func (v *VRGInstance) kubeObjectsCaptureStartOrResumeOrDelay(result *ctrl.Result
 		return
 	}
 
-	if v.kubeObjectsCaptureDelete(result, s3StoreAccessors, number, pathName); result.Requeue {
+	// The oldest captures are removed from the status, and so from the VRG
+	// object uploaded with it, before their objects are deleted, by a later
+	// reconcile, so that neither refers to a deleted capture
+	if captures := kubeObjectsCapturesRetain(status.Captures, retentionCount-1); len(captures) < len(status.Captures) {
+		v.log.Info("Kube objects captures no longer retained", "retained", captures)
+		status.Captures = captures
+		result.Requeue = true
+
+		return
+	}
+
+	if v.kubeObjectsCapturesDelete(result, s3StoreAccessors, status.Captures); result.Requeue {
+		return
+	}
+
+	if !v.kubeObjectsCaptureHooksPre(result) {
 		return
 	}
 
@@ -196,22 +276,48 @@ func (v *VRGInstance) kubeObjectsCaptureStartOrResumeOrDelay(result *ctrl.Result
 	captureStartOrResume()
 }
 
-func (v *VRGInstance) kubeObjectsCaptureDelete(
-	result *ctrl.Result, s3StoreAccessors []s3StoreAccessor, captureNumber int64, pathName string,
+// kubeObjectsCapturesDelete deletes the objects of the captures other than the
+// given retained ones, such as those no longer retained and those that did not
+// complete
+func (v *VRGInstance) kubeObjectsCapturesDelete(
+	result *ctrl.Result, s3StoreAccessors []s3StoreAccessor, retainedCaptures []ramen.KubeObjectsCaptureIdentifier,
 ) {
 	vrg := v.instance
-	pathName += KubeObjectsCapturesPath
+	capturesPathName := kubeObjectsCapturesPathName(vrg.Namespace, vrg.Name)
+	retainedPathNames := make(map[string]bool, len(retainedCaptures))
+
+	for _, capture := range retainedCaptures {
+		pathName, _ := kubeObjectsCapturePathNameAndNamePrefix(vrg.Namespace, vrg.Name, capture.Number)
+		retainedPathNames[pathName] = true
+	}
 
 	// current s3 profiles may differ from those at capture time
 	for i, s3ProfileName := range vrg.Spec.S3Profiles {
 		objectStore := s3StoreAccessors[i].ObjectStorer
-		if err := objectStore.DeleteObjects(pathName); err != nil {
-			v.log.Error(err, "Kube objects capture s3 objects delete error", "number", captureNumber, "profile", s3ProfileName)
+
+		pathNames, err := objectStore.ListKeyPrefixes(capturesPathName, "", 0)
+		if err != nil {
+			v.log.Error(err, "Kube objects captures list error", "profile", s3ProfileName)
 
 			result.Requeue = true
 
 			return
 		}
+
+		for _, pathName := range pathNames {
+			if retainedPathNames[pathName] {
+				continue
+			}
+
+			if err := objectStore.DeleteObjects(pathName); err != nil {
+				v.log.Error(err, "Kube objects capture s3 objects delete error", "path", pathName,
+					"profile", s3ProfileName)
+
+				result.Requeue = true
+
+				return
+			}
+		}
 	}
 }
 
@@ -221,49 +327,56 @@ func (v *VRGInstance) kubeObjectsCaptureStartOrResume(
 ) {
 	vrg := v.instance
 	groups := v.getCaptureGroups()
-	requests := make([]KubeObjectsProtectRequest, len(groups)*len(vrg.Spec.S3Profiles))
+	namespaceNames := vrgNamespaceNames(vrg)
+	requests := make([]KubeObjectsProtectRequest, len(groups)*len(namespaceNames)*len(vrg.Spec.S3Profiles))
 	requestsProcessedCount := 0
 	requestsCompletedCount := 0
 
 	for groupNumber, captureGroup := range groups {
-		for i, s3ProfileName := range vrg.Spec.S3Profiles {
-			s3StoreAccessor := s3StoreAccessors[i]
-			request, err := KubeObjectsProtect(
-				v.ctx, v.reconciler.Client, v.reconciler.APIReader, v.log,
-				s3StoreAccessor.url,
-				s3StoreAccessor.bucketName,
-				s3StoreAccessor.regionName,
-				pathName,
-				s3StoreAccessor.veleroNamespaceSecretName,
-				vrg.Namespace,
-				captureGroup.KubeObjectsSpec,
-				veleroNamespaceName, kubeObjectsCaptureName(namePrefix, captureGroup.Name, s3ProfileName),
-				labels)
-			requests[requestsProcessedCount] = request
-			requestsProcessedCount++
+		for namespaceNumber, namespaceName := range namespaceNames {
+			groupName := kubeObjectsNamespaceGroupName(captureGroup.Name, namespaceNumber, namespaceName)
 
-			if err == nil {
-				v.log.Info("Kube objects group captured", "number", captureNumber,
-					"group", groupNumber, "name", captureGroup.Name, "profile", s3ProfileName,
-					"start", request.StartTime(), "end", request.EndTime())
-				requestsCompletedCount++
+			for i, s3ProfileName := range vrg.Spec.S3Profiles {
+				s3StoreAccessor := s3StoreAccessors[i]
+				if !v.reconciler.kubeObjects.ObjectStoreTypeSupported(objectStoreType(s3StoreAccessor.S3StoreProfile)) {
+					continue
+				}
 
-				continue
-			}
+				request, err := v.reconciler.kubeObjects.ProtectRequestCreate(
+					v.ctx, v.reconciler.Client, v.reconciler.APIReader, v.log,
+					s3StoreAccessor.ObjectStorer,
+					s3StoreAccessor.S3StoreProfile,
+					pathName,
+					namespaceName,
+					captureGroup.KubeObjectsSpec,
+					veleroNamespaceName, kubeObjectsCaptureName(namePrefix, groupName, s3ProfileName),
+					labels)
+				requests[requestsProcessedCount] = request
+				requestsProcessedCount++
+
+				if err == nil {
+					v.log.Info("Kube objects group captured", "number", captureNumber,
+						"group", groupNumber, "name", groupName, "namespace", namespaceName, "profile", s3ProfileName,
+						"start", request.StartTime(), "end", request.EndTime())
+					requestsCompletedCount++
+
+					continue
+				}
 
-			if errors.Is(err, KubeObjectsRequestProcessingError{}) {
-				v.log.Info("Kube objects group capturing", "number", captureNumber, "group", groupNumber,
-					"name", captureGroup.Name, "profile", s3ProfileName, "state", err.Error())
+				if errors.Is(err, KubeObjectsRequestProcessingError{}) {
+					v.log.Info("Kube objects group capturing", "number", captureNumber, "group", groupNumber,
+						"name", groupName, "namespace", namespaceName, "profile", s3ProfileName, "state", err.Error())
 
-				continue
-			}
+					continue
+				}
 
-			v.log.Error(err, "Kube objects group capture error", "number", captureNumber,
-				"group", groupNumber, "name", captureGroup.Name, "profile", s3ProfileName)
+				v.log.Error(err, "Kube objects group capture error", "number", captureNumber,
+					"group", groupNumber, "name", groupName, "namespace", namespaceName, "profile", s3ProfileName)
 
-			result.Requeue = true
+				result.Requeue = true
 
-			return
+				return
+			}
 		}
 
 		if requestsCompletedCount < requestsProcessedCount {
@@ -284,7 +397,9 @@ func (v *VRGInstance) kubeObjectsCaptureComplete(
 	vrg := v.instance
 	status := &vrg.Status.KubeObjectProtection
 
-	if err := KubeObjectsCaptureRequestsDelete(v.ctx, v.reconciler.Client, veleroNamespaceName, labels); err != nil {
+	if err := v.reconciler.kubeObjects.ProtectRequestsDelete(
+		v.ctx, v.reconciler.Client, veleroNamespaceName, labels,
+	); err != nil {
 		v.log.Error(err, "Kube objects capture requests delete error", "number", captureNumber)
 
 		result.Requeue = true
@@ -292,9 +407,12 @@ func (v *VRGInstance) kubeObjectsCaptureComplete(
 		return
 	}
 
+	v.kubeObjectsCaptureHooksPost()
+
 	status.CaptureToRecoverFrom = &ramen.KubeObjectsCaptureIdentifier{
 		Number: captureNumber, StartTime: startTime,
 	}
+	status.Captures = append(status.Captures, *status.CaptureToRecoverFrom)
 
 	duration, delay := timeSincePreviousAndUntilNext(status.CaptureToRecoverFrom.StartTime.Time, interval)
 	if delay <= 0 {
@@ -361,7 +479,12 @@ func (v *VRGInstance) kubeObjectsRecover(result *ctrl.Result,
 		return nil
 	}
 
-	sourceVrgNamespaceName := vrg.Namespace
+	if storeType := objectStoreType(s3StoreProfile); !v.reconciler.kubeObjects.ObjectStoreTypeSupported(storeType) {
+		return fmt.Errorf("kube objects engine does not support the %s object store of profile %s, "+
+			"kube objects cannot be recovered from it", storeType, s3ProfileName)
+	}
+
+	sourceVrgNamespaceName := v.sourceNamespaceName()
 	sourceVrgName := vrg.Name
 	sourcePathNamePrefix := s3PathNamePrefix(sourceVrgNamespaceName, sourceVrgName)
 
@@ -379,24 +502,40 @@ func (v *VRGInstance) kubeObjectsRecover(result *ctrl.Result,
 		return nil
 	}
 
+	if number := spec.RecoverFromCaptureNumber; number != nil {
+		capture = kubeObjectsCaptureFind(sourceVrg.Status.KubeObjectProtection.Captures, *number)
+		if capture == nil {
+			return fmt.Errorf("kube objects capture %d to recover from not found in profile %s, retained captures: %v",
+				*number, s3ProfileName, sourceVrg.Status.KubeObjectProtection.Captures)
+		}
+
+		v.log.Info("Kube objects capture to recover from selected", "capture", capture)
+	}
+
 	vrg.Status.KubeObjectProtection.CaptureToRecoverFrom = capture
+	vrg.Status.KubeObjectProtection.Captures = sourceVrg.Status.KubeObjectProtection.Captures
 
 	return v.kubeObjectsRecoveryStartOrResume(
 		result,
 		s3ProfileName,
-		s3StoreAccessor{
-			objectStorer,
-			s3StoreProfile.S3CompatibleEndpoint,
-			s3StoreProfile.S3Bucket,
-			s3StoreProfile.S3Region,
-			s3StoreProfile.VeleroNamespaceSecretName,
-		},
+		s3StoreAccessor{objectStorer, s3StoreProfile},
 		sourceVrgNamespaceName,
 		sourceVrgName,
 		capture,
 	)
 }
 
+func kubeObjectsCaptureFind(captures []ramen.KubeObjectsCaptureIdentifier, number int64,
+) *ramen.KubeObjectsCaptureIdentifier {
+	for i := range captures {
+		if captures[i].Number == number {
+			return &captures[i]
+		}
+	}
+
+	return nil
+}
+
 func (v *VRGInstance) kubeObjectsRecoveryStartOrResume(
 	result *ctrl.Result, s3ProfileName string, s3StoreAccessor s3StoreAccessor,
 	sourceVrgNamespaceName, sourceVrgName string,
@@ -409,43 +548,53 @@ func (v *VRGInstance) kubeObjectsRecoveryStartOrResume(
 	veleroNamespaceName := v.veleroNamespaceName()
 	labels := ownerLabels(vrg.Namespace, vrg.Name)
 	groups := v.getRecoverGroups()
-	requests := make([]KubeObjectsProtectRequest, len(groups))
+	namespaceNames := vrgNamespaceNames(vrg)
+	requests := make([]KubeObjectsProtectRequest, len(groups)*len(namespaceNames))
 
 	for groupNumber, recoverGroup := range groups {
-		request, err := KubeObjectsRecover(
-			v.ctx, v.reconciler.Client, v.reconciler.APIReader, v.log,
-			s3StoreAccessor.url,
-			s3StoreAccessor.bucketName,
-			s3StoreAccessor.regionName,
-			capturePathName,
-			s3StoreAccessor.veleroNamespaceSecretName,
-			sourceVrgNamespaceName, vrg.Namespace, recoverGroup.KubeObjectsSpec, veleroNamespaceName,
-			kubeObjectsCaptureName(captureNamePrefix, recoverGroup.BackupName, s3ProfileName),
-			kubeObjectsRecoverName(recoverNamePrefix, groupNumber), labels)
-		requests[groupNumber] = request
-
-		if err == nil {
-			v.log.Info("Kube objects group recovered", "number", capture.Number,
-				"group", groupNumber, "name", recoverGroup.BackupName, "profile", s3ProfileName,
-				"start", request.StartTime(), "end", request.EndTime())
-
-			continue
-		}
+		for namespaceNumber, namespaceName := range namespaceNames {
+			// the VRG's own namespace may be recovered from another one, other
+			// protected namespaces are recovered from the same ones
+			sourceNamespaceName := namespaceName
+			if namespaceNumber == 0 {
+				sourceNamespaceName = sourceVrgNamespaceName
+			}
 
-		if errors.Is(err, KubeObjectsRequestProcessingError{}) {
-			v.log.Info("Kube objects group recovering", "number", capture.Number,
-				"group", groupNumber, "name", recoverGroup.BackupName, "profile", s3ProfileName,
-				"state", err.Error())
+			groupName := kubeObjectsNamespaceGroupName(recoverGroup.BackupName, namespaceNumber, namespaceName)
+			requestNumber := groupNumber*len(namespaceNames) + namespaceNumber
+			request, err := v.reconciler.kubeObjects.RecoverRequestCreate(
+				v.ctx, v.reconciler.Client, v.reconciler.APIReader, v.log,
+				s3StoreAccessor.ObjectStorer,
+				s3StoreAccessor.S3StoreProfile,
+				capturePathName,
+				sourceNamespaceName, namespaceName, recoverGroup.KubeObjectsSpec, veleroNamespaceName,
+				kubeObjectsCaptureName(captureNamePrefix, groupName, s3ProfileName),
+				kubeObjectsRecoverName(recoverNamePrefix, requestNumber), labels)
+			requests[requestNumber] = request
 
-			return err
-		}
+			if err == nil {
+				v.log.Info("Kube objects group recovered", "number", capture.Number,
+					"group", groupNumber, "name", groupName, "namespace", namespaceName, "profile", s3ProfileName,
+					"start", request.StartTime(), "end", request.EndTime())
 
-		v.log.Error(err, "Kube objects group recover error", "number", capture.Number,
-			"group", groupNumber, "name", recoverGroup.BackupName, "profile", s3ProfileName)
+				continue
+			}
 
-		result.Requeue = true
+			if errors.Is(err, KubeObjectsRequestProcessingError{}) {
+				v.log.Info("Kube objects group recovering", "number", capture.Number,
+					"group", groupNumber, "name", groupName, "namespace", namespaceName, "profile", s3ProfileName,
+					"state", err.Error())
 
-		return err
+				return err
+			}
+
+			v.log.Error(err, "Kube objects group recover error", "number", capture.Number,
+				"group", groupNumber, "name", groupName, "namespace", namespaceName, "profile", s3ProfileName)
+
+			result.Requeue = true
+
+			return err
+		}
 	}
 
 	startTime := requests[0].StartTime()
@@ -459,7 +608,9 @@ func (v *VRGInstance) kubeObjectsRecoveryStartOrResume(
 func (v *VRGInstance) kubeObjectsRecoverRequestsDelete(
 	result *ctrl.Result, veleroNamespaceName string, labels map[string]string,
 ) error {
-	if err := KubeObjectsRecoverRequestsDelete(v.ctx, v.reconciler.Client, veleroNamespaceName, labels); err != nil {
+	if err := v.reconciler.kubeObjects.RecoverRequestsDelete(
+		v.ctx, v.reconciler.Client, veleroNamespaceName, labels,
+	); err != nil {
 		v.log.Error(err, "Kube objects recover requests delete error")
 
 		result.Requeue = true
@@ -515,6 +666,11 @@ func (v *VRGInstance) kubeObjectsProtectionDelete(result *ctrl.Result) error {
 
 	vrg := v.instance
 
+	if vrg.Spec.KubeObjectProtection != nil {
+		// resume an application quiesced for a capture that will not complete
+		v.kubeObjectsCaptureHooksPost()
+	}
+
 	return v.kubeObjectsRecoverRequestsDelete(
 		result,
 		v.veleroNamespaceName(),
@@ -522,8 +678,32 @@ func (v *VRGInstance) kubeObjectsProtectionDelete(result *ctrl.Result) error {
 	)
 }
 
-func kubeObjectsRequestsWatch(b *builder.Builder) *builder.Builder {
+// kubeObjectsRequestsManagerNew returns the requests manager of the kube
+// objects engine configured in the given ramen config
+func kubeObjectsRequestsManagerNew(mgr ctrl.Manager, ramenConfig *ramen.RamenConfig,
+) (KubeObjectsRequestsManager, error) {
+	switch engine := kubeObjectsEngineOrDefault(ramenConfig); engine {
+	case ramen.VeleroKubeObjectsEngine:
+		return veleroRequestsManager{}, nil
+	case ramen.NativeKubeObjectsEngine:
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+		if err != nil {
+			return nil, fmt.Errorf("kube objects engine %s discovery client create failed, %w", engine, err)
+		}
+
+		return KubeObjectsNativeRequestsManagerNew(discoveryClient), nil
+	default:
+		return nil, fmt.Errorf("kube objects engine %s unknown", engine)
+	}
+}
+
+func kubeObjectsRequestsWatch(b *builder.Builder, kubeObjects KubeObjectsRequestsManager) *builder.Builder {
 	watch := func(request KubeObjectsRequest) {
+		if request.Object() == nil {
+			// requests processed synchronously have no object to watch
+			return
+		}
+
 		src := &source.Kind{Type: request.Object()}
 		b.Watches(
 			src,
@@ -557,8 +737,8 @@ func kubeObjectsRequestsWatch(b *builder.Builder) *builder.Builder {
 		)
 	}
 
-	watch(KubeObjectsCaptureRequestNew())
-	watch(KubeObjectsRecoverRequestNew())
+	watch(kubeObjects.ProtectRequestNew())
+	watch(kubeObjects.RecoverRequestNew())
 
 	return b
 }
@@ -0,0 +1,656 @@
+/*
+Copyright 2022 The RamenDR authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;patch
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Capture hooks quiesce an application before a kube objects capture starts,
+// or before a final sync of its volumes is prepared or run, and resume it once
+// the capture completes, or once the final sync is no longer requested. Since
+// either may take several reconciles, the VRG status records that the pre
+// capture hooks ran, and for which, so that they are not run again while the
+// application is quiesced, and so that the post capture hooks run exactly once,
+// when neither needs the application quiesced any longer or when a pre capture
+// hook fails. Workloads scaled to zero are not waited on; the VRG is requeued
+// until their pods terminate. The replicas to scale a workload back to are
+// recorded in an annotation on it, so that it is scaled back even if the status
+// is lost.
+
+// captureHookReplicasAnnotation records, on a workload scaled to zero by a
+// capture hook, the replicas to scale it back to after the capture
+const captureHookReplicasAnnotation = "volumereplicationgroups.ramendr.openshift.io/capture-hook-replicas"
+
+const captureHookScalePollInterval = time.Second
+
+// podCommandExecutor runs commands in containers of pods
+type podCommandExecutor interface {
+	exec(ctx context.Context, namespaceName, podName, containerName string, command []string) error
+}
+
+type spdyPodCommandExecutor struct {
+	config     *rest.Config
+	restClient rest.Interface
+}
+
+func podCommandExecutorNew(config *rest.Config) (podCommandExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return spdyPodCommandExecutor{config: config, restClient: clientset.CoreV1().RESTClient()}, nil
+}
+
+func (e spdyPodCommandExecutor) exec(ctx context.Context, namespaceName, podName, containerName string,
+	command []string,
+) error {
+	request := e.restClient.Post().Resource("pods").Namespace(namespaceName).Name(podName).
+		SubResource("exec").VersionedParams(&corev1.PodExecOptions{
+		Container: containerName,
+		Command:   command,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.config, http.MethodPost, request.URL())
+	if err != nil {
+		return err
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- executor.Stream(remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr})
+	}()
+
+	select {
+	case err := <-errs:
+		if err != nil {
+			return fmt.Errorf("%w, stderr: %s", err, strings.TrimSpace(stderr.String()))
+		}
+
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("command did not complete in time, %w", ctx.Err())
+	}
+}
+
+func captureHookTimeout(hook *ramen.KubeObjectsCaptureHook) time.Duration {
+	if hook.Timeout == nil {
+		return ramen.KubeObjectsCaptureHookTimeoutDefault
+	}
+
+	return hook.Timeout.Duration
+}
+
+// kubeObjectsHooks returns the capture hooks of the VRG, unless kube objects
+// protection is disabled
+func (v *VRGInstance) kubeObjectsHooks() []ramen.KubeObjectsCaptureHook {
+	if v.instance.Spec.KubeObjectProtection == nil || v.kubeObjectProtectionDisabled() {
+		return nil
+	}
+
+	return v.instance.Spec.KubeObjectProtection.CaptureHooks
+}
+
+// kubeObjectsCaptureHooksPre quiesces the application for a capture and
+// returns whether the capture may start
+func (v *VRGInstance) kubeObjectsCaptureHooksPre(result *ctrl.Result) bool {
+	return v.kubeObjectsHooksPre(result, &v.instance.Status.KubeObjectProtection.CaptureHooksPostPending, "capture")
+}
+
+// kubeObjectsCaptureHooksPost resumes the application once a capture
+// completes, unless it is quiesced for a final sync too. Workloads scaled to
+// zero by a pre capture hook are scaled back even if the status that records
+// that the pre capture hooks ran was lost.
+func (v *VRGInstance) kubeObjectsCaptureHooksPost() {
+	status := &v.instance.Status.KubeObjectProtection
+
+	if status.CaptureHooksPostPending || status.FinalSyncHooksPostPending {
+		v.kubeObjectsHooksPost(&status.CaptureHooksPostPending)
+
+		return
+	}
+
+	hooks := v.kubeObjectsHooks()
+
+	for i := range hooks {
+		hook := &hooks[i]
+		if !hook.ScaleToZero {
+			continue
+		}
+
+		if err := v.kubeObjectsCaptureHookScaleBack(hook); err != nil {
+			v.log.Error(err, "Kube objects capture hook scale back error", "hook", hook.Name)
+		}
+	}
+}
+
+// kubeObjectsFinalSyncHooks quiesces the application, before a final sync of
+// its volumes is prepared or run, and resumes it once neither is requested.
+// It returns whether the final sync may proceed.
+func (v *VRGInstance) kubeObjectsFinalSyncHooks(result *ctrl.Result) bool {
+	status := &v.instance.Status.KubeObjectProtection
+
+	if v.instance.Spec.PrepareForFinalSync || v.instance.Spec.RunFinalSync {
+		return v.kubeObjectsHooksPre(result, &status.FinalSyncHooksPostPending, "final sync")
+	}
+
+	if status.FinalSyncHooksPostPending {
+		v.kubeObjectsHooksPost(&status.FinalSyncHooksPostPending)
+	}
+
+	return true
+}
+
+// kubeObjectsFinalSyncHooksForget forgets that the application was quiesced
+// for a final sync once the VRG is secondary, since the application then runs
+// on the peer cluster and is no longer to be resumed here
+func (v *VRGInstance) kubeObjectsFinalSyncHooksForget() {
+	v.instance.Status.KubeObjectProtection.FinalSyncHooksPostPending = false
+}
+
+// kubeObjectsHooksPre runs the pre capture hooks, unless they ran already for
+// a capture or a final sync, sets the given post pending flag, and returns
+// whether the hooks' workloads are scaled to zero. It does not wait for them,
+// but requeues until they are or until a hook times out.
+func (v *VRGInstance) kubeObjectsHooksPre(result *ctrl.Result, postPending *bool, purpose string) bool {
+	vrg := v.instance
+	hooks := v.kubeObjectsHooks()
+	status := &vrg.Status.KubeObjectProtection
+
+	if len(hooks) == 0 {
+		return true
+	}
+
+	if !status.CaptureHooksPostPending && !status.FinalSyncHooksPostPending {
+		if !v.kubeObjectsHooksPreRun(result, hooks, purpose) {
+			return false
+		}
+	}
+
+	*postPending = true
+
+	return v.kubeObjectsHooksScaledToZero(result, hooks, purpose)
+}
+
+func (v *VRGInstance) kubeObjectsHooksPreRun(result *ctrl.Result, hooks []ramen.KubeObjectsCaptureHook,
+	purpose string,
+) bool {
+	vrg := v.instance
+	status := &vrg.Status.KubeObjectProtection
+	failures := []string{}
+
+	for i := range hooks {
+		hook := &hooks[i]
+
+		if err := v.kubeObjectsCaptureHookRun(hook, true); err != nil {
+			v.log.Error(err, "Kube objects pre capture hook error", "hook", hook.Name)
+			failures = append(failures, fmt.Sprintf("%s: %v", hook.Name, err))
+
+			if hook.OnError == ramen.KubeObjectsCaptureHookErrorModeContinue {
+				continue
+			}
+
+			// undo what the hooks that ran, including the failed one, did
+			failures = append(failures, v.kubeObjectsCaptureHooksPostRun(hooks[:i+1])...)
+			setVRGCaptureHooksFailedCondition(&vrg.Status.Conditions, vrg.Generation,
+				VRGConditionReasonPreCaptureHookFailed,
+				fmt.Sprintf("Pre capture hooks failed, %s not started; %s", purpose, strings.Join(failures, "; ")))
+
+			result.Requeue = true
+
+			return false
+		}
+	}
+
+	now := metav1.Now()
+	status.HooksScaleToZeroStartTime = &now
+
+	if len(failures) > 0 {
+		setVRGCaptureHooksFailedCondition(&vrg.Status.Conditions, vrg.Generation,
+			VRGConditionReasonPreCaptureHookFailed,
+			fmt.Sprintf("Pre capture hooks failed, %s started regardless; %s", purpose, strings.Join(failures, "; ")))
+
+		return true
+	}
+
+	setVRGCaptureHooksProgressingCondition(&vrg.Status.Conditions, vrg.Generation,
+		VRGConditionReasonQuiescing, "Pre capture hooks ran, waiting for workloads to scale to zero")
+
+	return true
+}
+
+// kubeObjectsHooksScaledToZero returns whether the workloads that the pre
+// capture hooks scaled to zero have no pods left. Until they do, it requeues,
+// unless a hook's timeout elapsed, in which case the hook fails.
+func (v *VRGInstance) kubeObjectsHooksScaledToZero(result *ctrl.Result, hooks []ramen.KubeObjectsCaptureHook,
+	purpose string,
+) bool {
+	vrg := v.instance
+	status := &vrg.Status.KubeObjectProtection
+
+	if status.HooksScaleToZeroStartTime == nil {
+		return true
+	}
+
+	elapsed := time.Since(status.HooksScaleToZeroStartTime.Time)
+	failures := []string{}
+	waiting := false
+
+	for i := range hooks {
+		hook := &hooks[i]
+		if !hook.ScaleToZero {
+			continue
+		}
+
+		scaled, err := v.kubeObjectsCaptureHookScaledToZero(hook)
+		if err == nil && scaled {
+			continue
+		}
+
+		if err == nil && elapsed < captureHookTimeout(hook) {
+			waiting = true
+
+			continue
+		}
+
+		if err == nil {
+			err = fmt.Errorf("workloads not scaled to zero in %v", captureHookTimeout(hook))
+		}
+
+		v.log.Error(err, "Kube objects pre capture hook error", "hook", hook.Name)
+		failures = append(failures, fmt.Sprintf("%s: %v", hook.Name, err))
+
+		if hook.OnError == ramen.KubeObjectsCaptureHookErrorModeContinue {
+			continue
+		}
+
+		// undo what the hooks did
+		status.CaptureHooksPostPending = false
+		status.FinalSyncHooksPostPending = false
+		status.HooksScaleToZeroStartTime = nil
+		failures = append(failures, v.kubeObjectsCaptureHooksPostRun(hooks)...)
+		setVRGCaptureHooksFailedCondition(&vrg.Status.Conditions, vrg.Generation,
+			VRGConditionReasonPreCaptureHookFailed,
+			fmt.Sprintf("Pre capture hooks failed, %s not started; %s", purpose, strings.Join(failures, "; ")))
+
+		result.Requeue = true
+
+		return false
+	}
+
+	if waiting {
+		v.log.Info("Kube objects capture hooks waiting for scale to zero", "elapsed", elapsed)
+		delaySetIfLess(result, captureHookScalePollInterval, v.log)
+
+		return false
+	}
+
+	status.HooksScaleToZeroStartTime = nil
+
+	if len(failures) > 0 {
+		setVRGCaptureHooksFailedCondition(&vrg.Status.Conditions, vrg.Generation,
+			VRGConditionReasonPreCaptureHookFailed,
+			fmt.Sprintf("Pre capture hooks failed, %s started regardless; %s", purpose, strings.Join(failures, "; ")))
+
+		return true
+	}
+
+	// a pre capture hook failure remains reported
+	if condition := findCondition(vrg.Status.Conditions, VRGConditionTypeCaptureHooksSucceeded); condition != nil &&
+		condition.Status == metav1.ConditionFalse {
+		return true
+	}
+
+	setVRGCaptureHooksSucceededCondition(&vrg.Status.Conditions, vrg.Generation,
+		VRGConditionReasonQuiesced, "Pre capture hooks succeeded")
+
+	return true
+}
+
+// kubeObjectsHooksPost clears the given post pending flag and, unless the
+// application remains quiesced for a capture or a final sync, runs the post
+// capture hooks in reverse order. A failed hook does not prevent the others
+// from running.
+func (v *VRGInstance) kubeObjectsHooksPost(postPending *bool) {
+	vrg := v.instance
+	hooks := v.kubeObjectsHooks()
+	status := &vrg.Status.KubeObjectProtection
+
+	*postPending = false
+
+	if status.CaptureHooksPostPending || status.FinalSyncHooksPostPending {
+		return
+	}
+
+	status.HooksScaleToZeroStartTime = nil
+
+	if failures := v.kubeObjectsCaptureHooksPostRun(hooks); len(failures) > 0 {
+		setVRGCaptureHooksFailedCondition(&vrg.Status.Conditions, vrg.Generation,
+			VRGConditionReasonPostCaptureHookFailed, "Post capture hooks failed; "+strings.Join(failures, "; "))
+
+		return
+	}
+
+	// a pre capture hook failure remains reported until the next capture
+	if condition := findCondition(vrg.Status.Conditions, VRGConditionTypeCaptureHooksSucceeded); condition != nil &&
+		condition.Status == metav1.ConditionFalse {
+		return
+	}
+
+	setVRGCaptureHooksSucceededCondition(&vrg.Status.Conditions, vrg.Generation,
+		VRGConditionReasonResumed, "Capture hooks succeeded")
+}
+
+func (v *VRGInstance) kubeObjectsCaptureHooksPostRun(hooks []ramen.KubeObjectsCaptureHook) []string {
+	failures := []string{}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := &hooks[i]
+
+		if err := v.kubeObjectsCaptureHookRun(hook, false); err != nil {
+			v.log.Error(err, "Kube objects post capture hook error", "hook", hook.Name)
+			failures = append(failures, fmt.Sprintf("%s post: %v", hook.Name, err))
+		}
+	}
+
+	return failures
+}
+
+// kubeObjectsCaptureHookScaledToZero returns whether the workloads the given
+// hook scaled to zero have no pods left
+func (v *VRGInstance) kubeObjectsCaptureHookScaledToZero(hook *ramen.KubeObjectsCaptureHook) (bool, error) {
+	ctx, cancel := context.WithTimeout(v.ctx, captureHookTimeout(hook))
+	defer cancel()
+
+	selector, err := metav1.LabelSelectorAsSelector(&hook.LabelSelector)
+	if err != nil {
+		return false, fmt.Errorf("label selector invalid, %w", err)
+	}
+
+	return v.captureHookScaledToZero(ctx, selector)
+}
+
+// kubeObjectsCaptureHookScaleBack scales back the workloads the given hook
+// scaled to zero
+func (v *VRGInstance) kubeObjectsCaptureHookScaleBack(hook *ramen.KubeObjectsCaptureHook) error {
+	ctx, cancel := context.WithTimeout(v.ctx, captureHookTimeout(hook))
+	defer cancel()
+
+	selector, err := metav1.LabelSelectorAsSelector(&hook.LabelSelector)
+	if err != nil {
+		return fmt.Errorf("label selector invalid, %w", err)
+	}
+
+	return v.captureHookScaleBack(ctx, selector)
+}
+
+// kubeObjectsCaptureHookRun runs the given hook's pre or post capture actions.
+// Before the capture, its command runs before its workloads are scaled to zero,
+// and after the capture, its workloads are scaled back before its command runs.
+func (v *VRGInstance) kubeObjectsCaptureHookRun(hook *ramen.KubeObjectsCaptureHook, pre bool) error {
+	ctx, cancel := context.WithTimeout(v.ctx, captureHookTimeout(hook))
+	defer cancel()
+
+	selector, err := metav1.LabelSelectorAsSelector(&hook.LabelSelector)
+	if err != nil {
+		return fmt.Errorf("label selector invalid, %w", err)
+	}
+
+	var command []string
+
+	if hook.Exec != nil {
+		command = hook.Exec.Post
+		if pre {
+			command = hook.Exec.Pre
+		}
+	}
+
+	if pre && len(command) > 0 {
+		if err := v.captureHookExec(ctx, hook, selector, command); err != nil {
+			return err
+		}
+	}
+
+	if hook.ScaleToZero {
+		if pre {
+			err = v.captureHookScaleToZero(ctx, selector)
+		} else {
+			err = v.captureHookScaleBack(ctx, selector)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if !pre && len(command) > 0 {
+		return v.captureHookExec(ctx, hook, selector, command)
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) captureHookExec(ctx context.Context, hook *ramen.KubeObjectsCaptureHook,
+	selector labels.Selector, command []string,
+) error {
+	for _, namespaceName := range vrgNamespaceNames(v.instance) {
+		pods := &corev1.PodList{}
+		if err := v.reconciler.APIReader.List(ctx, pods,
+			client.InNamespace(namespaceName),
+			client.MatchingLabelsSelector{Selector: selector},
+		); err != nil {
+			return fmt.Errorf("pods list in namespace %s failed, %w", namespaceName, err)
+		}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Status.Phase != corev1.PodRunning || len(pod.Spec.Containers) == 0 {
+				continue
+			}
+
+			containerName := hook.Exec.Container
+			if containerName == "" {
+				containerName = pod.Spec.Containers[0].Name
+			}
+
+			if err := v.reconciler.podExecutor.exec(ctx, pod.Namespace, pod.Name, containerName, command); err != nil {
+				return fmt.Errorf("command %v in pod %s/%s container %s failed, %w",
+					command, pod.Namespace, pod.Name, containerName, err)
+			}
+
+			v.log.Info("Kube objects capture hook command ran", "pod", pod.Namespace+"/"+pod.Name,
+				"container", containerName, "command", command)
+		}
+	}
+
+	return nil
+}
+
+// captureHookWorkload is a deployment or stateful set scaled by a capture hook
+type captureHookWorkload struct {
+	client.Object
+	replicas       **int32
+	statusReplicas int32
+}
+
+func (v *VRGInstance) captureHookWorkloadsGet(ctx context.Context, selector labels.Selector,
+) ([]captureHookWorkload, error) {
+	workloads := []captureHookWorkload{}
+
+	for _, namespaceName := range vrgNamespaceNames(v.instance) {
+		options := []client.ListOption{
+			client.InNamespace(namespaceName),
+			client.MatchingLabelsSelector{Selector: selector},
+		}
+
+		deployments := &appsv1.DeploymentList{}
+		if err := v.reconciler.APIReader.List(ctx, deployments, options...); err != nil {
+			return nil, fmt.Errorf("deployments list in namespace %s failed, %w", namespaceName, err)
+		}
+
+		statefulSets := &appsv1.StatefulSetList{}
+		if err := v.reconciler.APIReader.List(ctx, statefulSets, options...); err != nil {
+			return nil, fmt.Errorf("stateful sets list in namespace %s failed, %w", namespaceName, err)
+		}
+
+		for i := range deployments.Items {
+			deployment := &deployments.Items[i]
+			workloads = append(workloads,
+				captureHookWorkload{deployment, &deployment.Spec.Replicas, deployment.Status.Replicas})
+		}
+
+		for i := range statefulSets.Items {
+			statefulSet := &statefulSets.Items[i]
+			workloads = append(workloads,
+				captureHookWorkload{statefulSet, &statefulSet.Spec.Replicas, statefulSet.Status.Replicas})
+		}
+	}
+
+	return workloads, nil
+}
+
+// captureHookScaleToZero scales the selected workloads to zero, recording
+// their replicas in an annotation on each of them
+func (v *VRGInstance) captureHookScaleToZero(ctx context.Context, selector labels.Selector) error {
+	workloads, err := v.captureHookWorkloadsGet(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	for _, workload := range workloads {
+		// scaled to zero by a prior attempt
+		if _, ok := workload.GetAnnotations()[captureHookReplicasAnnotation]; ok {
+			continue
+		}
+
+		replicas := int32(1)
+		if *workload.replicas != nil {
+			replicas = **workload.replicas
+		}
+
+		if err := v.captureHookWorkloadScale(ctx, workload, 0, func(annotations map[string]string) {
+			annotations[captureHookReplicasAnnotation] = strconv.FormatInt(int64(replicas), 10)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// captureHookScaledToZero returns whether the selected workloads that were
+// scaled to zero have no pods left
+func (v *VRGInstance) captureHookScaledToZero(ctx context.Context, selector labels.Selector) (bool, error) {
+	workloads, err := v.captureHookWorkloadsGet(ctx, selector)
+	if err != nil {
+		return false, err
+	}
+
+	for _, workload := range workloads {
+		if _, ok := workload.GetAnnotations()[captureHookReplicasAnnotation]; !ok {
+			continue
+		}
+
+		if workload.statusReplicas != 0 {
+			v.log.Info("Kube objects capture hook waiting for scale to zero",
+				"name", workload.GetName(), "replicas", workload.statusReplicas)
+
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// captureHookScaleBack scales the selected workloads that were scaled to zero
+// back to their recorded replicas
+func (v *VRGInstance) captureHookScaleBack(ctx context.Context, selector labels.Selector) error {
+	workloads, err := v.captureHookWorkloadsGet(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	for _, workload := range workloads {
+		value, ok := workload.GetAnnotations()[captureHookReplicasAnnotation]
+		if !ok {
+			continue
+		}
+
+		replicas, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("%s annotation %s of %s invalid, %w",
+				captureHookReplicasAnnotation, value, workload.GetName(), err)
+		}
+
+		if err := v.captureHookWorkloadScale(ctx, workload, int32(replicas), func(annotations map[string]string) {
+			delete(annotations, captureHookReplicasAnnotation)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) captureHookWorkloadScale(ctx context.Context, workload captureHookWorkload,
+	replicas int32, annotationsUpdate func(map[string]string),
+) error {
+	patch := client.MergeFrom(workload.DeepCopyObject().(client.Object))
+
+	annotations := workload.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotationsUpdate(annotations)
+	workload.SetAnnotations(annotations)
+	*workload.replicas = &replicas
+
+	if err := v.reconciler.Client.Patch(ctx, workload.Object, patch); err != nil {
+		return fmt.Errorf("%s scale to %d failed, %w", workload.GetName(), replicas, err)
+	}
+
+	v.log.Info("Kube objects capture hook scaled", "name", workload.GetName(), "replicas", replicas)
+
+	return nil
+}
@@ -97,8 +97,6 @@ func (v *VRGInstance) reconcileVolRepsAsPrimary(requeue *bool) {
 
 		log.Info("Successfully processed VolumeReplication for PersistentVolumeClaim")
 	}
-
-	v.updateFinalSyncStatus()
 }
 
 // reconcileVolRepsAsSecondary reconciles VolumeReplication resources for the VRG as secondary
@@ -175,7 +173,7 @@ func (v *VRGInstance) isPVCReadyForSecondary(pvc *corev1.PersistentVolumeClaim,
 		log.Info("VolumeReplication cannot become Secondary, as its PersistentVolumeClaim is not marked for deletion")
 
 		msg := "PVC not being deleted. Not ready to become Secondary"
-		v.updatePVCDataReadyCondition(pvc.Name, VRGConditionReasonProgressing, msg)
+		v.updatePVCDataReadyCondition(pvc.Namespace, pvc.Name, VRGConditionReasonProgressing, msg)
 
 		return !ready
 	}
@@ -185,7 +183,7 @@ func (v *VRGInstance) isPVCReadyForSecondary(pvc *corev1.PersistentVolumeClaim,
 		log.Info("VolumeReplication cannot become Secondary, as its PersistentVolumeClaim is still in use")
 
 		msg := "PVC still in use"
-		v.updatePVCDataReadyCondition(pvc.Name, VRGConditionReasonProgressing, msg)
+		v.updatePVCDataReadyCondition(pvc.Namespace, pvc.Name, VRGConditionReasonProgressing, msg)
 
 		return !ready
 	}
@@ -217,7 +215,7 @@ func (v *VRGInstance) preparePVCForVRProtection(pvc *corev1.PersistentVolumeClai
 		// Since pvc is skipped, mark the condition for the PVC as progressing. Even for
 		// deletion this applies where if the VR protection finalizer is absent for pvc and
 		// it is being deleted.
-		v.updatePVCDataReadyCondition(pvc.Name, VRGConditionReasonProgressing, msg)
+		v.updatePVCDataReadyCondition(pvc.Namespace, pvc.Name, VRGConditionReasonProgressing, msg)
 
 		return !requeue, skip
 	}
@@ -236,7 +234,7 @@ func (v *VRGInstance) protectPVC(pvc *corev1.PersistentVolumeClaim,
 		log.Info("Requeuing, as adding PersistentVolumeClaim finalizer failed", "errorValue", err)
 
 		msg := "Failed to add Protected Finalizer to PVC"
-		v.updatePVCDataReadyCondition(pvc.Name, VRGConditionReasonError, msg)
+		v.updatePVCDataReadyCondition(pvc.Namespace, pvc.Name, VRGConditionReasonError, msg)
 
 		return requeue, !skip
 	}
@@ -245,7 +243,7 @@ func (v *VRGInstance) protectPVC(pvc *corev1.PersistentVolumeClaim,
 		log.Info("Requeuing, as retaining PersistentVolume failed", "errorValue", err)
 
 		msg := "Failed to retain PV for PVC"
-		v.updatePVCDataReadyCondition(pvc.Name, VRGConditionReasonError, msg)
+		v.updatePVCDataReadyCondition(pvc.Namespace, pvc.Name, VRGConditionReasonError, msg)
 
 		return requeue, !skip
 	}
@@ -256,7 +254,7 @@ func (v *VRGInstance) protectPVC(pvc *corev1.PersistentVolumeClaim,
 			log.Info("Requeuing, as annotating PersistentVolumeClaim failed", "errorValue", err)
 
 			msg := "Failed to add protected annotatation to PVC"
-			v.updatePVCDataReadyCondition(pvc.Name, VRGConditionReasonError, msg)
+			v.updatePVCDataReadyCondition(pvc.Namespace, pvc.Name, VRGConditionReasonError, msg)
 
 			return requeue, !skip
 		}
@@ -440,7 +438,7 @@ func (v *VRGInstance) undoPVRetentionForPVC(pvc corev1.PersistentVolumeClaim, lo
 // Upload PV to the list of S3 stores in the VRG spec
 func (v *VRGInstance) uploadPVToS3Stores(pvc *corev1.PersistentVolumeClaim, log logr.Logger) (err error) {
 	// Find the ProtectedPVC of the given PVC in v.instance.Status.ProtectedPVCs[]
-	protectedPVC := v.findProtectedPVC(pvc.Name)
+	protectedPVC := v.findProtectedPVC(pvc.Namespace, pvc.Name)
 	// Find the ClusterDataProtected condition of the given PVC in ProtectedPVC.Conditions
 	clusterDataProtected := findCondition(protectedPVC.Conditions, VRGConditionTypeClusterDataProtected)
 
@@ -455,7 +453,7 @@ func (v *VRGInstance) uploadPVToS3Stores(pvc *corev1.PersistentVolumeClaim, log
 	numProfilesToUpload := len(v.instance.Spec.S3Profiles)
 	if numProfilesToUpload == 0 {
 		msg := "Error uploading PV cluster data because VRG spec has no S3 profiles"
-		v.updatePVCClusterDataProtectedCondition(pvc.Name,
+		v.updatePVCClusterDataProtectedCondition(pvc.Namespace, pvc.Name,
 			VRGConditionReasonUploadError, msg)
 		v.log.Info(msg)
 
@@ -474,14 +472,14 @@ func (v *VRGInstance) uploadPVToS3Stores(pvc *corev1.PersistentVolumeClaim, log
 		msg := fmt.Sprintf("Done uploading PV cluster data to %d of %d S3 profile(s): %v",
 			numProfilesUploaded, numProfilesToUpload, s3Profiles)
 		v.log.Info(msg)
-		v.updatePVCClusterDataProtectedCondition(pvc.Name,
+		v.updatePVCClusterDataProtectedCondition(pvc.Namespace, pvc.Name,
 			VRGConditionReasonUploaded, msg)
 	} else {
 		// Merely defensive as we don't expect to reach here
 		msg := fmt.Sprintf("Uploaded PV cluster data to only  %d of %d S3 profile(s): %v",
 			numProfilesUploaded, numProfilesToUpload, s3Profiles)
 		v.log.Info(msg)
-		v.updatePVCClusterDataProtectedCondition(pvc.Name,
+		v.updatePVCClusterDataProtectedCondition(pvc.Namespace, pvc.Name,
 			VRGConditionReasonUploadError, msg)
 	}
 
@@ -528,7 +526,7 @@ func (v *VRGInstance) PVUploadToObjectStores(pvc *corev1.PersistentVolumeClaim,
 	for _, s3ProfileName := range v.instance.Spec.S3Profiles {
 		err := v.PVUploadToObjectStore(s3ProfileName, pvc)
 		if err != nil {
-			v.updatePVCClusterDataProtectedCondition(pvc.Name, VRGConditionReasonUploadError, err.Error())
+			v.updatePVCClusterDataProtectedCondition(pvc.Namespace, pvc.Name, VRGConditionReasonUploadError, err.Error())
 			rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeWarning,
 				rmnutil.EventReasonPVUploadFailed, err.Error())
 
@@ -750,8 +748,8 @@ func (v *VRGInstance) processVRAsPrimary(vrNamespacedName types.NamespacedName,
 	// condition where both async and sync are enabled at the same time.
 	if v.instance.Spec.Sync.Mode == ramendrv1alpha1.SyncModeEnabled {
 		msg := "PVC in the VolumeReplicationGroup is ready for use"
-		v.updatePVCDataReadyCondition(vrNamespacedName.Name, VRGConditionReasonReady, msg)
-		v.updatePVCDataProtectedCondition(vrNamespacedName.Name, VRGConditionReasonReady, msg)
+		v.updatePVCDataReadyCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonReady, msg)
+		v.updatePVCDataProtectedCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonReady, msg)
 
 		return false, true, nil
 	}
@@ -779,8 +777,9 @@ func (v *VRGInstance) processVRAsSecondary(vrNamespacedName types.NamespacedName
 	// condition where both async and sync are enabled at the same time.
 	if v.instance.Spec.Sync.Mode == ramendrv1alpha1.SyncModeEnabled {
 		msg := "VolumeReplication resource for the pvc as Secondary is in sync with Primary"
-		v.updatePVCDataReadyCondition(vrNamespacedName.Name, VRGConditionReasonReplicated, msg)
-		v.updatePVCDataProtectedCondition(vrNamespacedName.Name, VRGConditionReasonDataProtected, msg)
+		v.updatePVCDataReadyCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonReplicated, msg)
+		v.updatePVCDataProtectedCondition(vrNamespacedName.Namespace, vrNamespacedName.Name,
+			VRGConditionReasonDataProtected, msg)
 
 		return false, true, nil
 	}
@@ -815,7 +814,7 @@ func (v *VRGInstance) createOrUpdateVR(vrNamespacedName types.NamespacedName,
 			// is it replicating or not. So, mark the protected pvc as error
 			// with condition.status as Unknown.
 			msg := "Failed to get VolumeReplication resource"
-			v.updatePVCDataReadyCondition(vrNamespacedName.Name, VRGConditionReasonErrorUnknown, msg)
+			v.updatePVCDataReadyCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonErrorUnknown, msg)
 
 			return requeue, false, fmt.Errorf("failed to get VolumeReplication resource"+
 				" (%s/%s) belonging to VolumeReplicationGroup (%s/%s), %w",
@@ -829,7 +828,7 @@ func (v *VRGInstance) createOrUpdateVR(vrNamespacedName types.NamespacedName,
 				rmnutil.EventReasonVRCreateFailed, err.Error())
 
 			msg := "Failed to create VolumeReplication resource"
-			v.updatePVCDataReadyCondition(vrNamespacedName.Name, VRGConditionReasonError, msg)
+			v.updatePVCDataReadyCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonError, msg)
 
 			return requeue, false, fmt.Errorf("failed to create VolumeReplication resource"+
 				" (%s/%s) belonging to VolumeReplicationGroup (%s/%s), %w",
@@ -838,7 +837,7 @@ func (v *VRGInstance) createOrUpdateVR(vrNamespacedName types.NamespacedName,
 
 		// Just created VolRep. Mark status.conditions as Progressing.
 		msg := "Created VolumeReplication resource for PVC"
-		v.updatePVCDataReadyCondition(vrNamespacedName.Name, VRGConditionReasonProgressing, msg)
+		v.updatePVCDataReadyCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonProgressing, msg)
 
 		return !requeue, false, nil
 	}
@@ -884,7 +883,7 @@ func (v *VRGInstance) updateVR(volRep *volrep.VolumeReplication,
 			rmnutil.EventReasonVRUpdateFailed, err.Error())
 
 		msg := "Failed to update VolumeReplication resource"
-		v.updatePVCDataReadyCondition(volRep.Name, VRGConditionReasonError, msg)
+		v.updatePVCDataReadyCondition(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg)
 
 		return requeue, false, fmt.Errorf("failed to update VolumeReplication resource"+
 			" (%s/%s) as %s, belonging to VolumeReplicationGroup (%s/%s), %w",
@@ -896,7 +895,7 @@ func (v *VRGInstance) updateVR(volRep *volrep.VolumeReplication,
 		volRep.Name, volRep.Namespace, state))
 	// Just updated the state of the VolRep. Mark it as progressing.
 	msg := "Updated VolumeReplication resource for PVC"
-	v.updatePVCDataReadyCondition(volRep.Name, VRGConditionReasonProgressing, msg)
+	v.updatePVCDataReadyCondition(volRep.Namespace, volRep.Name, VRGConditionReasonProgressing, msg)
 
 	return !requeue, false, nil
 }
@@ -927,10 +926,15 @@ func (v *VRGInstance) createVR(vrNamespacedName types.NamespacedName, state volr
 	}
 
 	// Let VRG receive notification for any changes to VolumeReplication CR
-	// created by VRG.
-	if err := ctrl.SetControllerReference(v.instance, volRep, v.reconciler.Scheme); err != nil {
-		return fmt.Errorf("failed to set owner reference to VolumeReplication resource (%s/%s), %w",
-			volRep.Name, volRep.Namespace, err)
+	// created by VRG. An owner must be in the same namespace as the resources
+	// it owns, so a VolumeReplication CR in another namespace is labeled instead.
+	if volRep.Namespace == v.instance.Namespace {
+		if err := ctrl.SetControllerReference(v.instance, volRep, v.reconciler.Scheme); err != nil {
+			return fmt.Errorf("failed to set owner reference to VolumeReplication resource (%s/%s), %w",
+				volRep.Name, volRep.Namespace, err)
+		}
+	} else {
+		volRep.Labels = ownerLabels(v.instance.Namespace, v.instance.Name)
 	}
 
 	v.log.Info("Creating VolumeReplication resource", "resource", volRep)
@@ -1054,7 +1058,7 @@ func (v *VRGInstance) checkVRStatus(volRep *volrep.VolumeReplication) bool {
 			volRep.Name, volRep.Namespace))
 
 		msg := "VolumeReplication generation not updated in status"
-		v.updatePVCDataReadyCondition(volRep.Name, VRGConditionReasonProgressing, msg)
+		v.updatePVCDataReadyCondition(volRep.Namespace, volRep.Name, VRGConditionReasonProgressing, msg)
 
 		return false
 	}
@@ -1069,7 +1073,7 @@ func (v *VRGInstance) checkVRStatus(volRep *volrep.VolumeReplication) bool {
 			string(v.instance.Spec.ReplicationState), v.instance.Name, v.instance.Namespace))
 
 		msg := "VolumeReplicationGroup state invalid"
-		v.updatePVCDataReadyCondition(volRep.Name, VRGConditionReasonError, msg)
+		v.updatePVCDataReadyCondition(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg)
 
 		return false
 	}
@@ -1099,10 +1103,10 @@ func (v *VRGInstance) validateVRStatus(volRep *volrep.VolumeReplication, state r
 	conditionMet, msg := isVRConditionMet(volRep, volrepController.ConditionCompleted, metav1.ConditionTrue)
 	if !conditionMet {
 		defaultMsg := fmt.Sprintf("VolumeReplication resource for pvc not %s to %s", action, stateString)
-		v.updatePVCDataReadyConditionHelper(volRep.Name, VRGConditionReasonError, msg,
+		v.updatePVCDataReadyConditionHelper(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg,
 			defaultMsg)
 
-		v.updatePVCDataProtectedConditionHelper(volRep.Name, VRGConditionReasonError, msg,
+		v.updatePVCDataProtectedConditionHelper(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg,
 			defaultMsg)
 
 		v.log.Info(fmt.Sprintf("%s (VolRep: %s/%s)", defaultMsg, volRep.Name, volRep.Namespace))
@@ -1113,9 +1117,13 @@ func (v *VRGInstance) validateVRStatus(volRep *volrep.VolumeReplication, state r
 	// if primary, all checks are completed
 	if state == ramendrv1alpha1.Primary {
 		msg = "PVC in the VolumeReplicationGroup is ready for use"
-		v.updatePVCDataReadyCondition(volRep.Name, VRGConditionReasonReady, msg)
+		v.updatePVCDataReadyCondition(volRep.Namespace, volRep.Name, VRGConditionReasonReady, msg)
+
+		v.updatePVCDataProtectedCondition(volRep.Namespace, volRep.Name, VRGConditionReasonReady, msg)
 
-		v.updatePVCDataProtectedCondition(volRep.Name, VRGConditionReasonReady, msg)
+		// VolumeReplication status reports when its last operation completed,
+		// rather than when the data last synced, so the sync time is unknown
+		v.updatePVCLastSyncTime(volRep.Namespace, volRep.Name, nil)
 
 		v.log.Info(fmt.Sprintf("VolumeReplication resource %s/%s is ready for use", volRep.Name,
 			volRep.Namespace))
@@ -1151,10 +1159,10 @@ func (v *VRGInstance) validateAdditionalVRStatusForSecondary(volRep *volrep.Volu
 
 	conditionMet, msg := isVRConditionMet(volRep, volrepController.ConditionDegraded, metav1.ConditionTrue)
 	if !conditionMet {
-		v.updatePVCDataProtectedConditionHelper(volRep.Name, VRGConditionReasonError, msg,
+		v.updatePVCDataProtectedConditionHelper(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg,
 			"VolumeReplication resource for pvc is not in Degraded condition while resyncing")
 
-		v.updatePVCDataReadyConditionHelper(volRep.Name, VRGConditionReasonError, msg,
+		v.updatePVCDataReadyConditionHelper(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg,
 			"VolumeReplication resource for pvc is not in Degraded condition while resyncing")
 
 		v.log.Info(fmt.Sprintf("VolumeReplication resource is not in degraded condition while"+
@@ -1164,8 +1172,8 @@ func (v *VRGInstance) validateAdditionalVRStatusForSecondary(volRep *volrep.Volu
 	}
 
 	msg = "VolumeReplication resource for the pvc is syncing as Secondary"
-	v.updatePVCDataReadyCondition(volRep.Name, VRGConditionReasonReplicating, msg)
-	v.updatePVCDataProtectedCondition(volRep.Name, VRGConditionReasonReplicating, msg)
+	v.updatePVCDataReadyCondition(volRep.Namespace, volRep.Name, VRGConditionReasonReplicating, msg)
+	v.updatePVCDataProtectedCondition(volRep.Namespace, volRep.Name, VRGConditionReasonReplicating, msg)
 
 	v.log.Info(fmt.Sprintf("VolumeReplication resource for the pvc is syncing as Secondary (%s/%s)",
 		volRep.Name, volRep.Namespace))
@@ -1178,10 +1186,10 @@ func (v *VRGInstance) checkResyncCompletionAsSecondary(volRep *volrep.VolumeRepl
 	conditionMet, msg := isVRConditionMet(volRep, volrepController.ConditionResyncing, metav1.ConditionFalse)
 	if !conditionMet {
 		defaultMsg := "VolumeReplication resource for pvc not syncing as Secondary"
-		v.updatePVCDataReadyConditionHelper(volRep.Name, VRGConditionReasonError, msg,
+		v.updatePVCDataReadyConditionHelper(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg,
 			defaultMsg)
 
-		v.updatePVCDataProtectedConditionHelper(volRep.Name, VRGConditionReasonError, msg,
+		v.updatePVCDataProtectedConditionHelper(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg,
 			defaultMsg)
 
 		v.log.Info(fmt.Sprintf("%s (VolRep: %s/%s)", defaultMsg, volRep.Name, volRep.Namespace))
@@ -1192,10 +1200,10 @@ func (v *VRGInstance) checkResyncCompletionAsSecondary(volRep *volrep.VolumeRepl
 	conditionMet, msg = isVRConditionMet(volRep, volrepController.ConditionDegraded, metav1.ConditionFalse)
 	if !conditionMet {
 		defaultMsg := "VolumeReplication resource for pvc is not syncing and is degraded as Secondary"
-		v.updatePVCDataReadyConditionHelper(volRep.Name, VRGConditionReasonError, msg,
+		v.updatePVCDataReadyConditionHelper(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg,
 			defaultMsg)
 
-		v.updatePVCDataProtectedConditionHelper(volRep.Name, VRGConditionReasonError, msg,
+		v.updatePVCDataProtectedConditionHelper(volRep.Namespace, volRep.Name, VRGConditionReasonError, msg,
 			defaultMsg)
 
 		v.log.Info(fmt.Sprintf("%s (VolRep: %s/%s)", defaultMsg, volRep.Name, volRep.Namespace))
@@ -1204,8 +1212,8 @@ func (v *VRGInstance) checkResyncCompletionAsSecondary(volRep *volrep.VolumeRepl
 	}
 
 	msg = "VolumeReplication resource for the pvc as Secondary is in sync with Primary"
-	v.updatePVCDataReadyCondition(volRep.Name, VRGConditionReasonReplicated, msg)
-	v.updatePVCDataProtectedCondition(volRep.Name, VRGConditionReasonDataProtected, msg)
+	v.updatePVCDataReadyCondition(volRep.Namespace, volRep.Name, VRGConditionReasonReplicated, msg)
+	v.updatePVCDataProtectedCondition(volRep.Namespace, volRep.Name, VRGConditionReasonDataProtected, msg)
 
 	v.log.Info(fmt.Sprintf("data sync completed as both degraded and resyncing are false for"+
 		" secondary VolRep (%s/%s)", volRep.Name, volRep.Namespace))
@@ -1246,24 +1254,24 @@ func isVRConditionMet(volRep *volrep.VolumeReplication,
 // Disabling unparam linter as currently every invokation of this
 // function sends reason as VRGConditionReasonError and the linter
 // complains about this function always receiving the same reason.
-func (v *VRGInstance) updatePVCDataReadyConditionHelper(name, reason, message, defaultMessage string) {
+func (v *VRGInstance) updatePVCDataReadyConditionHelper(namespace, name, reason, message, defaultMessage string) {
 	if message != "" {
-		v.updatePVCDataReadyCondition(name, reason, message)
+		v.updatePVCDataReadyCondition(namespace, name, reason, message)
 
 		return
 	}
 
-	v.updatePVCDataReadyCondition(name, reason, defaultMessage)
+	v.updatePVCDataReadyCondition(namespace, name, reason, defaultMessage)
 }
 
-func (v *VRGInstance) updatePVCDataReadyCondition(pvcName, reason, message string) {
-	if protectedPVC := v.findProtectedPVC(pvcName); protectedPVC != nil {
+func (v *VRGInstance) updatePVCDataReadyCondition(pvcNamespace, pvcName, reason, message string) {
+	if protectedPVC := v.findProtectedPVC(pvcNamespace, pvcName); protectedPVC != nil {
 		setPVCDataReadyCondition(protectedPVC, reason, message, v.instance.Generation)
 		// No need to append it as an already existing entry from the list is being modified.
 		return
 	}
 
-	protectedPVC := &ramendrv1alpha1.ProtectedPVC{Name: pvcName}
+	protectedPVC := &ramendrv1alpha1.ProtectedPVC{Name: pvcName, Namespace: pvcNamespace}
 	setPVCDataReadyCondition(protectedPVC, reason, message, v.instance.Generation)
 
 	// created a new instance. Add it to the list
@@ -1274,24 +1282,24 @@ func (v *VRGInstance) updatePVCDataReadyCondition(pvcName, reason, message strin
 // Disabling unparam linter as currently every invokation of this
 // function sends reason as VRGConditionReasonError and the linter
 // complains about this function always receiving the same reason.
-func (v *VRGInstance) updatePVCDataProtectedConditionHelper(name, reason, message, defaultMessage string) {
+func (v *VRGInstance) updatePVCDataProtectedConditionHelper(namespace, name, reason, message, defaultMessage string) {
 	if message != "" {
-		v.updatePVCDataProtectedCondition(name, reason, message)
+		v.updatePVCDataProtectedCondition(namespace, name, reason, message)
 
 		return
 	}
 
-	v.updatePVCDataProtectedCondition(name, reason, defaultMessage)
+	v.updatePVCDataProtectedCondition(namespace, name, reason, defaultMessage)
 }
 
-func (v *VRGInstance) updatePVCDataProtectedCondition(pvcName, reason, message string) {
-	if protectedPVC := v.findProtectedPVC(pvcName); protectedPVC != nil {
+func (v *VRGInstance) updatePVCDataProtectedCondition(pvcNamespace, pvcName, reason, message string) {
+	if protectedPVC := v.findProtectedPVC(pvcNamespace, pvcName); protectedPVC != nil {
 		setPVCDataProtectedCondition(protectedPVC, reason, message, v.instance.Generation)
 		// No need to append it as an already existing entry from the list is being modified.
 		return
 	}
 
-	protectedPVC := &ramendrv1alpha1.ProtectedPVC{Name: pvcName}
+	protectedPVC := &ramendrv1alpha1.ProtectedPVC{Name: pvcName, Namespace: pvcNamespace}
 	setPVCDataProtectedCondition(protectedPVC, reason, message, v.instance.Generation)
 
 	// created a new instance. Add it to the list
@@ -1348,14 +1356,14 @@ func setPVCDataProtectedCondition(protectedPVC *ramendrv1alpha1.ProtectedPVC, re
 	}
 }
 
-func (v *VRGInstance) updatePVCClusterDataProtectedCondition(pvcName, reason, message string) {
-	if protectedPVC := v.findProtectedPVC(pvcName); protectedPVC != nil {
+func (v *VRGInstance) updatePVCClusterDataProtectedCondition(pvcNamespace, pvcName, reason, message string) {
+	if protectedPVC := v.findProtectedPVC(pvcNamespace, pvcName); protectedPVC != nil {
 		setPVCClusterDataProtectedCondition(protectedPVC, reason, message, v.instance.Generation)
 		// No need to append it as an already existing entry from the list is being modified.
 		return
 	}
 
-	protectedPVC := &ramendrv1alpha1.ProtectedPVC{Name: pvcName}
+	protectedPVC := &ramendrv1alpha1.ProtectedPVC{Name: pvcName, Namespace: pvcNamespace}
 	setPVCClusterDataProtectedCondition(protectedPVC, reason, message, v.instance.Generation)
 	v.instance.Status.ProtectedPVCs = append(v.instance.Status.ProtectedPVCs, *protectedPVC)
 }
@@ -1483,11 +1491,22 @@ func (v *VRGInstance) removeFinalizerFromPVC(pvc *corev1.PersistentVolumeClaim,
 	return nil
 }
 
-// findProtectedPVC returns the &VRG.Status.ProtectedPVC[x] for the given pvcName
-func (v *VRGInstance) findProtectedPVC(pvcName string) *ramendrv1alpha1.ProtectedPVC {
+// updatePVCLastSyncTime records the time of the last completed replication of
+// the PVC data, or nil if unknown
+func (v *VRGInstance) updatePVCLastSyncTime(pvcNamespace, pvcName string, lastSyncTime *metav1.Time) {
+	protectedPVC := v.findProtectedPVC(pvcNamespace, pvcName)
+	if protectedPVC == nil {
+		return
+	}
+
+	protectedPVC.LastSyncTime = lastSyncTime
+}
+
+// findProtectedPVC returns the &VRG.Status.ProtectedPVC[x] for the given pvcNamespace and pvcName
+func (v *VRGInstance) findProtectedPVC(pvcNamespace, pvcName string) *ramendrv1alpha1.ProtectedPVC {
 	for index := range v.instance.Status.ProtectedPVCs {
 		protectedPVC := &v.instance.Status.ProtectedPVCs[index]
-		if protectedPVC.Name == pvcName {
+		if v.protectedPVCMatches(protectedPVC, pvcNamespace, pvcName) {
 			return protectedPVC
 		}
 	}
@@ -1495,6 +1514,19 @@ func (v *VRGInstance) findProtectedPVC(pvcName string) *ramendrv1alpha1.Protecte
 	return nil
 }
 
+// protectedPVCMatches returns whether the ProtectedPVC is of the given PVC. A
+// ProtectedPVC without a namespace is of a PVC in the VRG's namespace
+func (v *VRGInstance) protectedPVCMatches(protectedPVC *ramendrv1alpha1.ProtectedPVC,
+	pvcNamespace, pvcName string,
+) bool {
+	namespace := protectedPVC.Namespace
+	if namespace == "" {
+		namespace = v.instance.Namespace
+	}
+
+	return protectedPVC.Name == pvcName && namespace == pvcNamespace
+}
+
 // s3KeyPrefix returns the S3 key prefix of cluster data of this VRG.
 func (v *VRGInstance) s3KeyPrefix() string {
 	return S3KeyPrefix(v.namespacedName)
@@ -1523,10 +1555,25 @@ func (v *VRGInstance) restorePVsForVolRep(result *ctrl.Result) error {
 	return nil
 }
 
+// setVRGClusterDataRestoreErrorCondition sets the ClusterDataReady condition
+// false, with a reason that tells corrupted PV cluster data apart from other
+// restore errors
+func (v *VRGInstance) setVRGClusterDataRestoreErrorCondition(err error, msg string) {
+	if errors.As(err, &ObjectCorruptedError{}) {
+		setVRGClusterDataCorruptedCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+
+		return
+	}
+
+	setVRGClusterDataErrorCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+}
+
 func (v *VRGInstance) fetchAndRestorePV(result *ctrl.Result) error {
 	err := errors.New("s3Profiles empty")
 	NoS3 := false
 
+	var corruptedErr error
+
 	for _, s3ProfileName := range v.instance.Spec.S3Profiles {
 		if s3ProfileName == NoS3StoreAvailable {
 			v.log.Info("NoS3 available to fetch")
@@ -1551,10 +1598,14 @@ func (v *VRGInstance) fetchAndRestorePV(result *ctrl.Result) error {
 
 		var pvList []corev1.PersistentVolume
 
-		pvList, err = downloadPVs(objectStore, v.s3KeyPrefix())
+		pvList, err = v.downloadPVsToRestore(objectStore)
 		if err != nil {
 			v.log.Error(err, fmt.Sprintf("error fetching PV cluster data from S3 profile %s", s3ProfileName))
 
+			if errors.As(err, &ObjectCorruptedError{}) {
+				corruptedErr = err
+			}
+
 			continue
 		}
 
@@ -1574,6 +1625,12 @@ func (v *VRGInstance) fetchAndRestorePV(result *ctrl.Result) error {
 
 		v.log.Info(fmt.Sprintf("Restored %d PVs using profile %s", len(pvList), s3ProfileName))
 
+		if err = v.restoreDrillPVCs(pvList); err != nil {
+			v.log.Info("Failed to restore drill PVCs", "error", err.Error())
+
+			continue
+		}
+
 		return v.kubeObjectsRecover(result, s3ProfileName, s3StoreProfile, objectStore)
 	}
 
@@ -1583,6 +1640,12 @@ func (v *VRGInstance) fetchAndRestorePV(result *ctrl.Result) error {
 
 	result.Requeue = true
 
+	// Report corrupted data, rather than a later error of another profile, so
+	// that it is not restored from and the corruption is surfaced
+	if corruptedErr != nil {
+		return corruptedErr
+	}
+
 	return err
 }
 
@@ -1632,6 +1695,7 @@ func (v *VRGInstance) restorePVClusterData(pvList []corev1.PersistentVolume) err
 	for idx := range pvList {
 		pv := &pvList[idx]
 		v.cleanupPVForRestore(pv)
+		v.redirectPVForDrill(pv)
 		v.addPVRestoreAnnotation(pv)
 
 		if err := v.reconciler.Create(v.ctx, pv); err != nil {
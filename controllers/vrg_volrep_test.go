@@ -546,23 +546,101 @@ var _ = Describe("VolumeReplicationGroupVolRepController", func() {
 			v.cleanup()
 		})
 	})
+
+	// Creates a VRG that protects a PVC in its namespace and another in a
+	// namespace listed in its protectedNamespaces, and checks that a VR is
+	// created for each, the latter with owner labels instead of an owner
+	Context("in primary state protecting another namespace", func() {
+		var v *vrgTest
+		var otherNamespace, otherPVCName string
+		namespacesTestTemplate := &template{
+			ClaimBindInfo:          corev1.ClaimBound,
+			VolumeBindInfo:         corev1.VolumeBound,
+			schedulingInterval:     "1h",
+			storageClassName:       "manual",
+			replicationClassName:   "test-replicationclass",
+			vrcProvisioner:         "manual.storage.com",
+			scProvisioner:          "manual.storage.com",
+			replicationClassLabels: map[string]string{"protection": "ramen"},
+		}
+		otherVolRepGet := func() (*volrep.VolumeReplication, error) {
+			volRep := &volrep.VolumeReplication{}
+
+			return volRep, k8sClient.Get(context.TODO(),
+				types.NamespacedName{Namespace: otherNamespace, Name: otherPVCName}, volRep)
+		}
+		It("sets up PVCs, PVs and a VRG in two namespaces", func() {
+			namespacesTestTemplate.s3Profiles = []string{s3Profiles[vrgS3ProfileNumber].S3ProfileName}
+			v = newVRGTestCaseCreate(1, namespacesTestTemplate, true, false)
+			otherNamespace = v.namespace + "-other"
+			otherPVCName = fmt.Sprintf("pvc-%v-other", v.uniqueID)
+			otherPVName := fmt.Sprintf("pv-%v-other", v.uniqueID)
+			v.protectedNamespaces = []string{otherNamespace}
+			Expect(k8sClient.Create(context.TODO(),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: otherNamespace}})).To(Succeed())
+			v.createPV(otherPVName, otherPVCName, otherNamespace, namespacesTestTemplate.VolumeBindInfo)
+			v.createPVC(otherPVCName, otherNamespace, otherPVName, v.pvcLabels, namespacesTestTemplate.ClaimBindInfo)
+			v.VRGTestCaseStart()
+		})
+		It("waits for VRG to create a VR for each PVC", func() {
+			v.waitForVRCountToMatch(1)
+			Eventually(func() error {
+				_, err := otherVolRepGet()
+
+				return err
+			}, timeout, interval).Should(Succeed())
+			volRep, err := otherVolRepGet()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(volRep.OwnerReferences).To(BeEmpty())
+			Expect(volRep.Labels).To(HaveKeyWithValue("ramendr.openshift.io/owner-namespace-name", v.namespace))
+		})
+		It("reports the PVC of the other namespace with its namespace", func() {
+			Eventually(func() bool {
+				for _, protectedPVC := range v.getVRG().Status.ProtectedPVCs {
+					if protectedPVC.Name == otherPVCName && protectedPVC.Namespace == otherNamespace {
+						return true
+					}
+				}
+
+				return false
+			}, timeout, interval).Should(BeTrue())
+		})
+		It("cleans up after testing", func() {
+			v.cleanupVRG()
+			Eventually(func() bool {
+				_, err := otherVolRepGet()
+
+				return errors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+			v.cleanupPVCs()
+			Expect(k8sClient.Delete(context.TODO(), &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: otherNamespace, Name: otherPVCName},
+			})).To(Succeed())
+			v.cleanupNamespace()
+			Expect(k8sClient.Delete(context.TODO(),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: otherNamespace}})).To(Succeed())
+			v.cleanupSC()
+			v.cleanupVRC()
+		})
+	})
 	// TODO: Add tests to move VRG to Secondary
 	// TODO: Add tests to ensure delete as Secondary (check if delete as Primary is tested above)
 })
 
 type vrgTest struct {
-	uniqueID         string
-	namespace        string
-	pvNames          []string
-	pvcNames         []string
-	vrgName          string
-	storageClass     string
-	replicationClass string
-	pvcLabels        map[string]string
-	pvcCount         int
-	checkBind        bool
-	vrgFirst         bool
-	template         *template
+	uniqueID            string
+	namespace           string
+	protectedNamespaces []string
+	pvNames             []string
+	pvcNames            []string
+	vrgName             string
+	storageClass        string
+	replicationClass    string
+	pvcLabels           map[string]string
+	pvcCount            int
+	checkBind           bool
+	vrgFirst            bool
+	template            *template
 }
 
 type template struct {
@@ -665,7 +743,7 @@ func (v *vrgTest) createPVCandPV(claimBindInfo corev1.PersistentVolumeClaimPhase
 		// race, create PV first and then PVC. Until PVC is created and bound,
 		// VRG will not be able to reach PV. And by the time VRG reconciler
 		// reaches PV, it is already bound by this unit test.
-		v.createPV(pvName, pvcName, volumeBindInfo)
+		v.createPV(pvName, pvcName, v.namespace, volumeBindInfo)
 		v.createPVC(pvcName, v.namespace, pvName, v.pvcLabels, claimBindInfo)
 		v.pvNames = append(v.pvNames, pvName)
 		v.pvcNames = append(v.pvcNames, pvcName)
@@ -767,7 +845,7 @@ func (v *vrgTest) createNamespace() {
 		"failed to create namespace %s", v.namespace)
 }
 
-func (v *vrgTest) createPV(pvName, claimName string, bindInfo corev1.PersistentVolumePhase) {
+func (v *vrgTest) createPV(pvName, claimName, claimNamespace string, bindInfo corev1.PersistentVolumePhase) {
 	By("creating PV " + pvName)
 
 	capacity := corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}
@@ -786,7 +864,7 @@ func (v *vrgTest) createPV(pvName, claimName string, bindInfo corev1.PersistentV
 			AccessModes: accessModes,
 			ClaimRef: &corev1.ObjectReference{
 				Kind:      "PersistentVolumeClaim",
-				Namespace: v.namespace,
+				Namespace: claimNamespace,
 				Name:      claimName,
 				// UID:       types.UID(claimName),
 			},
@@ -906,8 +984,9 @@ func (v *vrgTest) createVRG() {
 			Namespace: v.namespace,
 		},
 		Spec: ramendrv1alpha1.VolumeReplicationGroupSpec{
-			PVCSelector:      metav1.LabelSelector{MatchLabels: v.pvcLabels},
-			ReplicationState: "primary",
+			PVCSelector:         metav1.LabelSelector{MatchLabels: v.pvcLabels},
+			ProtectedNamespaces: v.protectedNamespaces,
+			ReplicationState:    "primary",
 			Async: ramendrv1alpha1.VRGAsyncSpec{
 				Mode:                     ramendrv1alpha1.AsyncModeEnabled,
 				SchedulingInterval:       schedulingInterval,
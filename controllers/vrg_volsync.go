@@ -40,7 +40,7 @@ func (v *VRGInstance) restorePVsForVolSync() error {
 		if err != nil {
 			v.log.Info(fmt.Sprintf("Unable to ensure PVC %v -- err: %v", rdSpec, err))
 
-			protectedPVC := v.findProtectedPVC(rdSpec.ProtectedPVC.Name)
+			protectedPVC := v.findProtectedPVC(v.instance.Namespace, rdSpec.ProtectedPVC.Name)
 			if protectedPVC == nil {
 				protectedPVC = &ramendrv1alpha1.ProtectedPVC{}
 				rdSpec.ProtectedPVC.DeepCopyInto(protectedPVC)
@@ -55,7 +55,7 @@ func (v *VRGInstance) restorePVsForVolSync() error {
 
 		numPVsRestored++
 
-		protectedPVC := v.findProtectedPVC(rdSpec.ProtectedPVC.Name)
+		protectedPVC := v.findProtectedPVC(v.instance.Namespace, rdSpec.ProtectedPVC.Name)
 		if protectedPVC == nil {
 			protectedPVC = &ramendrv1alpha1.ProtectedPVC{}
 			rdSpec.ProtectedPVC.DeepCopyInto(protectedPVC)
@@ -81,6 +81,14 @@ func (v *VRGInstance) reconcileVolSyncAsPrimary() (requeue bool) {
 
 	requeue = false
 
+	if err := v.updateVolSyncSecretRotatedAt(); err != nil {
+		v.log.Error(err, "Failed to report when the VolSync secret keys were generated")
+
+		requeue = true
+
+		return
+	}
+
 	// Cleanup - this VRG is primary, cleanup if necessary
 	// remove any ReplicationDestinations (that would have been created when this VRG was secondary) if they
 	// are not in the RDSpec list
@@ -105,7 +113,7 @@ func (v *VRGInstance) reconcileVolSyncAsPrimary() (requeue bool) {
 			Resources:          pvc.Spec.Resources,
 		}
 
-		protectedPVC := v.findProtectedPVC(pvc.Name)
+		protectedPVC := v.findProtectedPVC(pvc.Namespace, pvc.Name)
 		if protectedPVC == nil {
 			protectedPVC = newProtectedPVC
 			v.instance.Status.ProtectedPVCs = append(v.instance.Status.ProtectedPVCs, *protectedPVC)
@@ -144,6 +152,10 @@ func (v *VRGInstance) reconcileVolSyncAsPrimary() (requeue bool) {
 			requeue = true
 		} else {
 			setVRGConditionTypeVolSyncRepSourceSetupComplete(&protectedPVC.Conditions, v.instance.Generation, "Ready")
+
+			if rs.Status != nil {
+				protectedPVC.LastSyncTime = rs.Status.LastSyncTime
+			}
 		}
 
 		if v.instance.Spec.RunFinalSync && !finalSyncComplete {
@@ -175,6 +187,14 @@ func (v *VRGInstance) reconcileVolSyncAsSecondary() (requeue bool) {
 
 	requeue = false
 
+	if err := v.updateVolSyncSecretRotatedAt(); err != nil {
+		v.log.Error(err, "Failed to report when the VolSync secret keys were generated")
+
+		requeue = true
+
+		return
+	}
+
 	// If we are secondary, and RDSpec is not set, then we don't want to have any PVC
 	// flagged as a VolSync PVC.
 	if v.instance.Spec.VolSync.RDSpec == nil {
@@ -225,6 +245,20 @@ func (v *VRGInstance) reconcileVolSyncAsSecondary() (requeue bool) {
 	return requeue
 }
 
+// updateVolSyncSecretRotatedAt reports when the keys of the VolSync secret on
+// the cluster were generated, for the hub to resume replication, paused while
+// the clusters have different keys, once both have them
+func (v *VRGInstance) updateVolSyncSecretRotatedAt() error {
+	rotatedAt, err := v.volSyncHandler.GetSecretRotatedAt()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	v.instance.Status.VolSyncSecretRotatedAt = rotatedAt
+
+	return nil
+}
+
 func (v *VRGInstance) aggregateVolSyncDataReadyCondition() *v1.Condition {
 	dataReadyCondition := &v1.Condition{
 		Type:               VRGConditionTypeDataReady,
@@ -182,6 +182,50 @@ var _ = Describe("VolumeReplicationGroupVolSyncController", func() {
 						Expect(rs2.Spec.Trigger).NotTo(BeNil())
 						Expect(*rs2.Spec.Trigger.Schedule).To(Equal("0 */1 * * *")) // scheduling interval was set to 1h
 					})
+
+					It("Should pause ReplicationSources while the secret has keys other than both clusters'", func() {
+						const rotatedAt = "2022-06-01T12:00:00Z"
+
+						rsPaused := func() bool {
+							rs := &volsyncv1alpha1.ReplicationSource{}
+							Expect(k8sClient.Get(testCtx, types.NamespacedName{
+								Name: boundPvcs[0].GetName(), Namespace: testNamespace.GetName(),
+							}, rs)).To(Succeed())
+
+							return rs.Spec.Paused
+						}
+
+						Eventually(rsPaused, testMaxWait, testInterval).Should(BeFalse())
+
+						By("reporting the keys of the secret on the cluster, pausing replication")
+						secret := &corev1.Secret{}
+						Expect(k8sClient.Get(testCtx, types.NamespacedName{
+							Name:      volsync.GetVolSyncSSHSecretNameFromVRGName(testVsrg.GetName()),
+							Namespace: testNamespace.GetName(),
+						}, secret)).To(Succeed())
+						secret.Annotations = map[string]string{volsync.SecretRotatedAtAnnotation: rotatedAt}
+						Expect(k8sClient.Update(testCtx, secret)).To(Succeed())
+
+						Eventually(func() string {
+							Expect(k8sClient.Get(testCtx, client.ObjectKeyFromObject(testVsrg), testVsrg)).To(Succeed())
+
+							return testVsrg.Status.VolSyncSecretRotatedAt
+						}, testMaxWait, testInterval).Should(Equal(rotatedAt))
+						Eventually(rsPaused, testMaxWait, testInterval).Should(BeTrue())
+
+						By("resuming replication once both clusters have the keys")
+						Eventually(func() error {
+							err := k8sClient.Get(testCtx, client.ObjectKeyFromObject(testVsrg), testVsrg)
+							if err != nil {
+								return err
+							}
+
+							testVsrg.Spec.VolSync.SecretRotatedAt = rotatedAt
+
+							return k8sClient.Update(testCtx, testVsrg)
+						}, testMaxWait, testInterval).Should(Succeed())
+						Eventually(rsPaused, testMaxWait, testInterval).Should(BeFalse())
+					})
 				})
 			})
 		})
@@ -25,6 +25,7 @@ import (
 	volsyncv1alpha1 "github.com/backube/volsync/api/v1alpha1"
 	volrep "github.com/csi-addons/volume-replication-operator/api/v1alpha1"
 	snapv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	ocmclv1 "github.com/open-cluster-management/api/cluster/v1"
 	ocmworkv1 "github.com/open-cluster-management/api/work/v1"
 	cpcv1 "github.com/stolostron/config-policy-controller/api/v1"
 	gppv1 "github.com/stolostron/governance-policy-propagator/api/v1"
@@ -96,6 +97,7 @@ func newManager() (ctrl.Manager, *ramendrv1alpha1.RamenConfig, error) {
 	if controllers.ControllerType == ramendrv1alpha1.DRHubType {
 		utilruntime.Must(plrv1.AddToScheme(scheme))
 		utilruntime.Must(ocmworkv1.AddToScheme(scheme))
+		utilruntime.Must(ocmclv1.AddToScheme(scheme))
 		utilruntime.Must(viewv1beta1.AddToScheme(scheme))
 		utilruntime.Must(cpcv1.AddToScheme(scheme))
 		utilruntime.Must(gppv1.AddToScheme(scheme))
@@ -134,6 +136,24 @@ func setupReconcilers(mgr ctrl.Manager, ramenConfig *ramendrv1alpha1.RamenConfig
 		os.Exit(1)
 	}
 
+	if err := (&controllers.StorageFencingReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("StorageFencing"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StorageFencing")
+		os.Exit(1)
+	}
+
+	if err := (&controllers.NodeFenceReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("NodeFence"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NodeFence")
+		os.Exit(1)
+	}
+
 	// Index fields that are required for VSHandler
 	if err := volsync.IndexFieldsForVSHandler(context.Background(), mgr.GetFieldIndexer()); err != nil {
 		setupLog.Error(err, "unable to index fields for controller", "controller", "VolumeReplicationGroup")
@@ -158,6 +178,7 @@ func setupReconcilersHub(mgr ctrl.Manager) {
 		APIReader:         mgr.GetAPIReader(),
 		Scheme:            mgr.GetScheme(),
 		ObjectStoreGetter: controllers.S3ObjectStoreGetter(),
+		MCVGetter:         rmnutil.ManagedClusterViewGetterImpl{Client: mgr.GetClient()},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DRPolicy")
 		os.Exit(1)
@@ -175,16 +196,31 @@ func setupReconcilersHub(mgr ctrl.Manager) {
 	}
 
 	if err := (&controllers.DRPlacementControlReconciler{
-		Client:    mgr.GetClient(),
-		APIReader: mgr.GetAPIReader(),
-		Log:       ctrl.Log.WithName("controllers").WithName("DRPlacementControl"),
-		MCVGetter: rmnutil.ManagedClusterViewGetterImpl{Client: mgr.GetClient()},
-		Scheme:    mgr.GetScheme(),
-		Callback:  func(string, string) {},
+		Client:         mgr.GetClient(),
+		APIReader:      mgr.GetAPIReader(),
+		Log:            ctrl.Log.WithName("controllers").WithName("DRPlacementControl"),
+		MCVGetter:      rmnutil.ManagedClusterViewGetterImpl{Client: mgr.GetClient()},
+		ObjStoreGetter: controllers.S3ObjectStoreGetter(),
+		Scheme:         mgr.GetScheme(),
+		Callback:       func(string, string) {},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DRPlacementControl")
 		os.Exit(1)
 	}
+
+	if err := (&controllers.DRFailoverPlanReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DRFailoverPlan")
+		os.Exit(1)
+	}
+
+	if err := mgr.Add(controllers.HubBackupRunnable(mgr.GetClient(), mgr.GetAPIReader(),
+		controllers.S3ObjectStoreGetter(), ctrl.Log.WithName("HubBackup"))); err != nil {
+		setupLog.Error(err, "unable to add runnable", "runnable", "HubBackup")
+		os.Exit(1)
+	}
 }
 
 func main() {